@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportServer     bool
+	exportStrictName bool
+	importForce      bool
+)
+
+var exportConfigCmd = &cobra.Command{
+	Use:   "export-config <simulation-name> <bundle.tar.gz>",
+	Short: "Package a simulation definition into a shareable bundle",
+	Long: `Write the simulation and metrics configs for a simulation into a gzipped
+tarball that a teammate can load with 'autobox import-config'.
+
+Use --server to include the server config the simulation runs with as well:
+~/.autobox/config/server/<name>.json if it exists, or else
+~/.autobox/config/server.json.
+
+Examples:
+  autobox export-config gift_choice gift_choice.tar.gz
+  autobox export-config gift_choice gift_choice.tar.gz --server`,
+	Args: cobra.ExactArgs(2),
+	RunE: runExportConfig,
+}
+
+var importConfigCmd = &cobra.Command{
+	Use:   "import-config <bundle.tar.gz>",
+	Short: "Unpack a simulation bundle into ~/.autobox/config",
+	Long: `Unpack a bundle written by 'autobox export-config' into ~/.autobox/config.
+
+The bundle is checked before anything is written: it must hold one simulation
+config and its metrics config (and optionally server.json or the matching
+server/<name>.json), all valid JSON.
+Existing files are left alone unless --force is given.
+
+Examples:
+  autobox import-config gift_choice.tar.gz
+  autobox import-config gift_choice.tar.gz --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportConfig,
+}
+
+func init() {
+	exportConfigCmd.Flags().BoolVar(&exportServer, "server", false, "Include the simulation's server config in the bundle")
+	exportConfigCmd.Flags().BoolVar(&exportStrictName, "strict-name", false, "Use the simulation name as the file name exactly (config: simulation.strict_names)")
+	importConfigCmd.Flags().BoolVarP(&importForce, "force", "f", false, "Overwrite existing config files")
+}
+
+func runExportConfig(cmd *cobra.Command, args []string) error {
+	if exportStrictName {
+		config.Set("simulation.strict_names", true)
+	}
+	simulationName, bundlePath := args[0], args[1]
+
+	// Check before creating the bundle so a typo doesn't clobber an existing file.
+	if err := config.ValidateSimulationConfig(simulationName); err != nil {
+		return err
+	}
+
+	f, err := os.Create(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	if err := config.ExportBundle(simulationName, f, exportServer); err != nil {
+		f.Close()
+		os.Remove(bundlePath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Printf("%s Exported %s to %s\n", color.GreenString("✓"), simulationName, bundlePath)
+	return nil
+}
+
+func runImportConfig(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer f.Close()
+
+	written, err := config.ImportBundle(f, importForce)
+	for _, path := range written {
+		fmt.Printf("%s Wrote %s\n", color.GreenString("✓"), path)
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
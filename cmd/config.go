@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var configInitForce bool
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Set up and check the ~/.autobox config directory",
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the config directories and starter files",
+	Long: `Create ~/.autobox/config/simulations, ~/.autobox/config/metrics and
+~/.autobox/logs, and write starter files to edit:
+
+  ~/.autobox/autobox.yaml                       CLI settings, with comments
+  ~/.autobox/config/simulations/example.json    an example simulation
+  ~/.autobox/config/metrics/example.json        its metrics config
+
+Files that already exist are kept, and checked so a broken one is pointed out;
+--force replaces them with the starter files instead.
+
+Examples:
+  autobox config init
+  autobox run example
+
+  # Start over from the starter files
+  autobox config init --force`,
+	Args: cobra.NoArgs,
+	RunE: runConfigInit,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [simulation-name]",
+	Short: "Check simulation configs before running them",
+	Long: `Check simulation configs the way run does before launching: the simulation
+and metrics files must both exist and parse, and the simulation must pass the
+schema check (required fields, field types and misspelled keys).
+
+Without a name every simulation in ~/.autobox/config/ is checked and a table
+of results is printed. With a name only that simulation is checked, and each
+problem is listed. The command exits non-zero if any config is invalid, so it
+can gate CI.
+
+Examples:
+  autobox config validate
+  autobox config validate gift_choice`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigValidate,
+}
+
+func init() {
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false, "Overwrite existing files with the starter files")
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	results, err := config.Scaffold(configInitForce)
+	printScaffoldResults(results)
+	if err != nil {
+		return fmt.Errorf("failed to initialize the config directory: %w", err)
+	}
+
+	fmt.Printf("%s Config directory ready; try 'autobox run example'\n", color.GreenString("✓"))
+	return nil
+}
+
+func printScaffoldResults(results []config.ScaffoldResult) {
+	for _, result := range results {
+		switch {
+		case result.Status == config.ScaffoldCreated:
+			fmt.Printf("%s Created %s\n", color.GreenString("✓"), result.Path)
+		case result.Status == config.ScaffoldOverwritten:
+			fmt.Printf("%s Overwrote %s\n", color.YellowString("→"), result.Path)
+		case result.Invalid != nil:
+			fmt.Printf("%s Kept %s, but it is not valid: %v (fix it, or use --force to replace it)\n", color.YellowString("⚠"), result.Path, result.Invalid)
+		default:
+			fmt.Printf("%s Kept %s (already exists)\n", color.YellowString("→"), result.Path)
+		}
+	}
+}
+
+// simulationCheck is the result of validating one simulation; Err is nil if
+// it is valid.
+type simulationCheck struct {
+	Name string
+	Err  error
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		return validateOneSimulation(os.Stdout, args[0])
+	}
+
+	names, err := config.ListAvailableSimulations()
+	if err != nil {
+		return fmt.Errorf("failed to list simulations: %w", err)
+	}
+	if len(names) == 0 {
+		fmt.Println("No simulations found in ~/.autobox/config/ (create one with 'autobox config init')")
+		return nil
+	}
+
+	// The names are file names already, so they mustn't be normalized again.
+	config.Set("simulation.strict_names", true)
+	checks := checkSimulations(names)
+	printSimulationChecks(os.Stdout, checks)
+	return invalidSimulationsError(checks)
+}
+
+func validateOneSimulation(w io.Writer, name string) error {
+	if err := validateSimulation(name); err != nil {
+		fmt.Fprintf(w, "%s %s is not valid:\n", color.RedString("✗"), name)
+		for _, problem := range validationProblems(err) {
+			fmt.Fprintf(w, "  - %s\n", problem)
+		}
+		return fmt.Errorf("simulation %s is invalid", name)
+	}
+	fmt.Fprintf(w, "%s %s is valid\n", color.GreenString("✓"), name)
+	return nil
+}
+
+// validateSimulation runs the checks run makes before a launch, then loads
+// the configs so that a metrics or server file that isn't JSON is caught too.
+func validateSimulation(name string) error {
+	if err := config.ValidateSimulationConfig(name); err != nil {
+		return err
+	}
+	_, err := config.LoadSimulationConfig(name)
+	return err
+}
+
+func checkSimulations(names []string) []simulationCheck {
+	checks := make([]simulationCheck, 0, len(names))
+	for _, name := range names {
+		checks = append(checks, simulationCheck{Name: name, Err: validateSimulation(name)})
+	}
+	return checks
+}
+
+// validationProblems splits a validation error into its separate problems:
+// one per schema problem, or the error itself.
+func validationProblems(err error) []string {
+	var schemaErr *config.SchemaError
+	if errors.As(err, &schemaErr) {
+		return schemaErr.Problems
+	}
+	return []string{err.Error()}
+}
+
+func printSimulationChecks(w io.Writer, checks []simulationCheck) {
+	fmt.Fprintf(w, "%-30s  %s\n", "NAME", "RESULT")
+	fmt.Fprintln(w, strings.Repeat("-", 70))
+	for _, check := range checks {
+		result := color.GreenString("OK")
+		if check.Err != nil {
+			result = color.RedString("%s", strings.Join(validationProblems(check.Err), "; "))
+		}
+		fmt.Fprintf(w, "%-30s  %s\n", truncate(check.Name, 30), result)
+	}
+}
+
+func invalidSimulationsError(checks []simulationCheck) error {
+	invalid := 0
+	for _, check := range checks {
+		if check.Err != nil {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d simulation(s) are invalid", invalid, len(checks))
+	}
+	return nil
+}
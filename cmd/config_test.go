@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// writeSimulationFiles writes simulation and metrics configs under a
+// temporary HOME, keyed by simulation name.
+func writeSimulationFiles(t *testing.T, simulations, metrics map[string]string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for dir, files := range map[string]map[string]string{"simulations": simulations, "metrics": metrics} {
+		base := filepath.Join(home, ".autobox", "config", dir)
+		if err := os.MkdirAll(base, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(base, name+".json"), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestCheckSimulations(t *testing.T) {
+	oldNoColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = oldNoColor }()
+
+	writeSimulationFiles(t,
+		map[string]string{
+			"good":        `{"name": "good", "agents": [{"name": "a"}], "duration": 60}`,
+			"schema":      `{"agents": "none", "durration": 60}`,
+			"bad_json":    `{"name": `,
+			"bad_metrics": `{"name": "bad_metrics", "agents": []}`,
+		},
+		map[string]string{
+			"good":        `{}`,
+			"schema":      `{}`,
+			"bad_json":    `{}`,
+			"bad_metrics": `not json`,
+		})
+
+	checks := checkSimulations([]string{"good", "schema", "bad_json", "bad_metrics"})
+	valid := map[string]bool{}
+	for _, check := range checks {
+		valid[check.Name] = check.Err == nil
+	}
+	want := map[string]bool{"good": true, "schema": false, "bad_json": false, "bad_metrics": false}
+	for name, ok := range want {
+		if valid[name] != ok {
+			t.Errorf("%s: valid = %v, want %v", name, valid[name], ok)
+		}
+	}
+
+	var out bytes.Buffer
+	printSimulationChecks(&out, checks)
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("table has %d lines, want a header, a rule and 4 rows:\n%s", len(lines), out.String())
+	}
+	if !strings.HasPrefix(lines[2], "good") || !strings.HasSuffix(lines[2], "OK") {
+		t.Errorf("good row = %q", lines[2])
+	}
+	for _, problem := range []string{`"name" is required`, `"agents" must be an array`, `unknown key "durration"`} {
+		if !strings.Contains(lines[3], problem) {
+			t.Errorf("schema row %q doesn't report %s", lines[3], problem)
+		}
+	}
+	if !strings.Contains(lines[4], "failed to parse simulation config") {
+		t.Errorf("bad_json row = %q", lines[4])
+	}
+	if !strings.Contains(lines[5], "failed to parse metrics config") {
+		t.Errorf("bad_metrics row = %q", lines[5])
+	}
+
+	err := invalidSimulationsError(checks)
+	if err == nil || err.Error() != "3 of 4 simulation(s) are invalid" {
+		t.Errorf("invalidSimulationsError() = %v", err)
+	}
+	if err := invalidSimulationsError(checks[:1]); err != nil {
+		t.Errorf("invalidSimulationsError() with only valid configs = %v", err)
+	}
+}
+
+func TestValidateOneSimulation(t *testing.T) {
+	oldNoColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = oldNoColor }()
+
+	writeSimulationFiles(t,
+		map[string]string{"good": `{"name": "good", "agents": []}`, "schema": `{"duration": -1}`},
+		map[string]string{"good": `{}`, "schema": `{}`})
+
+	var out bytes.Buffer
+	if err := validateOneSimulation(&out, "good"); err != nil {
+		t.Errorf("validateOneSimulation(good) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "good is valid") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := validateOneSimulation(&out, "schema"); err == nil {
+		t.Error("validateOneSimulation(schema) succeeded, want an error")
+	}
+	want := "✗ schema is not valid:\n" +
+		"  - \"name\" is required\n" +
+		"  - \"agents\" is required\n" +
+		"  - \"duration\" must be positive\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
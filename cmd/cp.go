@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var cpCmd = &cobra.Command{
+	Use:   "cp SRC DST",
+	Short: "Copy files between a simulation and the host",
+	Long: `Copy a file or directory into or out of a simulation's container, like
+docker cp. Exactly one of SRC and DST is SIMULATION:PATH, where SIMULATION is
+an ID, ID prefix or name; the other is a host path.
+
+A directory is copied with everything in it, and file modes are kept. If the
+destination is an existing directory the copy goes inside it; otherwise it
+takes the destination's name. Stopped simulations can be copied from too.
+
+To name a host file containing a colon, start it with ./ or give an absolute
+path.
+
+Examples:
+  autobox cp gift_choice:/app/logs ./logs
+  autobox cp abc123def456:/app/logs/results.json .
+  autobox cp ./agents.json abc123def456:/app/config/`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCp,
+}
+
+// copyPath is one side of a cp: a host path, or a path inside the
+// simulation named by Ref.
+type copyPath struct {
+	Ref  string
+	Path string
+}
+
+func (p copyPath) inSimulation() bool {
+	return p.Ref != ""
+}
+
+// parseCopyPath splits SIMULATION:PATH, treating anything else as a host
+// path. As with docker cp, a colon after a path separator, or in a path that
+// starts with . or is absolute, is part of a host file name.
+func parseCopyPath(arg string) copyPath {
+	if filepath.IsAbs(arg) || strings.HasPrefix(arg, ".") {
+		return copyPath{Path: arg}
+	}
+	ref, p, ok := strings.Cut(arg, ":")
+	if !ok || ref == "" || strings.ContainsAny(ref, `/\`) {
+		return copyPath{Path: arg}
+	}
+	return copyPath{Ref: ref, Path: p}
+}
+
+// parseCopyArgs checks that exactly one side of a cp is in a simulation.
+func parseCopyArgs(src, dst string) (copyPath, copyPath, error) {
+	from, to := parseCopyPath(src), parseCopyPath(dst)
+	switch {
+	case from.inSimulation() && to.inSimulation():
+		return from, to, fmt.Errorf("copying between two simulations is not supported; copy to the host first")
+	case !from.inSimulation() && !to.inSimulation():
+		return from, to, fmt.Errorf("one of SRC and DST must be SIMULATION:PATH")
+	}
+	for _, p := range []copyPath{from, to} {
+		if p.inSimulation() && p.Path == "" {
+			return from, to, fmt.Errorf("missing path after %s:", p.Ref)
+		}
+	}
+	return from, to, nil
+}
+
+func runCp(cmd *cobra.Command, args []string) error {
+	from, to, err := parseCopyArgs(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	if from.inSimulation() {
+		simulationID, err := resolveSimulationID(ctx, client, from.Ref)
+		if err != nil {
+			return err
+		}
+		if err := client.CopyFromSimulation(ctx, simulationID, from.Path, to.Path); err != nil {
+			return err
+		}
+	} else {
+		simulationID, err := resolveSimulationID(ctx, client, to.Ref)
+		if err != nil {
+			return err
+		}
+		if err := client.CopyToSimulation(ctx, simulationID, from.Path, to.Path); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%s Copied %s to %s\n", color.GreenString("✓"), args[0], args[1])
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the CLI can run simulations",
+	Long: `Check the local setup: Docker connectivity and socket permissions, the
+engine image, and the ~/.autobox config layout. Exits non-zero if any check
+fails.
+
+Examples:
+  autobox doctor`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+// doctorCheck is the outcome of one check. Warnings are reported but don't
+// fail the command.
+type doctorCheck struct {
+	name    string
+	ok      bool
+	warning bool
+	detail  string
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	fmt.Printf("\n%s Autobox Doctor\n", color.CyanString("▶"))
+	fmt.Println(strings.Repeat("─", 50))
+
+	var checks []doctorCheck
+
+	// The API version is reported as a check below rather than as a warning.
+	docker.WarningHandler = nil
+	client, err := docker.NewClient()
+	switch {
+	case errors.Is(err, docker.ErrPermissionDenied):
+		checks = append(checks, doctorCheck{name: "Docker socket", detail: err.Error()})
+	case err != nil:
+		checks = append(checks, doctorCheck{name: "Docker daemon", detail: err.Error()})
+	default:
+		defer client.Close()
+		checks = append(checks, doctorCheck{name: "Docker daemon", ok: true, detail: "reachable at " + client.DaemonHost()})
+		checks = append(checks, checkAPIVersion(client))
+		checks = append(checks, checkEngineImage(ctx, client))
+	}
+
+	checks = append(checks, checkConfigDirectories()...)
+	checks = append(checks, checkLayout())
+
+	failed := 0
+	for _, check := range checks {
+		var mark string
+		switch {
+		case check.ok:
+			mark = color.GreenString("✓")
+		case check.warning:
+			mark = color.YellowString("⚠")
+		default:
+			mark = color.RedString("✗")
+			failed++
+		}
+		fmt.Printf("%s %-20s %s\n", mark, check.name, check.detail)
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func checkAPIVersion(client *docker.Client) doctorCheck {
+	version := client.APIVersion()
+	if warning := docker.APIVersionWarning(version, config.GetString("docker.api_version")); warning != "" {
+		return doctorCheck{name: "Docker API", warning: true, detail: warning}
+	}
+	return doctorCheck{name: "Docker API", ok: true, detail: version + " (negotiated)"}
+}
+
+func checkEngineImage(ctx context.Context, client *docker.Client) doctorCheck {
+	image := config.GetString("docker.image")
+	if image == "" {
+		image = "autobox-engine:latest"
+	}
+
+	exists, err := client.ImageExists(ctx, image)
+	switch {
+	case err != nil:
+		return doctorCheck{name: "Engine image", detail: err.Error()}
+	case !exists:
+		return doctorCheck{name: "Engine image", warning: true, detail: image + " not found locally; run will try to pull it"}
+	default:
+		return doctorCheck{name: "Engine image", ok: true, detail: image}
+	}
+}
+
+func checkConfigDirectories() []doctorCheck {
+	paths, err := config.Paths()
+	if err != nil {
+		return []doctorCheck{{name: "Config directory", detail: err.Error()}}
+	}
+
+	var checks []doctorCheck
+	for _, sub := range []struct{ name, dir string }{{"simulations", paths.Simulations}, {"metrics", paths.Metrics}} {
+		dir := sub.dir
+		name := "Config " + sub.name
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			checks = append(checks, doctorCheck{name: name, warning: true, detail: dir + " missing; created on first run"})
+			continue
+		}
+		checks = append(checks, doctorCheck{name: name, ok: true, detail: dir})
+	}
+	return checks
+}
+
+func checkLayout() doctorCheck {
+	if warning := config.CheckLayoutVersion(); warning != "" {
+		return doctorCheck{name: "Config layout", warning: true, detail: warning}
+	}
+	return doctorCheck{name: "Config layout", ok: true, detail: fmt.Sprintf("schema version %d", config.SchemaVersion)}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationSegment = regexp.MustCompile(`^(\d+(?:\.\d*)?|\.\d+)([a-zµμ]+)`)
+
+var extendedUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// parseExtendedDuration is time.ParseDuration plus "d" (24h) and "w" (7d)
+// units, which may be mixed with Go's own: "2w", "1d12h", "1.5d".
+func parseExtendedDuration(s string) (time.Duration, error) {
+	orig := s
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+
+	var total time.Duration
+	for s != "" {
+		m := durationSegment.FindStringSubmatch(s)
+		if m == nil {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+
+		value, unit := m[1], m[2]
+		if scale, ok := extendedUnits[unit]; ok {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			total += time.Duration(n * float64(scale))
+		} else {
+			d, err := time.ParseDuration(value + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			total += d
+		}
+		s = s[len(m[0]):]
+	}
+
+	if negative {
+		total = -total
+	}
+	return total, nil
+}
+
+// hasExtendedUnit reports whether s uses a unit time.ParseDuration doesn't
+// know, such as "d" in "1d12h".
+func hasExtendedUnit(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	for s != "" {
+		m := durationSegment.FindStringSubmatch(s)
+		if m == nil {
+			return false
+		}
+		if _, ok := extendedUnits[m[2]]; ok {
+			return true
+		}
+		s = s[len(m[0]):]
+	}
+	return false
+}
+
+// extendedDurationValue is a pflag.Value parsed with parseExtendedDuration,
+// so duration flags accept "1d" and "2w" as well as Go durations.
+type extendedDurationValue time.Duration
+
+func newExtendedDuration(value time.Duration, p *time.Duration) *extendedDurationValue {
+	*p = value
+	return (*extendedDurationValue)(p)
+}
+
+func (d *extendedDurationValue) Set(s string) error {
+	v, err := parseExtendedDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = extendedDurationValue(v)
+	return nil
+}
+
+func (d *extendedDurationValue) String() string {
+	// "0" rather than "0s" lets pflag recognize an unset default and leave
+	// it out of the help text.
+	if *d == 0 {
+		return "0"
+	}
+	return time.Duration(*d).String()
+}
+
+func (d *extendedDurationValue) Type() string {
+	return "duration"
+}
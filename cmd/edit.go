@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var editStrictName bool
+
+var editCmd = &cobra.Command{
+	Use:   "edit <simulation-name>",
+	Short: "Edit a simulation config in your editor",
+	Long: `Open the simulation config from ~/.autobox/config/simulations/ in $VISUAL or
+$EDITOR (vi, or notepad on Windows, if neither is set).
+
+The edit is made on a temporary copy and only saved once it is valid JSON that
+passes the simulation config checks. If it doesn't, the error is shown and you
+can reopen the editor to fix it or discard the edit.
+
+Examples:
+  autobox edit gift_choice
+  EDITOR="code --wait" autobox edit gift_choice`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEdit,
+}
+
+func init() {
+	editCmd.Flags().BoolVar(&editStrictName, "strict-name", false, "Use the simulation name as the file name exactly (config: simulation.strict_names)")
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	if editStrictName {
+		config.Set("simulation.strict_names", true)
+	}
+
+	path, err := config.SimulationConfigPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("simulation config not found: %s", filepath.Base(path))
+		}
+		return fmt.Errorf("failed to read simulation config: %w", err)
+	}
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read simulation config: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "autobox-edit-*-"+filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(original); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	for {
+		if err := runEditor(tmp.Name()); err != nil {
+			return err
+		}
+
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read edited file: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			fmt.Println("No changes")
+			return nil
+		}
+
+		if err := config.ValidateSimulationDocument(edited); err != nil {
+			fmt.Printf("%s %s is invalid: %v\n", color.RedString("✗"), filepath.Base(path), err)
+			fmt.Print("Reopen the editor to fix it? [Y/n]: ")
+			var response string
+			fmt.Scanln(&response)
+			if response == "n" || response == "N" {
+				return fmt.Errorf("edit discarded, %s is unchanged", path)
+			}
+			continue
+		}
+
+		if err := os.WriteFile(path, edited, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to save simulation config: %w", err)
+		}
+		fmt.Printf("%s Saved %s\n", color.GreenString("✓"), path)
+		return nil
+	}
+}
+
+// runEditor opens path in the user's editor and waits for it to exit. The
+// editor variable may include arguments, such as "code --wait".
+func runEditor(path string) error {
+	editor := editorCommand()
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return fmt.Errorf("no editor configured: set $EDITOR")
+	}
+
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
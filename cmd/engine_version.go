@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/fatih/color"
+)
+
+// The engine versions this CLI is known to work with: at least
+// minEngineVersion and below maxEngineVersion.
+const (
+	minEngineVersion = "0.1.0"
+	maxEngineVersion = "1.0.0"
+)
+
+// checkEngineVersion detects the engine version in image and warns when it is
+// outside the supported range. It never blocks the launch: an unknown version
+// only produces a warning, and the detected version (possibly empty) is
+// returned for recording on the container.
+func checkEngineVersion(ctx context.Context, client *docker.Client, image string) string {
+	version, err := client.EngineVersion(ctx, image)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not determine engine version of %s: %v\n",
+			color.YellowString("⚠"), image, err)
+		return ""
+	}
+
+	if !engineVersionSupported(version) {
+		fmt.Fprintf(os.Stderr, "%s Engine version %s is outside the range this CLI supports (>= %s, < %s); use --skip-version-check to silence this\n",
+			color.YellowString("⚠"), version, minEngineVersion, maxEngineVersion)
+	}
+	return version
+}
+
+func engineVersionSupported(version string) bool {
+	return compareVersions(version, minEngineVersion) >= 0 && compareVersions(version, maxEngineVersion) < 0
+}
+
+// compareVersions compares two MAJOR.MINOR.PATCH[-PRERELEASE] versions,
+// returning -1, 0 or 1. A pre-release sorts before its release, and
+// pre-release identifiers are compared as plain strings.
+func compareVersions(a, b string) int {
+	aCore, aPre, _ := strings.Cut(a, "-")
+	bCore, bPre, _ := strings.Cut(b, "-")
+
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := 0; i < 3; i++ {
+		x, y := versionPart(aParts, i), versionPart(bParts, i)
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func versionPart(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[i])
+	return n
+}
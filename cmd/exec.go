@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/moby/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	execInteractive bool
+	execTTY         bool
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec SIMULATION_ID|NAME -- COMMAND [ARG...]",
+	Short: "Run a command inside a running simulation",
+	Long: `Run a command inside a running simulation's container, for debugging.
+
+Everything after -- is the command, so its own flags aren't read by autobox.
+Without -i the command gets no input; its stdout and stderr are passed through
+separately. Use -it for an interactive shell. autobox exits with the command's
+exit code.
+
+Examples:
+  autobox exec abc123def456 -- ls -la /app
+  autobox exec abc123def456 -- cat /app/config/simulation.json
+  autobox exec -it abc123def456 -- /bin/sh`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		simulationID, command, err := splitExecArgs(args, cmd.ArgsLenAtDash())
+		if err != nil {
+			return err
+		}
+		code, err := runExec(simulationID, command)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			os.Exit(code)
+		}
+		return nil
+	},
+}
+
+func init() {
+	execCmd.Flags().BoolVarP(&execInteractive, "interactive", "i", false, "Attach stdin to the command")
+	execCmd.Flags().BoolVarP(&execTTY, "tty", "t", false, "Allocate a pseudo-terminal (stdin must be a terminal)")
+}
+
+// splitExecArgs separates the simulation ID from the command. dashAt is the
+// number of arguments before --, or -1 when there was none; without -- the
+// command is everything after the ID.
+func splitExecArgs(args []string, dashAt int) (string, []string, error) {
+	switch {
+	case len(args) == 0 || dashAt == 0:
+		return "", nil, fmt.Errorf("missing simulation ID (usage: autobox exec SIMULATION_ID -- COMMAND [ARG...])")
+	case dashAt > 1:
+		return "", nil, fmt.Errorf("expected one simulation ID before --, got %d: %v", dashAt, args[:dashAt])
+	case len(args) == 1:
+		return "", nil, fmt.Errorf("no command given (usage: autobox exec SIMULATION_ID -- COMMAND [ARG...])")
+	}
+	return args[0], args[1:], nil
+}
+
+func runExec(ref string, command []string) (int, error) {
+	ctx := context.Background()
+
+	opts := docker.ExecOptions{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		TTY:    execTTY,
+	}
+	if execInteractive {
+		opts.Stdin = os.Stdin
+	}
+
+	if execTTY {
+		fd, isTerminal := term.GetFdInfo(os.Stdin)
+		if !isTerminal {
+			return 0, fmt.Errorf("--tty requires stdin to be a terminal")
+		}
+		if size, err := term.GetWinsize(fd); err == nil {
+			opts.ConsoleSize = &[2]uint{uint(size.Height), uint(size.Width)}
+		}
+	}
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	simulationID, err := resolveSimulationID(ctx, client, ref)
+	if err != nil {
+		return 0, err
+	}
+
+	// Raw mode passes keystrokes such as Ctrl+C through to the command
+	// instead of acting on autobox itself.
+	if execTTY && execInteractive {
+		fd, _ := term.GetFdInfo(os.Stdin)
+		state, err := term.SetRawTerminal(fd)
+		if err != nil {
+			return 0, fmt.Errorf("failed to put the terminal in raw mode: %w", err)
+		}
+		defer term.RestoreTerminal(fd, state)
+	}
+
+	code, err := client.ExecInSimulation(ctx, simulationID, command, opts)
+	if err != nil {
+		if docker.IsNotFound(err) {
+			return 0, fmt.Errorf("simulation %s not found", ref)
+		}
+		return 0, err
+	}
+	return code, nil
+}
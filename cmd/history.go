@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/internal/state"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit      int
+	historySort       string
+	historyReverse    bool
+	historyFailedOnly bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show past simulation runs",
+	Long: `Show simulation runs recorded by this CLI, newest first, including runs
+whose containers have since been terminated.
+
+Runs are recorded in ~/.autobox/state/history.jsonl when launched. Their exit
+is recorded by terminate, or the next time history finds the container has
+finished.
+
+Examples:
+  autobox history
+  autobox history --limit 50
+  autobox history --sort duration
+  autobox history --failed-only --sort exit-code
+  autobox history --output json`,
+	RunE: runHistory,
+}
+
+func init() {
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Maximum number of runs to show (0 for all)")
+	historyCmd.Flags().StringVar(&historySort, "sort", "", "Sort by started, duration or exit-code (newest, longest or highest first)")
+	historyCmd.Flags().BoolVar(&historyReverse, "reverse", false, "Reverse the sort order (oldest first by default)")
+	historyCmd.Flags().BoolVar(&historyFailedOnly, "failed-only", false, "Only show runs that failed or exited non-zero")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if historyLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	switch historySort {
+	case "", "started", "duration", "exit-code":
+	default:
+		return fmt.Errorf("invalid --sort %q (expected started, duration or exit-code)", historySort)
+	}
+
+	records, err := state.ReadHistory()
+	if err != nil {
+		return err
+	}
+
+	if hasUnfinishedRuns(records) {
+		client, err := docker.NewClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Could not check running simulations: %v\n", color.YellowString("⚠"), err)
+		} else {
+			defer client.Close()
+			records = reconcileHistory(context.Background(), client, records)
+		}
+	}
+
+	if historyFailedOnly {
+		records = filterFailedRuns(records)
+	}
+	sortHistory(records, historySort, historyReverse, time.Now())
+	if historyLimit > 0 && len(records) > historyLimit {
+		records = records[:historyLimit]
+	}
+
+	switch output {
+	case "json":
+		return outputJSON(records)
+	case "yaml":
+		return outputYAML(records)
+	default:
+		return outputHistoryTable(records)
+	}
+}
+
+func filterFailedRuns(records []state.HistoryRecord) []state.HistoryRecord {
+	var failed []state.HistoryRecord
+	for _, record := range records {
+		if record.Outcome == string(models.StatusFailed) || (record.ExitCode != nil && *record.ExitCode != 0) {
+			failed = append(failed, record)
+		}
+	}
+	return failed
+}
+
+// sortHistory orders records, which arrive in launch order, newest first, or
+// by the given key with the largest value first. Runs still going count
+// their duration up to now, and runs without an exit code sort below those
+// with one. Ties keep the newest first.
+func sortHistory(records []state.HistoryRecord, key string, reverse bool, now time.Time) {
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := historySortValue(records[i], key, now), historySortValue(records[j], key, now)
+		if reverse {
+			return a < b
+		}
+		return a > b
+	})
+}
+
+func historySortValue(record state.HistoryRecord, key string, now time.Time) int64 {
+	switch key {
+	case "duration":
+		if !record.Finished() {
+			return int64(now.Sub(record.StartedAt))
+		}
+		return int64(record.Duration())
+	case "exit-code":
+		if record.ExitCode == nil {
+			return -1
+		}
+		return int64(*record.ExitCode)
+	default:
+		return record.StartedAt.UnixNano()
+	}
+}
+
+func hasUnfinishedRuns(records []state.HistoryRecord) bool {
+	for _, record := range records {
+		if !record.Finished() {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileHistory records the exit of runs that finished since the last
+// invocation. Runs are left untouched when Docker can't be queried, so an
+// unreachable daemon isn't mistaken for a removed container.
+func reconcileHistory(ctx context.Context, client *docker.Client, records []state.HistoryRecord) []state.HistoryRecord {
+	for i, record := range records {
+		if record.Finished() {
+			continue
+		}
+
+		sim, err := client.InspectSimulation(ctx, record.ContainerID)
+		var update state.HistoryRecord
+		switch {
+		case docker.IsNotFound(err):
+			update = state.HistoryRecord{ContainerID: record.ContainerID, Outcome: state.OutcomeRemoved}
+		case err != nil:
+			continue
+		case sim.Status == models.StatusRunning || sim.Status == models.StatusPending || sim.Status == models.StatusPaused:
+			continue
+		default:
+			update = finishedHistoryRecord(sim, string(sim.Status))
+		}
+
+		if err := state.AppendHistory(update); err != nil {
+			fmt.Fprintf(os.Stderr, "%s Failed to update history: %v\n", color.YellowString("⚠"), err)
+		}
+		records[i] = record.Merge(update)
+	}
+	return records
+}
+
+// warnDigestChange warns when a tag now resolves to a different image than
+// the last recorded run of the same simulation with that tag used, so a
+// silently moved tag doesn't go unnoticed. Digest-pinned images can't drift.
+func warnDigestChange(name, image, digest string) {
+	if digest == "" || strings.Contains(image, "@") {
+		return
+	}
+	records, err := state.ReadHistory()
+	if err != nil {
+		return
+	}
+	if previous := previousImageDigest(records, name, image); previous != "" && previous != digest {
+		fmt.Fprintf(os.Stderr, "%s %s now resolves to %s, but the last %s run used %s; pin %s to reproduce that run\n",
+			color.YellowString("⚠"), image, digest, name, previous, previous)
+	}
+}
+
+// previousImageDigest returns the digest recorded for the latest run of name
+// with image, or an empty string.
+func previousImageDigest(records []state.HistoryRecord, name, image string) string {
+	var latest *state.HistoryRecord
+	for i, record := range records {
+		if record.Name != name || record.Image != image || record.ImageDigest == "" {
+			continue
+		}
+		if latest == nil || record.StartedAt.After(latest.StartedAt) {
+			latest = &records[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.ImageDigest
+}
+
+// recordLaunch adds a run to the history. History is best-effort and never
+// fails the launch itself.
+func recordLaunch(simulation *models.Simulation, name, image string) {
+	startedAt := time.Now()
+	if simulation.StartedAt != nil {
+		startedAt = *simulation.StartedAt
+	}
+
+	err := state.AppendHistory(state.HistoryRecord{
+		ContainerID: simulation.ContainerID,
+		Name:        name,
+		Image:       image,
+		ImageDigest: simulation.Config.ImageDigest,
+		StartedAt:   startedAt,
+		Outcome:     state.OutcomeRunning,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to record run history: %v\n", color.YellowString("⚠"), err)
+	}
+}
+
+// recordTermination records the final state of a simulation that terminate
+// has just removed, since reconcileHistory can no longer inspect it.
+func recordTermination(sim *models.Simulation) {
+	outcome := string(sim.Status)
+	if sim.Status == models.StatusRunning || sim.Status == models.StatusPending || sim.Status == models.StatusPaused {
+		outcome = state.OutcomeTerminated
+		now := time.Now()
+		sim.FinishedAt = &now
+	}
+
+	if err := state.AppendHistory(finishedHistoryRecord(sim, outcome)); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to update history: %v\n", color.YellowString("⚠"), err)
+	}
+}
+
+func finishedHistoryRecord(sim *models.Simulation, outcome string) state.HistoryRecord {
+	record := state.HistoryRecord{
+		ContainerID: sim.ContainerID,
+		Name:        sim.Name,
+		Image:       sim.Config.Image,
+		FinishedAt:  sim.FinishedAt,
+		ExitCode:    sim.ExitCode,
+		Outcome:     outcome,
+	}
+	if sim.StartedAt != nil {
+		record.StartedAt = *sim.StartedAt
+	}
+	return record
+}
+
+func outputHistoryTable(records []state.HistoryRecord) error {
+	if len(records) == 0 {
+		fmt.Println(color.YellowString("No recorded runs"))
+		return nil
+	}
+
+	fmt.Printf("\n%s Last %d run(s)\n\n", color.CyanString("▶"), len(records))
+
+	fmt.Printf("%-12s  %-30s  %-16s  %-10s  %-9s  %-12s\n", "ID", "NAME", "STARTED", "DURATION", "EXIT CODE", "OUTCOME")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for _, record := range records {
+		duration := "-"
+		if d := record.Duration(); d > 0 {
+			duration = formatDuration(d)
+		} else if !record.Finished() {
+			duration = formatDuration(time.Since(record.StartedAt))
+		}
+
+		exitCode := "-"
+		if record.ExitCode != nil {
+			exitCode = fmt.Sprintf("%d", *record.ExitCode)
+		}
+
+		fmt.Printf("%-12s  %-30s  %-16s  %-10s  %-9s  %s\n",
+			color.CyanString(shortID(record.ContainerID)),
+			truncate(record.Name, 30),
+			record.StartedAt.Local().Format("2006-01-02 15:04"),
+			duration,
+			exitCode,
+			colorizeOutcome(record.Outcome),
+		)
+	}
+
+	return nil
+}
+
+func colorizeOutcome(outcome string) string {
+	switch outcome {
+	case state.OutcomeTerminated, state.OutcomeRemoved:
+		return color.YellowString(outcome)
+	default:
+		return colorizeStatus(models.SimulationStatus(outcome))
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/template"
+)
+
+// hookData is the template context for --on-complete and --on-failure.
+type hookData struct {
+	ID       string
+	Name     string
+	Status   string
+	ExitCode int64
+}
+
+var hookFuncs = template.FuncMap{
+	"quote": shellQuote,
+}
+
+// renderHook expands a hook command template such as
+// "./collect.sh {{.ID}} {{quote .Name}}".
+func renderHook(command string, data hookData) (string, error) {
+	tmpl, err := template.New("hook").Funcs(hookFuncs).Option("missingkey=error").Parse(command)
+	if err != nil {
+		return "", fmt.Errorf("invalid hook template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render hook: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// runHook executes the rendered command through the system shell with the
+// caller's privileges, streaming its output to the terminal.
+func runHook(command string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// shellQuote wraps s in single quotes for POSIX shells, so simulation names
+// containing spaces or metacharacters are passed as a single literal argument.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenderHook(t *testing.T) {
+	data := hookData{ID: "abc123def456", Name: "it's a sim", Status: "failed", ExitCode: 137}
+
+	tests := []struct {
+		name     string
+		command  string
+		expected string
+		wantErr  bool
+	}{
+		{"ID only", "./collect.sh {{.ID}}", "./collect.sh abc123def456", false},
+		{"All fields", "notify {{.Status}} {{.ExitCode}}", "notify failed 137", false},
+		{"Quoted name", "echo {{quote .Name}}", `echo 'it'\''s a sim'`, false},
+		{"Unknown field", "echo {{.Missing}}", "", true},
+		{"Bad template", "echo {{.ID", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := renderHook(tt.command, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderHook(%q) error = %v, wantErr %v", tt.command, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("renderHook(%q) = %q, want %q", tt.command, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateExitHooks(t *testing.T) {
+	defer func() {
+		runDetach, runDetachAfter, runOnComplete, runOnFailure = false, 0, "", ""
+	}()
+
+	tests := []struct {
+		name        string
+		detach      bool
+		detachAfter time.Duration
+		onComplete  string
+		wantErr     bool
+	}{
+		{"Hook while following", false, 0, "./collect.sh {{.ID}}", false},
+		{"Hook with --detach", true, 0, "./collect.sh {{.ID}}", true},
+		{"Hook with --detach-after", false, time.Minute, "./collect.sh {{.ID}}", true},
+		{"--detach-after without hooks", false, time.Minute, "", false},
+		{"Bad template", false, 0, "echo {{.ID", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runDetach, runDetachAfter, runOnComplete, runOnFailure = tt.detach, tt.detachAfter, tt.onComplete, ""
+			if err := validateExitHooks(); (err != nil) != tt.wantErr {
+				t.Errorf("validateExitHooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
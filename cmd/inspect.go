@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var inspectShowSecrets bool
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect SIMULATION_ID|NAME",
+	Short: "Show Docker's full inspect output for a simulation",
+	Long: `Show the complete docker inspect output for a simulation's container:
+mounts, network settings, state including the exit code and OOM kill flag,
+labels and so on. status summarizes this; inspect shows it as Docker reports it.
+
+Output is JSON unless --output yaml is given. Environment variables that look
+like credentials (see output.secret_pattern) have their values masked, as in
+status; --show-secrets prints them as they are.
+
+Examples:
+  autobox inspect abc123def456
+  autobox inspect gift_choice --output yaml
+  autobox inspect abc123def456 | jq '.State'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInspect,
+}
+
+func init() {
+	inspectCmd.Flags().BoolVar(&inspectShowSecrets, "show-secrets", false, "Don't mask the values of secret environment variables")
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	simulationID, err := resolveSimulationID(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	inspect, err := client.InspectSimulationRaw(ctx, simulationID)
+	if err != nil {
+		return err
+	}
+
+	if inspect.Config != nil && !inspectShowSecrets {
+		masked := *inspect.Config
+		masked.Env = redactEnvList(masked.Env, secretPattern(config.Get().Output.SecretPattern))
+		inspect.Config = &masked
+	}
+
+	if output == "yaml" {
+		node, err := jsonToYAMLNode(inspect)
+		if err != nil {
+			return err
+		}
+		return outputYAML(node)
+	}
+	return outputJSON(inspect)
+}
+
+// jsonToYAMLNode converts data to YAML by way of its JSON encoding, so the
+// keys and their order match docker inspect rather than the Go field names.
+func jsonToYAMLNode(data interface{}) (*yaml.Node, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is valid YAML, but parsing it keeps the flow style and quoting.
+	var node yaml.Node
+	if err := yaml.Unmarshal(encoded, &node); err != nil {
+		return nil, err
+	}
+	resetYAMLStyle(&node)
+	return &node, nil
+}
+
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
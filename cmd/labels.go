@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+)
+
+// reservedLabels returns the full label keys the CLI manages itself, under
+// the configured label prefix.
+func reservedLabels() map[string]bool {
+	prefix := docker.LabelPrefix()
+	reserved := make(map[string]bool, len(docker.ReservedLabelKeys))
+	for _, key := range docker.ReservedLabelKeys {
+		reserved[prefix+"."+key] = true
+	}
+	return reserved
+}
+
+// validateCustomLabels rejects user-supplied labels in the CLI's namespace.
+// The whole prefix is reserved, not just the keys in use today, so a label
+// set now can't silently change meaning when a later version starts using
+// it. Every offending key is listed in a single error, and any that the CLI
+// already uses are called out.
+func validateCustomLabels(labels map[string]string) error {
+	prefix := docker.LabelPrefix() + "."
+	reserved := reservedLabels()
+
+	var attempted []string
+	for key := range labels {
+		if strings.HasPrefix(key, prefix) {
+			attempted = append(attempted, key)
+		}
+	}
+	if len(attempted) == 0 {
+		return nil
+	}
+	sort.Strings(attempted)
+
+	var inUse []string
+	for _, key := range attempted {
+		if reserved[key] {
+			inUse = append(inUse, key)
+		}
+	}
+
+	msg := fmt.Sprintf("reserved label(s) can't be set: %s (keys under %s* are managed by autobox", strings.Join(attempted, ", "), prefix)
+	if len(inUse) > 0 {
+		msg += "; " + strings.Join(inUse, ", ") + " identify the simulation"
+	}
+	return fmt.Errorf("%s)", msg)
+}
+
+// parseLabels turns repeated KEY=VALUE flags into a map and checks them with
+// validateCustomLabels. An empty value is allowed, as with docker run --label.
+func parseLabels(flag string, values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, _ := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid %s %q (expected KEY=VALUE)", flag, value)
+		}
+		labels[key] = val
+	}
+
+	if err := validateCustomLabels(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
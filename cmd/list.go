@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/Autobox-AI/autobox-cli/internal/config"
 	"github.com/Autobox-AI/autobox-cli/internal/docker"
 	"github.com/Autobox-AI/autobox-cli/pkg/models"
 	"github.com/fatih/color"
@@ -13,29 +18,87 @@ import (
 )
 
 var (
-	listAll bool
+	listAll        bool
+	listMetaFilter []string
+	listStatus     []string
+	listName       string
+	listSince      time.Duration
+	listSort       string
+	listReverse    bool
+	listWide       bool
+	listYAMLDocs   bool
 )
 
+// highRestartCount is the restart count from which list and status flag a
+// simulation as crash-looping.
+const highRestartCount = 3
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all simulations",
 	Long: `List all Autobox simulations with their current status.
 
+With --output json or yaml, the simulations come under "simulations", next to
+a "summary" of counts by status. --yaml-documents instead emits one document
+per simulation, without the summary.
+
+Only live simulations, running or paused, are listed unless --all or --status
+is given. The filters combine: a simulation is listed when its status is one
+of the --status values, its name contains --name (ignoring case), it was
+created within --since, and its metadata matches every --meta-filter.
+
+The list is in Docker's order unless --sort is given: created puts the newest
+first, and name, status and id sort alphabetically. --reverse flips the order;
+simulations that tie keep their original order either way.
+
 Examples:
   autobox list
   autobox list --all
-  autobox list --output json`,
+  autobox list --output json
+  autobox list --wide
+  autobox list --output yaml --yaml-documents
+  autobox list --status running --status failed
+  autobox list --all --name gift --since 24h
+  autobox list --all --sort created
+  autobox list --all --sort name --reverse
+  autobox list --all --meta-filter experiment=42 --meta-filter owner=me`,
 	RunE: runList,
 }
 
 func init() {
 	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "Show all simulations (including stopped)")
+	listCmd.Flags().BoolVar(&listWide, "wide", false, "Show additional columns such as restart count (inspects each container)")
+	listCmd.Flags().BoolVar(&listYAMLDocs, "yaml-documents", false, "With --output yaml, emit each simulation as a separate --- document")
+	listCmd.Flags().StringArrayVar(&listMetaFilter, "meta-filter", []string{}, "Only show simulations whose --meta matches key=value (repeatable, dotted keys allowed)")
+	listCmd.Flags().StringArrayVar(&listStatus, "status", []string{}, "Only show simulations in this state (repeatable, e.g. --status running --status failed)")
+	listCmd.Flags().StringVar(&listName, "name", "", "Only show simulations whose name contains this (case-insensitive)")
+	listCmd.Flags().Var(newExtendedDuration(0, &listSince), "since", "Only show simulations created within this long (e.g. 2h, 7d)")
+	listCmd.Flags().StringVar(&listSort, "sort", "", "Sort by created (newest first), name, status or id")
+	listCmd.Flags().BoolVar(&listReverse, "reverse", false, "With --sort, reverse the order")
+	listCmd.MarkFlagsMutuallyExclusive("all", "status")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if listYAMLDocs && output != "yaml" {
+		return fmt.Errorf("--yaml-documents requires --output yaml")
+	}
+	switch listSort {
+	case "", "created", "name", "status", "id":
+	default:
+		return fmt.Errorf("invalid --sort %q (expected created, name, status or id)", listSort)
+	}
+	if listReverse && listSort == "" {
+		return fmt.Errorf("--reverse requires --sort")
+	}
+
+	filters, err := listFilters(time.Now())
+	if err != nil {
+		return err
+	}
+
 	ctx := context.Background()
 
-	client, err := docker.NewClient()
+	client, err := newSimulationManager()
 	if err != nil {
 		return fmt.Errorf("failed to create Docker client: %w", err)
 	}
@@ -45,21 +108,94 @@ func runList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to list simulations: %w", err)
 	}
+	simulations = applyFilters(simulations, filters...)
+	if listSort != "" {
+		sortSimulations(simulations, listSort, listReverse)
+	}
 
-	if !listAll {
-		simulations = filterRunningSimulations(simulations)
+	if listWide {
+		simulations, err = inspectSimulations(ctx, client, simulations)
+		if err != nil {
+			return err
+		}
 	}
 
+	redactSimulations(simulations, secretPattern(config.Get().Output.SecretPattern))
+
 	switch output {
 	case "json":
-		return outputJSON(simulations)
+		return outputJSON(newListOutput(simulations))
 	case "yaml":
-		return outputYAML(simulations)
+		if listYAMLDocs {
+			return outputYAMLDocuments(os.Stdout, simulations)
+		}
+		return outputYAML(newListOutput(simulations))
+	case "csv":
+		return outputCSV(simulations)
 	default:
 		return outputListTable(simulations)
 	}
 }
 
+// listOutput is the --output json/yaml document for list: the simulations
+// and the counts shown under the table.
+type listOutput struct {
+	Simulations []*models.Simulation `json:"simulations" yaml:"simulations"`
+	Summary     listSummary          `json:"summary" yaml:"summary"`
+}
+
+// listSummary counts every status, so the counts add up to Total.
+type listSummary struct {
+	Pending   int `json:"pending" yaml:"pending"`
+	Running   int `json:"running" yaml:"running"`
+	Paused    int `json:"paused" yaml:"paused"`
+	Completed int `json:"completed" yaml:"completed"`
+	Failed    int `json:"failed" yaml:"failed"`
+	Stopped   int `json:"stopped" yaml:"stopped"`
+	Total     int `json:"total" yaml:"total"`
+}
+
+func newListOutput(simulations []*models.Simulation) listOutput {
+	if simulations == nil {
+		simulations = []*models.Simulation{}
+	}
+	return listOutput{
+		Simulations: simulations,
+		Summary: listSummary{
+			Pending:   countByStatus(simulations, models.StatusPending),
+			Running:   countByStatus(simulations, models.StatusRunning),
+			Paused:    countByStatus(simulations, models.StatusPaused),
+			Completed: countByStatus(simulations, models.StatusCompleted),
+			Failed:    countByStatus(simulations, models.StatusFailed),
+			Stopped:   countByStatus(simulations, models.StatusStopped),
+			Total:     len(simulations),
+		},
+	}
+}
+
+// sortSimulations orders simulations by key: newest first for created,
+// otherwise ascending. reverse flips that, and ties keep their order.
+func sortSimulations(simulations []*models.Simulation, key string, reverse bool) {
+	less := func(a, b *models.Simulation) bool {
+		switch key {
+		case "created":
+			return a.CreatedAt.After(b.CreatedAt)
+		case "name":
+			return a.Name < b.Name
+		case "status":
+			return a.Status < b.Status
+		default:
+			return a.ID < b.ID
+		}
+	}
+	sort.SliceStable(simulations, func(i, j int) bool {
+		if reverse {
+			return less(simulations[j], simulations[i])
+		}
+		return less(simulations[i], simulations[j])
+	})
+}
+
 func filterRunningSimulations(simulations []*models.Simulation) []*models.Simulation {
 	var running []*models.Simulation
 	for _, sim := range simulations {
@@ -70,16 +206,193 @@ func filterRunningSimulations(simulations []*models.Simulation) []*models.Simula
 	return running
 }
 
+// inspectSimulations replaces list entries with fully inspected ones, which
+// carry fields such as RestartCount that the container list API omits.
+func inspectSimulations(ctx context.Context, client docker.SimulationManager, simulations []*models.Simulation) ([]*models.Simulation, error) {
+	inspected := make([]*models.Simulation, 0, len(simulations))
+	for _, sim := range simulations {
+		full, err := client.InspectSimulation(ctx, sim.ContainerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect simulation %s: %w", sim.ID, err)
+		}
+		inspected = append(inspected, full)
+	}
+	return inspected, nil
+}
+
+// simulationFilter reports whether list should show a simulation.
+type simulationFilter func(*models.Simulation) bool
+
+// listFilters builds list's filters from its flags. Without --status only
+// live simulations, running or paused, are shown; --all drops the status
+// filter.
+func listFilters(now time.Time) ([]simulationFilter, error) {
+	var filters []simulationFilter
+
+	if len(listStatus) > 0 {
+		statuses, err := parseStatusFilters(listStatus)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, hasStatus(statuses...))
+	} else if !listAll {
+		filters = append(filters, hasStatus(models.StatusRunning, models.StatusPaused))
+	}
+
+	if listName != "" {
+		filters = append(filters, nameContains(listName))
+	}
+
+	if listSince < 0 {
+		return nil, fmt.Errorf("--since must not be negative")
+	}
+	if listSince > 0 {
+		filters = append(filters, createdSince(now.Add(-listSince)))
+	}
+
+	if len(listMetaFilter) > 0 {
+		meta, err := parseMetaFilters(listMetaFilter)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, metadataMatches(meta))
+	}
+
+	return filters, nil
+}
+
+// applyFilters keeps the simulations that pass every filter.
+func applyFilters(simulations []*models.Simulation, filters ...simulationFilter) []*models.Simulation {
+	var matched []*models.Simulation
+	for _, sim := range simulations {
+		keep := true
+		for _, filter := range filters {
+			if !filter(sim) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			matched = append(matched, sim)
+		}
+	}
+	return matched
+}
+
+func hasStatus(statuses ...models.SimulationStatus) simulationFilter {
+	return func(sim *models.Simulation) bool {
+		for _, status := range statuses {
+			if sim.Status == status {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func nameContains(substr string) simulationFilter {
+	substr = strings.ToLower(substr)
+	return func(sim *models.Simulation) bool {
+		return strings.Contains(strings.ToLower(sim.Name), substr)
+	}
+}
+
+func createdSince(cutoff time.Time) simulationFilter {
+	return func(sim *models.Simulation) bool {
+		return !sim.CreatedAt.Before(cutoff)
+	}
+}
+
+func metadataMatches(filters map[string]string) simulationFilter {
+	return func(sim *models.Simulation) bool {
+		return matchesMetadata(sim.Config.Metadata, filters)
+	}
+}
+
+func parseStatusFilters(raw []string) ([]models.SimulationStatus, error) {
+	statuses := make([]models.SimulationStatus, 0, len(raw))
+	for _, r := range raw {
+		status := models.SimulationStatus(strings.ToLower(r))
+		switch status {
+		case models.StatusPending, models.StatusRunning, models.StatusPaused,
+			models.StatusCompleted, models.StatusFailed, models.StatusStopped:
+			statuses = append(statuses, status)
+		default:
+			return nil, fmt.Errorf("invalid --status %q (expected pending, running, paused, completed, failed or stopped)", r)
+		}
+	}
+	return statuses, nil
+}
+
+func parseMetaFilters(raw []string) (map[string]string, error) {
+	filters := make(map[string]string, len(raw))
+	for _, f := range raw {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --meta-filter %q (expected key=value)", f)
+		}
+		filters[key] = value
+	}
+	return filters, nil
+}
+
+// matchesMetadata compares each filter against the stored JSON value's
+// string form, so experiment=42 matches the number 42 and the string "42".
+func matchesMetadata(metadata map[string]interface{}, filters map[string]string) bool {
+	for key, want := range filters {
+		var value interface{} = metadata
+		for _, part := range strings.Split(key, ".") {
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			if value, ok = obj[part]; !ok {
+				return false
+			}
+		}
+
+		var got string
+		switch v := value.(type) {
+		case string:
+			got = v
+		case nil:
+			got = "null"
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return false
+			}
+			got = string(encoded)
+		}
+		if got != want {
+			return false
+		}
+	}
+	return true
+}
+
 func outputListTable(simulations []*models.Simulation) error {
+	return writeListTable(os.Stdout, simulations)
+}
+
+// writeListTable renders the list table to w, so watch can draw a whole frame
+// before putting it on screen.
+func writeListTable(w io.Writer, simulations []*models.Simulation) error {
 	if len(simulations) == 0 {
-		fmt.Println(color.YellowString("No simulations found"))
+		fmt.Fprintln(w, color.YellowString("No simulations found"))
 		return nil
 	}
 
-	fmt.Printf("\n%s Found %d simulation(s)\n\n", color.CyanString("▶"), len(simulations))
+	fmt.Fprintf(w, "\n%s Found %d simulation(s)\n\n", color.CyanString("▶"), len(simulations))
 
-	fmt.Printf("%-12s  %-30s  %-12s  %-16s  %-12s\n", "ID", "NAME", "STATUS", "CREATED", "RUNNING FOR")
-	fmt.Println(strings.Repeat("-", 90))
+	header := fmt.Sprintf("%-12s  %-30s  %-12s  %-16s  %-12s", "ID", "NAME", "STATUS", "CREATED", "RUNNING FOR")
+	width := 90
+	if listWide {
+		header += fmt.Sprintf("  %-8s", "RESTARTS")
+		width += 10
+	}
+	fmt.Fprintln(w, header)
+	fmt.Fprintln(w, strings.Repeat("-", width))
 
 	for _, sim := range simulations {
 		runningFor := "-"
@@ -91,34 +404,61 @@ func outputListTable(simulations []*models.Simulation) error {
 		statusStr := colorizeStatus(sim.Status)
 		idStr := color.CyanString(sim.ID)
 
-		fmt.Printf("%-12s  %-30s  %-12s  %-16s  %-12s\n",
+		fmt.Fprintf(w, "%-12s  %-30s  %-12s  %-16s  %-12s",
 			idStr,
 			truncate(sim.Name, 30),
 			statusStr,
 			sim.CreatedAt.Format("2006-01-02 15:04"),
 			runningFor,
 		)
+		if listWide {
+			restarts := "-"
+			if sim.RestartCount > 0 {
+				restarts = colorizeRestartCount(sim.RestartCount)
+			}
+			fmt.Fprintf(w, "  %s", restarts)
+		}
+		fmt.Fprintln(w)
 	}
 
 	running := countByStatus(simulations, models.StatusRunning)
 	completed := countByStatus(simulations, models.StatusCompleted)
 	failed := countByStatus(simulations, models.StatusFailed)
+	paused := countByStatus(simulations, models.StatusPaused)
+	stopped := countByStatus(simulations, models.StatusStopped)
+	pending := countByStatus(simulations, models.StatusPending)
 
-	fmt.Printf("\nSummary: ")
+	fmt.Fprintf(w, "\nSummary: ")
+	if pending > 0 {
+		fmt.Fprintf(w, "%d pending ", pending)
+	}
 	if running > 0 {
-		fmt.Printf("%s ", color.GreenString("%d running", running))
+		fmt.Fprintf(w, "%s ", color.GreenString("%d running", running))
+	}
+	if paused > 0 {
+		fmt.Fprintf(w, "%s ", color.MagentaString("%d paused", paused))
 	}
 	if completed > 0 {
-		fmt.Printf("%s ", color.BlueString("%d completed", completed))
+		fmt.Fprintf(w, "%s ", color.BlueString("%d completed", completed))
 	}
 	if failed > 0 {
-		fmt.Printf("%s ", color.RedString("%d failed", failed))
+		fmt.Fprintf(w, "%s ", color.RedString("%d failed", failed))
+	}
+	if stopped > 0 {
+		fmt.Fprintf(w, "%s ", color.YellowString("%d stopped", stopped))
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	return nil
 }
 
+func colorizeRestartCount(count int) string {
+	if count >= highRestartCount {
+		return color.RedString("%d", count)
+	}
+	return color.YellowString("%d", count)
+}
+
 func countByStatus(simulations []*models.Simulation, status models.SimulationStatus) int {
 	count := 0
 	for _, sim := range simulations {
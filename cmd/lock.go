@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/internal/state"
+)
+
+// resolveSimulationID turns a simulation ID, ID prefix or name, as typed on
+// the command line, into the full container ID.
+func resolveSimulationID(ctx context.Context, client docker.SimulationManager, ref string) (string, error) {
+	sim, err := client.ResolveSimulation(ctx, ref)
+	if err != nil {
+		if docker.IsNotFound(err) {
+			return "", fmt.Errorf("simulation %s not found", ref)
+		}
+		return "", err
+	}
+	return sim.ContainerID, nil
+}
+
+// lockSimulation resolves simulationID to its full container ID before
+// locking, so "abc123" and the full ID contend for the same lock.
+func lockSimulation(ctx context.Context, client docker.SimulationManager, simulationID string) (*state.Lock, error) {
+	containerID, err := client.ResolveContainerID(ctx, simulationID)
+	if err != nil {
+		return nil, err
+	}
+	return state.AcquireLock(containerID)
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Keys engines commonly use for the fields logs --pretty puts up front, in
+// order of preference.
+var (
+	prettyTimeKeys    = []string{"timestamp", "time", "ts", "asctime"}
+	prettyLevelKeys   = []string{"level", "levelname", "severity", "lvl"}
+	prettyMessageKeys = []string{"message", "msg"}
+)
+
+// logLine is a log line split into the timestamp Docker adds, if any, and
+// the engine's own text, with its fields when that text is a JSON object.
+type logLine struct {
+	raw        string
+	dockerTime string
+	body       string
+	fields     map[string]interface{}
+}
+
+func parseLogLine(line string) logLine {
+	parsed := logLine{raw: line, body: line}
+	if prefix, rest, ok := strings.Cut(line, " "); ok {
+		if _, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+			parsed.dockerTime, parsed.body = prefix, rest
+		}
+	}
+
+	trimmed := strings.TrimSpace(parsed.body)
+	if !strings.HasPrefix(trimmed, "{") {
+		return parsed
+	}
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err == nil && !decoder.More() {
+		parsed.fields = fields
+	}
+	return parsed
+}
+
+// level returns the line's log level as written: the level field of a JSON
+// line, or the leading token of a plain one such as "ERROR", "[warn]" or
+// Python's "INFO:root:...". It is "" when the line has none.
+func (l logLine) level() string {
+	if l.fields != nil {
+		for _, key := range prettyLevelKeys {
+			if value, ok := l.fields[key]; ok {
+				return fieldString(value)
+			}
+		}
+		return ""
+	}
+
+	token, _, _ := strings.Cut(strings.TrimSpace(l.body), " ")
+	token = strings.Trim(token, "[]()<>")
+	token, _, _ = strings.Cut(token, ":")
+	if _, known := logLevelRank(token); known {
+		return token
+	}
+	return ""
+}
+
+// prettyLogLine rewrites a JSON log line as "time LEVEL message key=value...",
+// with the level colorized and the remaining fields sorted by key. Lines may
+// start with the timestamp Docker adds, which is used when the JSON has none.
+// Anything that isn't a JSON object is returned unchanged.
+func prettyLogLine(line string) string {
+	return parseLogLine(line).pretty()
+}
+
+func (l logLine) pretty() string {
+	if l.fields == nil {
+		return l.raw
+	}
+
+	fields := make(map[string]interface{}, len(l.fields))
+	for key, value := range l.fields {
+		fields[key] = value
+	}
+
+	timestamp := takeField(fields, prettyTimeKeys)
+	if timestamp == "" {
+		timestamp = l.dockerTime
+	}
+	level := takeField(fields, prettyLevelKeys)
+	message := takeField(fields, prettyMessageKeys)
+
+	var parts []string
+	if timestamp != "" {
+		parts = append(parts, color.WhiteString(timestamp))
+	}
+	if level != "" {
+		parts = append(parts, colorizeLevel(level))
+	}
+	if message != "" {
+		parts = append(parts, message)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, color.CyanString(key)+"="+fieldString(fields[key]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// takeField removes and returns the first of keys present in fields.
+func takeField(fields map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok {
+			delete(fields, key)
+			return fieldString(value)
+		}
+	}
+	return ""
+}
+
+func fieldString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	case json.Number:
+		return v.String()
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+func colorizeLevel(level string) string {
+	label := strings.ToUpper(level)
+	switch label {
+	case "ERROR", "ERR", "CRITICAL", "FATAL", "PANIC":
+		return color.RedString(label)
+	case "WARN", "WARNING":
+		return color.YellowString(label)
+	case "INFO":
+		return color.GreenString(label)
+	case "DEBUG", "TRACE":
+		return color.BlueString(label)
+	default:
+		return label
+	}
+}
+
+// logLevelRank orders level names by severity, accepting the spellings
+// common logging libraries use.
+func logLevelRank(level string) (int, bool) {
+	switch strings.ToLower(level) {
+	case "trace":
+		return 0, true
+	case "debug":
+		return 1, true
+	case "info":
+		return 2, true
+	case "warn", "warning":
+		return 3, true
+	case "error", "err":
+		return 4, true
+	case "critical", "crit", "fatal", "panic":
+		return 5, true
+	default:
+		return 0, false
+	}
+}
+
+// logFilter drops log lines below a minimum level or not matching a
+// pattern. The zero value keeps every line.
+type logFilter struct {
+	minLevel int
+	byLevel  bool
+	pattern  *regexp.Regexp
+}
+
+// newLogFilter checks --level and compiles --grep once, before any log line
+// is read.
+func newLogFilter(level, pattern string) (logFilter, error) {
+	var filter logFilter
+	if level != "" {
+		rank, ok := logLevelRank(level)
+		if !ok {
+			return logFilter{}, fmt.Errorf("invalid --level %q (expected trace, debug, info, warn, error or fatal)", level)
+		}
+		filter.minLevel, filter.byLevel = rank, true
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return logFilter{}, fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+		filter.pattern = re
+	}
+	return filter, nil
+}
+
+// keep reports whether line passes the filter. With a minimum level, lines
+// without a recognizable level are dropped.
+func (f logFilter) keep(line logLine) bool {
+	if f.byLevel {
+		rank, ok := logLevelRank(line.level())
+		if !ok || rank < f.minLevel {
+			return false
+		}
+	}
+	if f.pattern != nil && !f.pattern.MatchString(line.raw) {
+		return false
+	}
+	return true
+}
+
+// logLineWriter filters and, with pretty set, reformats each complete line
+// written to it. A trailing partial line is held until it is completed or
+// Flush is called, so streamed and buffered logs are treated the same.
+type logLineWriter struct {
+	w       io.Writer
+	filter  logFilter
+	pretty  bool
+	pending []byte
+}
+
+func newLogLineWriter(w io.Writer, filter logFilter, pretty bool) *logLineWriter {
+	return &logLineWriter{w: w, filter: filter, pretty: pretty}
+}
+
+func (p *logLineWriter) Write(data []byte) (int, error) {
+	p.pending = append(p.pending, data...)
+	for {
+		i := bytes.IndexByte(p.pending, '\n')
+		if i < 0 {
+			return len(data), nil
+		}
+		line := strings.TrimSuffix(string(p.pending[:i]), "\r")
+		p.pending = p.pending[i+1:]
+		if err := p.writeLine(line, "\n"); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Flush writes out a trailing line that had no newline.
+func (p *logLineWriter) Flush() error {
+	if len(p.pending) == 0 {
+		return nil
+	}
+	line := string(p.pending)
+	p.pending = nil
+	return p.writeLine(line, "")
+}
+
+func (p *logLineWriter) writeLine(raw, end string) error {
+	line := parseLogLine(raw)
+	if !p.filter.keep(line) {
+		return nil
+	}
+	text := line.raw
+	if p.pretty {
+		text = line.pretty()
+	}
+	_, err := io.WriteString(p.w, text+end)
+	return err
+}
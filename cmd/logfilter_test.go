@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestPrettyLogLine(t *testing.T) {
+	saved := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = saved })
+
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			"Structured line",
+			`{"timestamp": "2024-01-15T14:30:00Z", "level": "info", "message": "agent started", "agent": "buyer", "step": 3}`,
+			"2024-01-15T14:30:00Z INFO agent started agent=buyer step=3",
+		},
+		{
+			"Docker timestamp used when the JSON has none",
+			`2024-01-15T14:30:01.123456789Z {"levelname": "WARNING", "msg": "slow response", "latency_ms": 1250.5}`,
+			"2024-01-15T14:30:01.123456789Z WARNING slow response latency_ms=1250.5",
+		},
+		{
+			"JSON timestamp wins over Docker's",
+			`2024-01-15T14:30:02Z {"time": "14:30:02", "severity": "error", "msg": "boom", "ctx": {"id": 7}}`,
+			`14:30:02 ERROR boom ctx={"id":7}`,
+		},
+		{"Plain line", "Starting simulation engine...", "Starting simulation engine..."},
+		{"Plain line with Docker timestamp", "2024-01-15T14:30:00Z Loading config", "2024-01-15T14:30:00Z Loading config"},
+		{"Broken JSON", `{"level": "info", "message": `, `{"level": "info", "message": `},
+		{"JSON array", `["not", "an", "object"]`, `["not", "an", "object"]`},
+		{"Empty line", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prettyLogLine(tt.line); got != tt.want {
+				t.Errorf("prettyLogLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrettyLogWriterMixedLines(t *testing.T) {
+	saved := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = saved })
+
+	logs := "booting\n" +
+		`{"level": "info", "msg": "ready", "port": 9000}` + "\n" +
+		"plain again\n" +
+		`{"level": "debug", "msg": "tail"}`
+
+	var out bytes.Buffer
+	pretty := newLogLineWriter(&out, logFilter{}, true)
+	// Write in small chunks, as a stream delivers them, splitting lines.
+	for i := 0; i < len(logs); i += 7 {
+		end := min(i+7, len(logs))
+		if _, err := pretty.Write([]byte(logs[i:end])); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if strings.Contains(out.String(), "tail") {
+		t.Errorf("unterminated last line written before Flush: %q", out.String())
+	}
+	if err := pretty.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := "booting\nINFO ready port=9000\nplain again\nDEBUG tail"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	lines := []string{
+		`2024-01-15T14:30:00Z {"level": "debug", "msg": "tick", "agent": "agent-1"}`,
+		`2024-01-15T14:30:01Z {"level": "info", "msg": "bought", "agent": "agent-2"}`,
+		`2024-01-15T14:30:02Z {"levelname": "WARNING", "msg": "slow", "agent": "agent-3"}`,
+		`2024-01-15T14:30:03Z {"severity": "error", "msg": "failed", "agent": "agent-1"}`,
+		`2024-01-15T14:30:04Z {"msg": "no level", "agent": "agent-2"}`,
+		"2024-01-15T14:30:05Z INFO engine ready",
+		"2024-01-15T14:30:06Z [ERROR] agent-2 crashed",
+		"2024-01-15T14:30:07Z WARNING:root:retrying agent-1",
+		"2024-01-15T14:30:08Z Traceback (most recent call last):",
+		"plain line for agent-3",
+	}
+
+	tests := []struct {
+		name    string
+		level   string
+		grep    string
+		wantIdx []int
+	}{
+		{"No filter", "", "", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"Warn and above", "warn", "", []int{2, 3, 6, 7}},
+		{"Error only", "ERROR", "", []int{3, 6}},
+		{"Info and above", "info", "", []int{1, 2, 3, 5, 6, 7}},
+		{"Grep", "", `agent-(1|3)`, []int{0, 2, 3, 7, 9}},
+		{"Grep on plain text", "", `^plain`, []int{9}},
+		{"Level and grep together", "warn", `agent-1`, []int{3, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newLogFilter(tt.level, tt.grep)
+			if err != nil {
+				t.Fatalf("newLogFilter() error = %v", err)
+			}
+			var got []int
+			for i, line := range lines {
+				if filter.keep(parseLogLine(line)) {
+					got = append(got, i)
+				}
+			}
+			if !slices.Equal(got, tt.wantIdx) {
+				t.Errorf("kept lines %v, want %v", got, tt.wantIdx)
+			}
+		})
+	}
+}
+
+func TestNewLogFilterErrors(t *testing.T) {
+	if _, err := newLogFilter("", "agent-(1"); err == nil || !strings.Contains(err.Error(), "--grep") {
+		t.Errorf("newLogFilter() with a bad pattern = %v, want a --grep error", err)
+	}
+	if _, err := newLogFilter("loud", ""); err == nil || !strings.Contains(err.Error(), "--level") {
+		t.Errorf("newLogFilter() with a bad level = %v, want a --level error", err)
+	}
+}
+
+func TestLogLineWriterFilters(t *testing.T) {
+	filter, err := newLogFilter("warn", "")
+	if err != nil {
+		t.Fatalf("newLogFilter() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	w := newLogLineWriter(&out, filter, false)
+	// Split mid-line, as a follow stream would deliver it.
+	for _, chunk := range []string{"INFO start\nERR", "OR disk full\nDEBUG x\n", "WARN last"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if want := "ERROR disk full\nWARN last"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
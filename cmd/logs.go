@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Autobox-AI/autobox-cli/internal/docker"
 	"github.com/Autobox-AI/autobox-cli/pkg/models"
@@ -16,12 +18,19 @@ import (
 )
 
 var (
-	logsTail int
-	logsLive bool
+	logsTail      int
+	logsFollow    bool
+	logsTee       string
+	logsTeeAppend bool
+	logsSinceRun  bool
+	logsSince     string
+	logsPretty    bool
+	logsLevel     string
+	logsGrep      string
 )
 
 var logsCmd = &cobra.Command{
-	Use:   "logs [SIMULATION_ID]",
+	Use:   "logs [SIMULATION_ID|NAME]",
 	Short: "Get logs from a simulation",
 	Long: `Retrieve logs from a specific Autobox simulation container.
 If no simulation ID is provided, shows a list of running simulations to choose from.
@@ -30,21 +39,60 @@ Examples:
   autobox logs                        # Select from running simulations
   autobox logs abc123def456
   autobox logs abc123def456 --tail 50
-  autobox logs --live
-  autobox logs abc123def456 --live --tail 20`,
+  autobox logs abc123def456 --tail 500 --since 10m   # At most 500 lines from the last 10 minutes
+  autobox logs -f                     # Select, then follow
+  autobox logs abc123def456 -f --tail 20
+  autobox logs abc123def456 --follow --tee run.log
+  autobox logs abc123def456 --since-start   # Only output since the last (re)start
+  autobox logs abc123def456 -f --pretty     # Reformat JSON log lines
+  autobox logs abc123def456 --level warn    # Warnings and errors only
+  autobox logs abc123def456 -f --grep 'agent-(1|2)'
+
+With --pretty, lines that are JSON objects are shown as time, colorized level
+and message followed by the remaining fields as key=value; other lines are
+printed unchanged.
+
+--level keeps lines at or above a level: the level field of JSON lines, or
+the leading word of plain ones (ERROR, [warn], INFO:root:...). Lines without a
+level are dropped. --grep keeps lines matching a regular expression. Both
+apply to the lines retrieved by --tail/--since, and to --follow.`,
 	Args: cobra.MaximumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if logsTee != "" && !logsFollow {
+			return fmt.Errorf("--tee requires --follow")
+		}
+		return validateTeePath(logsTee)
+	},
 	RunE: runLogs,
 }
 
 func init() {
-	logsCmd.Flags().IntVarP(&logsTail, "tail", "t", 100, "Number of lines to show from the end of the logs")
-	logsCmd.Flags().BoolVarP(&logsLive, "live", "l", false, "Stream logs in real-time")
+	logsCmd.Flags().IntVarP(&logsTail, "tail", "t", 100, "Number of lines to show from the end of the logs (0 for no limit, requires --since)")
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "Only show logs since a duration ago (10m, 2d) or a timestamp (RFC3339 or Unix)")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep streaming new log lines until the simulation exits or Ctrl+C")
+	logsCmd.Flags().BoolVarP(&logsFollow, "live", "l", false, "Alias for --follow")
+	logsCmd.Flags().BoolVar(&logsSinceRun, "since-start", false, "Only show logs since the container last (re)started")
+	logsCmd.Flags().StringVar(&logsTee, "tee", "", "With --follow, also write the stream to this file (ANSI colors stripped)")
+	logsCmd.Flags().BoolVar(&logsTeeAppend, "tee-append", false, "Append to the --tee file instead of truncating it")
+	logsCmd.Flags().BoolVar(&logsPretty, "pretty", false, "Reformat JSON log lines as time, level, message and key=value fields")
+	logsCmd.Flags().StringVar(&logsLevel, "level", "", "Only show lines at or above this level (debug, info, warn, error, fatal)")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "", "Only show lines matching this regular expression")
+	logsCmd.MarkFlagsMutuallyExclusive("since", "since-start")
 }
 
 func runLogs(cmd *cobra.Command, args []string) error {
+	if err := validateLogBounds(logsTail, logsSince, logsSinceRun); err != nil {
+		return err
+	}
+	filter, err := newLogFilter(logsLevel, logsGrep)
+	if err != nil {
+		return err
+	}
+	processLines := logsPretty || logsLevel != "" || logsGrep != ""
+
 	ctx := context.Background()
 
-	client, err := docker.NewClient()
+	client, err := newSimulationManager()
 	if err != nil {
 		return fmt.Errorf("failed to create Docker client: %w", err)
 	}
@@ -78,35 +126,126 @@ func runLogs(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 	} else {
-		simulationID = args[0]
+		simulationID, err = resolveSimulationID(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
 	}
 
-	if logsLive {
-		fmt.Printf("%s Streaming logs for %s (press Ctrl+C to stop)...\n\n",
-			color.YellowString("→"), color.CyanString(simulationID[:12]))
+	logOptions := docker.LogOptions{Tail: logsTail, Since: dockerSince(logsSince)}
+	if logsSinceRun {
+		sim, err := client.InspectSimulation(ctx, simulationID)
+		if err != nil {
+			return fmt.Errorf("failed to get simulation status: %w", err)
+		}
+		if sim.StartedAt == nil || sim.StartedAt.IsZero() {
+			fmt.Println(color.YellowString("Simulation %s has never been started; no logs since start", shortID(simulationID)))
+			return nil
+		}
+		logOptions.Since = sim.StartedAt.Format(time.RFC3339Nano)
+	}
 
-		reader, err := client.GetSimulationLogsStream(ctx, simulationID, logsTail)
+	if logsFollow {
+		out, closeTee, err := teeOutput(logsTee, logsTeeAppend)
 		if err != nil {
-			return fmt.Errorf("failed to get simulation logs: %w", err)
+			return err
 		}
-		defer reader.Close()
+		defer closeTee()
+
+		fmt.Printf("%s Following logs for %s (press Ctrl+C to stop)...\n\n",
+			color.YellowString("→"), color.CyanString(shortID(simulationID)))
 
-		_, err = io.Copy(os.Stdout, reader)
-		if err != nil && err != io.EOF {
-			return fmt.Errorf("failed to stream logs: %w", err)
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+		if !processLines {
+			return streamLogs(ctx, client, simulationID, logOptions, out)
 		}
-		return nil
+		lines := newLogLineWriter(out, filter, logsPretty)
+		if err := streamLogs(ctx, client, simulationID, logOptions, lines); err != nil {
+			return err
+		}
+		return lines.Flush()
 	}
 
-	logs, err := client.GetSimulationLogs(ctx, simulationID, logsTail)
+	logs, err := client.GetSimulationLogs(ctx, simulationID, logOptions)
 	if err != nil {
 		return fmt.Errorf("failed to get simulation logs: %w", err)
 	}
 
+	if processLines {
+		lines := newLogLineWriter(os.Stdout, filter, logsPretty)
+		if _, err := io.WriteString(lines, logs); err != nil {
+			return err
+		}
+		return lines.Flush()
+	}
 	fmt.Print(logs)
 	return nil
 }
 
+// streamLogs copies the simulation's logs to out, starting from the tail or
+// since point in opts and following new lines until the container exits or
+// ctx is done. Cancellation is how the caller stops following, so it isn't
+// reported as an error.
+func streamLogs(ctx context.Context, client docker.SimulationManager, simulationID string, opts docker.LogOptions, out io.Writer) error {
+	reader, err := client.GetSimulationLogsStream(ctx, simulationID, opts)
+	if err != nil {
+		return fmt.Errorf("failed to get simulation logs: %w", err)
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(out, reader)
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to stream logs: %w", err)
+	}
+	return nil
+}
+
+// validateLogBounds checks --tail and --since before Docker sees them. Both
+// may be given, in which case the smaller window wins, but at least one
+// bound is required so a long-running simulation's full log isn't dumped by
+// accident.
+func validateLogBounds(tail int, since string, sinceStart bool) error {
+	if tail < 0 {
+		return fmt.Errorf("--tail must not be negative")
+	}
+	if tail == 0 && since == "" && !sinceStart {
+		return fmt.Errorf("--tail 0 removes the line limit and requires --since or --since-start")
+	}
+	if since == "" {
+		return nil
+	}
+
+	if _, err := parseExtendedDuration(since); err == nil {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339Nano, since); err == nil {
+		return nil
+	}
+	if _, err := strconv.ParseFloat(since, 64); err == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid --since %q (expected a duration like 10m or 2d, an RFC3339 timestamp, or a Unix timestamp)", since)
+}
+
+// dockerSince converts --since for Docker, which only understands Go
+// durations and timestamps: "2d" is sent as "48h0m0s". Anything else is
+// passed through untouched, so "0" stays the Unix epoch rather than
+// becoming a zero duration.
+func dockerSince(since string) string {
+	if !hasExtendedUnit(since) {
+		return since
+	}
+	d, err := parseExtendedDuration(since)
+	if err != nil {
+		return since
+	}
+	return d.String()
+}
+
 func selectSimulationForLogs(simulations []*models.Simulation) (string, error) {
 	fmt.Printf("\n%s Select a running simulation:\n\n", color.CyanString("▶"))
 
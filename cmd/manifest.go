@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFile is a run --manifest file: several simulations to launch with
+// one command.
+type manifestFile struct {
+	Simulations []manifestEntry `yaml:"simulations"`
+}
+
+// manifestEntry is one simulation in a manifest. Name is a simulation in the
+// config directory, as for "autobox run <name>"; the other fields override
+// the run flags for this simulation only.
+type manifestEntry struct {
+	Name      string            `yaml:"name"`
+	Image     string            `yaml:"image"`
+	Env       map[string]string `yaml:"env"`
+	Volumes   []string          `yaml:"volumes"`
+	DependsOn []string          `yaml:"depends_on"`
+}
+
+// dependencyReadyTimeout bounds how long a manifest launch waits for a
+// simulation that others depend on to be running, or healthy.
+const dependencyReadyTimeout = 10 * time.Minute
+
+// manifestResult is the outcome of launching one manifest entry. Simulation
+// is nil if the launch failed or was skipped.
+type manifestResult struct {
+	Name       string
+	Simulation *models.Simulation
+	Err        error
+}
+
+// validateManifestFlags rejects run flags that name a single simulation or
+// follow its output, since a manifest launches several, detached.
+func validateManifestFlags(args []string) error {
+	if len(args) > 0 || runConfig != "" || runMetricsPath != "" || runName != "" {
+		return fmt.Errorf("--manifest names its own simulations and can't be combined with a simulation name, --config, --metrics or --name")
+	}
+	following := []struct {
+		flag string
+		set  bool
+	}{
+		{"--tee", runTee != ""},
+		{"--stdout-file", runStdoutFile != ""},
+		{"--profile-resources", runProfilePath != ""},
+		{"--detach-after", runDetachAfter > 0},
+		{"--on-complete", runOnComplete != ""},
+		{"--on-failure", runOnFailure != ""},
+	}
+	for _, f := range following {
+		if f.set {
+			return fmt.Errorf("--manifest launches simulations detached and can't be combined with %s", f.flag)
+		}
+	}
+	if runParallel < 0 {
+		return fmt.Errorf("--parallel and --max-parallel must not be negative")
+	}
+	return nil
+}
+
+// parseManifest turns a manifest into one SimulationConfig per entry, each
+// starting from defaults (the run flags), and the launch graph of their
+// depends_on. An entry's env is merged over the defaults' and its volumes
+// are added to theirs, so the config mount the simulation is loaded from
+// stays. Unknown keys, missing names, duplicates and dependency cycles are
+// errors.
+func parseManifest(data []byte, defaults models.SimulationConfig) ([]models.SimulationConfig, []launchNode, error) {
+	var manifest manifestFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("manifest is empty")
+		}
+		return nil, nil, err
+	}
+	if len(manifest.Simulations) == 0 {
+		return nil, nil, fmt.Errorf("manifest lists no simulations")
+	}
+
+	configs := make([]models.SimulationConfig, 0, len(manifest.Simulations))
+	nodes := make([]launchNode, 0, len(manifest.Simulations))
+	for i, entry := range manifest.Simulations {
+		if entry.Name == "" {
+			return nil, nil, fmt.Errorf("simulations[%d]: name is required", i)
+		}
+
+		cfg := defaults
+		cfg.Name = entry.Name
+		if entry.Image != "" {
+			if err := validateImageReference(entry.Image); err != nil {
+				return nil, nil, fmt.Errorf("simulation %q: %w", entry.Name, err)
+			}
+			cfg.Image = entry.Image
+		}
+		cfg.Environment = make(map[string]string, len(defaults.Environment)+len(entry.Env))
+		for k, v := range defaults.Environment {
+			cfg.Environment[k] = v
+		}
+		for k, v := range entry.Env {
+			cfg.Environment[k] = v
+		}
+		cfg.Volumes = append(append([]string{}, defaults.Volumes...), entry.Volumes...)
+
+		configs = append(configs, cfg)
+		nodes = append(nodes, launchNode{Name: entry.Name, DependsOn: entry.DependsOn})
+	}
+
+	if _, err := launchOrder(nodes); err != nil {
+		return nil, nil, err
+	}
+	return configs, nodes, nil
+}
+
+// launchManifestRuns launches configs through the scheduler, at most
+// parallel at a time and each after its dependencies, and returns the
+// result of every config in manifest order. A failure doesn't stop the
+// others, only the simulations that depend on it.
+func launchManifestRuns(ctx context.Context, configs []models.SimulationConfig, nodes []launchNode, parallel int,
+	launch func(ctx context.Context, cfg models.SimulationConfig) (*models.Simulation, error)) ([]manifestResult, error) {
+	index := make(map[string]int, len(configs))
+	results := make([]manifestResult, len(configs))
+	for i, cfg := range configs {
+		index[cfg.Name] = i
+		results[i].Name = cfg.Name
+	}
+
+	var mu sync.Mutex
+	failures, err := scheduleLaunches(ctx, nodes, parallel, func(ctx context.Context, name string) error {
+		i := index[name]
+		sim, err := launch(ctx, configs[i])
+		mu.Lock()
+		results[i].Simulation = sim
+		mu.Unlock()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name, err := range failures {
+		results[index[name]].Err = err
+	}
+	return results, nil
+}
+
+func runFromManifest(ctx context.Context, client *docker.Client, path string, defaults models.SimulationConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+	configs, nodes, err := parseManifest(data, defaults)
+	if err != nil {
+		return fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	// Pull and check each image once, up front, rather than from parallel
+	// launches racing each other.
+	imageErrs := map[string]error{}
+	engineVersions := map[string]string{}
+	for _, cfg := range configs {
+		if _, seen := imageErrs[cfg.Image]; seen {
+			continue
+		}
+		imageErrs[cfg.Image] = ensureImage(ctx, client, cfg.Image)
+		if imageErrs[cfg.Image] == nil && !runSkipVersion {
+			engineVersions[cfg.Image] = checkEngineVersion(ctx, client, cfg.Image)
+		}
+	}
+
+	order, err := launchOrder(nodes)
+	if err != nil {
+		return fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	awaited := dependedOn(nodes)
+
+	fmt.Printf("%s Launching %d simulation(s) from %s...\n", color.YellowString("→"), len(configs), path)
+	fmt.Printf("  Launch order: %s\n", strings.Join(order, ", "))
+
+	// History, hooks and output aren't safe to interleave, so everything
+	// after the launch itself is done one simulation at a time.
+	var mu sync.Mutex
+	results, err := launchManifestRuns(ctx, configs, nodes, runParallel, func(ctx context.Context, cfg models.SimulationConfig) (*models.Simulation, error) {
+		if err := imageErrs[cfg.Image]; err != nil {
+			return nil, fmt.Errorf("image %s is not available: %w", cfg.Image, err)
+		}
+		configSet, err := loadNamedSimulation(cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ConfigPath, cfg.MetricsPath, cfg.ServerPath = namedSimulationPaths(configSet)
+		cfg.EngineVersion = engineVersions[cfg.Image]
+
+		simulation, err := client.LaunchSimulation(ctx, cfg)
+		if err != nil {
+			removeRunFiles(configSet.RunDirectory)
+			return nil, fmt.Errorf("failed to run simulation: %w", err)
+		}
+
+		mu.Lock()
+		warnDigestChange(cfg.Name, cfg.Image, simulation.Config.ImageDigest)
+		recordLaunch(simulation, cfg.Name, cfg.Image)
+		notifyWebhook(ctx, cfg.Webhook, "launch", simulation)
+		fmt.Printf("%s Launched %s (%s)\n", color.GreenString("✓"), cfg.Name, simulation.ID)
+		if runNotify != "" {
+			if _, err := startCompletionNotifier(simulation.ContainerID, runNotify); err != nil {
+				fmt.Fprintf(os.Stderr, "%s %v\n", color.YellowString("⚠"), err)
+			}
+		}
+		mu.Unlock()
+
+		if awaited[cfg.Name] {
+			readyCtx, cancel := context.WithTimeout(ctx, dependencyReadyTimeout)
+			defer cancel()
+			if err := client.WaitUntilReady(readyCtx, simulation.ContainerID); err != nil {
+				return simulation, fmt.Errorf("launched, but not ready for its dependents: %w", err)
+			}
+		}
+		return simulation, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	printManifestSummary(results)
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d simulation(s) failed to launch", failed, len(results))
+	}
+	return nil
+}
+
+// dependedOn returns the names of the simulations that others depend on,
+// which have to be ready before their dependents launch.
+func dependedOn(nodes []launchNode) map[string]bool {
+	names := make(map[string]bool)
+	for _, node := range nodes {
+		for _, dep := range node.DependsOn {
+			names[dep] = true
+		}
+	}
+	return names
+}
+
+func printManifestSummary(results []manifestResult) {
+	fmt.Printf("\n%-30s  %-12s  %s\n", "NAME", "ID", "STATUS")
+	fmt.Println(strings.Repeat("-", 70))
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Printf("%-30s  %-12s  %s\n", truncate(result.Name, 30), "-", color.RedString("failed: %v", result.Err))
+			continue
+		}
+		fmt.Printf("%-30s  %-12s  %s\n", truncate(result.Name, 30), result.Simulation.ID, colorizeStatus(result.Simulation.Status))
+	}
+}
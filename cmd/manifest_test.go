@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+func TestParseManifest(t *testing.T) {
+	defaults := models.SimulationConfig{
+		Image:       "autobox-engine:latest",
+		Environment: map[string]string{"LOG_LEVEL": "info", "REGION": "eu"},
+		Volumes:     []string{"/home/me/.autobox/config:/app/config"},
+		CPULimit:    1.5,
+	}
+	data := []byte(`
+simulations:
+  - name: market
+    image: autobox-engine:v1.2
+    env:
+      LOG_LEVEL: debug
+      WORKERS: 4
+  - name: report
+    volumes: ["/data:/app/data"]
+    depends_on: [market]
+`)
+
+	configs, nodes, err := parseManifest(data, defaults)
+	if err != nil {
+		t.Fatalf("parseManifest() error = %v", err)
+	}
+	if len(configs) != 2 {
+		t.Fatalf("parseManifest() returned %d configs, want 2", len(configs))
+	}
+
+	market, report := configs[0], configs[1]
+	if market.Name != "market" || market.Image != "autobox-engine:v1.2" || market.CPULimit != 1.5 {
+		t.Errorf("market = %+v, want its own name and image and the default CPU limit", market)
+	}
+	wantEnv := map[string]string{"LOG_LEVEL": "debug", "REGION": "eu", "WORKERS": "4"}
+	if !reflect.DeepEqual(market.Environment, wantEnv) {
+		t.Errorf("market env = %v, want %v", market.Environment, wantEnv)
+	}
+	if !reflect.DeepEqual(market.Volumes, defaults.Volumes) {
+		t.Errorf("market volumes = %v, want the defaults", market.Volumes)
+	}
+
+	wantVolumes := []string{"/home/me/.autobox/config:/app/config", "/data:/app/data"}
+	if report.Image != defaults.Image || !reflect.DeepEqual(report.Volumes, wantVolumes) {
+		t.Errorf("report = %+v, want the default image, and its own volumes after the defaults", report)
+	}
+	if !reflect.DeepEqual(defaults.Volumes, []string{"/home/me/.autobox/config:/app/config"}) {
+		t.Errorf("defaults volumes = %v, want them left alone", defaults.Volumes)
+	}
+	if !reflect.DeepEqual(report.Environment, defaults.Environment) {
+		t.Errorf("report env = %v, want the defaults", report.Environment)
+	}
+	if defaults.Environment["LOG_LEVEL"] != "info" {
+		t.Error("parseManifest() modified the default environment")
+	}
+
+	wantNodes := []launchNode{{Name: "market"}, {Name: "report", DependsOn: []string{"market"}}}
+	if !reflect.DeepEqual(nodes, wantNodes) {
+		t.Errorf("nodes = %+v, want %+v", nodes, wantNodes)
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"Empty", "", "manifest is empty"},
+		{"No simulations", "simulations: []", "no simulations"},
+		{"Missing name", "simulations:\n  - image: autobox-engine:latest", "simulations[0]: name is required"},
+		{"Unknown key", "simulations:\n  - name: market\n    imgae: autobox-engine:latest", "field imgae not found"},
+		{"Bad digest", "simulations:\n  - name: market\n    image: autobox-engine@sha256:abc", "invalid image digest"},
+		{"Duplicate", "simulations:\n  - name: market\n  - name: market", "listed more than once"},
+		{"Cycle", "simulations:\n  - name: a\n    depends_on: [b]\n  - name: b\n    depends_on: [a]", "dependency cycle"},
+		{"Unknown dependency", "simulations:\n  - name: a\n    depends_on: [ghost]", `unknown simulation "ghost"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseManifest([]byte(tt.data), models.SimulationConfig{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseManifest() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaunchManifestRuns(t *testing.T) {
+	configs := []models.SimulationConfig{{Name: "db"}, {Name: "market"}, {Name: "report"}, {Name: "climate"}, {Name: "ocean"}}
+	nodes := []launchNode{{Name: "db"}, {Name: "market", DependsOn: []string{"db"}}, {Name: "report", DependsOn: []string{"market"}}, {Name: "climate"}, {Name: "ocean"}}
+
+	var (
+		mu       sync.Mutex
+		launched []string
+		running  int32
+		peak     int32
+	)
+	results, err := launchManifestRuns(context.Background(), configs, nodes, 2, func(ctx context.Context, cfg models.SimulationConfig) (*models.Simulation, error) {
+		now := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if now <= old || atomic.CompareAndSwapInt32(&peak, old, now) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		launched = append(launched, cfg.Name)
+		mu.Unlock()
+		if cfg.Name == "market" {
+			return nil, errors.New("port already allocated")
+		}
+		return &models.Simulation{ID: cfg.Name + "-id", Status: models.StatusRunning}, nil
+	})
+	if err != nil {
+		t.Fatalf("launchManifestRuns() error = %v", err)
+	}
+
+	if peak > 2 {
+		t.Errorf("%d launches ran at once, want at most 2", peak)
+	}
+	for _, name := range launched {
+		if name == "report" {
+			t.Error("report was launched although market, its dependency, failed")
+		}
+	}
+
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"db", "market", "report", "climate", "ocean"}) {
+		t.Errorf("results are in order %v, want manifest order", names)
+	}
+	for _, result := range results {
+		switch result.Name {
+		case "market":
+			if result.Err == nil || !strings.Contains(result.Err.Error(), "port already allocated") {
+				t.Errorf("market error = %v, want the launch error", result.Err)
+			}
+		case "report":
+			if result.Err == nil || !strings.Contains(result.Err.Error(), `dependency "market" failed`) {
+				t.Errorf("report error = %v, want a skipped dependency", result.Err)
+			}
+		default:
+			if result.Err != nil || result.Simulation == nil || result.Simulation.ID != result.Name+"-id" {
+				t.Errorf("%s = %+v, want a launched simulation", result.Name, result)
+			}
+		}
+	}
+}
+
+func TestDependedOn(t *testing.T) {
+	nodes := []launchNode{{Name: "db"}, {Name: "market", DependsOn: []string{"db"}}, {Name: "report", DependsOn: []string{"market", "db"}}}
+	if got, want := dependedOn(nodes), map[string]bool{"db": true, "market": true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dependedOn() = %v, want %v", got, want)
+	}
+}
+
+func TestMaxParallelFlag(t *testing.T) {
+	defer func() {
+		runParallel = 1
+		runCmd.Flags().Lookup("max-parallel").Changed = false
+	}()
+	if err := runCmd.Flags().Set("max-parallel", "3"); err != nil || runParallel != 3 {
+		t.Errorf("--max-parallel 3 gave %d, %v; want the --parallel limit set to 3", runParallel, err)
+	}
+}
@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/Autobox-AI/autobox-cli/internal/docker"
 	"github.com/Autobox-AI/autobox-cli/pkg/models"
@@ -11,30 +17,99 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	metricsWatch    bool
+	metricsInterval time.Duration
+	metricsAll      bool
+	metricsSort     string
+	metricsReverse  bool
+)
+
+// simulationMetrics pairs a metrics snapshot with the simulation it came
+// from, for the combined metrics --all view.
+type simulationMetrics struct {
+	ID      string          `json:"id" yaml:"id"`
+	Name    string          `json:"name" yaml:"name"`
+	Metrics *models.Metrics `json:"metrics" yaml:"metrics"`
+}
+
 var metricsCmd = &cobra.Command{
-	Use:   "metrics [SIMULATION_ID]",
+	Use:   "metrics [SIMULATION_ID|NAME]",
 	Short: "Get metrics for a specific simulation",
 	Long: `Get real-time metrics for a specific Autobox simulation.
 	
 Metrics include CPU usage, memory usage, network I/O, and disk I/O.
 	
+With --watch, metrics are streamed from Docker until the simulation exits or
+Ctrl+C is pressed, and the latest sample is shown every --interval: the table
+is redrawn in place. With --output json each sample is written as one
+compact JSON object per line (NDJSON), including its timestamp, so a script
+can read the stream line by line; --output yaml writes each sample as its own
+"---" document. Streaming measures CPU usage between consecutive samples, so
+it is more accurate than a single snapshot.
+
+With --all, one snapshot per running simulation is shown in a combined table
+(or --output json|yaml|csv). --sort orders it by cpu, mem, net or disk usage,
+highest first; --reverse puts the lowest first.
+
+--output prometheus writes the snapshot in the Prometheus text exposition
+format, with samples such as autobox_cpu_usage_percent{id="...",name="..."},
+for the node exporter's textfile collector or a Pushgateway.
+
 Examples:
   autobox metrics abc123def456
-  autobox metrics abc123def456 --output json`,
-	Args: cobra.ExactArgs(1),
+  autobox metrics abc123def456 --output json
+  autobox metrics abc123def456 --watch
+  autobox metrics abc123def456 --watch --output json --interval 5s | jq -c '.cpu_usage'
+  autobox metrics --all --sort cpu
+  autobox metrics --all --sort mem --output csv
+  autobox metrics --all --output prometheus > /var/lib/node_exporter/textfile/autobox.prom`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if metricsAll {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: runMetrics,
 }
 
+func init() {
+	metricsCmd.Flags().BoolVarP(&metricsAll, "all", "a", false, "Show metrics for every running simulation")
+	metricsCmd.Flags().StringVar(&metricsSort, "sort", "", "With --all, sort by cpu, mem, net or disk (highest first)")
+	metricsCmd.Flags().BoolVar(&metricsReverse, "reverse", false, "With --sort, put the lowest usage first")
+	metricsCmd.Flags().BoolVarP(&metricsWatch, "watch", "w", false, "Continuously sample metrics until the simulation exits")
+	metricsCmd.Flags().Var(newExtendedDuration(2*time.Second, &metricsInterval), "interval", "Sampling interval for --watch")
+}
+
 func runMetrics(cmd *cobra.Command, args []string) error {
+	if !metricsAll && (metricsSort != "" || metricsReverse) {
+		return fmt.Errorf("--sort and --reverse require --all")
+	}
+	if metricsAll && metricsWatch {
+		return fmt.Errorf("--watch cannot be combined with --all")
+	}
+
 	ctx := context.Background()
-	simulationID := args[0]
 
-	client, err := docker.NewClient()
+	client, err := newSimulationManager()
 	if err != nil {
 		return fmt.Errorf("failed to create Docker client: %w", err)
 	}
 	defer client.Close()
 
+	if metricsAll {
+		return allMetrics(ctx, client)
+	}
+
+	simulationID, err := resolveSimulationID(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if metricsWatch {
+		return watchMetrics(ctx, client, simulationID)
+	}
+
 	metrics, err := client.GetSimulationMetrics(ctx, simulationID)
 	if err != nil {
 		return fmt.Errorf("failed to get simulation metrics: %w", err)
@@ -45,38 +120,264 @@ func runMetrics(cmd *cobra.Command, args []string) error {
 		return outputJSON(metrics)
 	case "yaml":
 		return outputYAML(metrics)
+	case "csv":
+		return outputCSV(metrics)
+	case "prometheus":
+		// Label the samples with the name as well, as metrics --all does.
+		sim, err := client.InspectSimulation(ctx, simulationID)
+		if err != nil {
+			return fmt.Errorf("failed to inspect simulation: %w", err)
+		}
+		return writePrometheus(os.Stdout, []simulationMetrics{{ID: sim.ID, Name: sim.Name, Metrics: metrics}})
 	default:
 		return outputMetricsTable(metrics)
 	}
 }
 
+func watchMetrics(ctx context.Context, client docker.SimulationManager, simulationID string) error {
+	switch output {
+	case "json", "yaml", "table":
+	default:
+		return fmt.Errorf("--watch supports --output table, json or yaml")
+	}
+	if metricsInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	running, err := client.IsSimulationRunning(ctx, simulationID)
+	if err != nil {
+		return fmt.Errorf("failed to get simulation status: %w", err)
+	}
+	if !running {
+		return fmt.Errorf("simulation %s is not running", shortID(simulationID))
+	}
+
+	samples, errs := client.StreamSimulationMetrics(ctx, simulationID)
+
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+
+	// Docker samples about once a second; show the first sample right away,
+	// then the most recent one every interval.
+	var latest *models.Metrics
+	shown := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case m, ok := <-samples:
+			if !ok {
+				if err := <-errs; err != nil {
+					return fmt.Errorf("failed to stream simulation metrics: %w", err)
+				}
+				return nil
+			}
+			latest = m
+			if shown {
+				continue
+			}
+		case <-ticker.C:
+			if latest == nil {
+				continue
+			}
+		}
+
+		if err := showMetricsSample(simulationID, latest); err != nil {
+			return err
+		}
+		shown = true
+	}
+}
+
+// showMetricsSample writes one --watch sample: a record in the stream for
+// json and yaml, or a redrawn table on a terminal.
+func showMetricsSample(simulationID string, metrics *models.Metrics) error {
+	if output != "table" {
+		return writeMetricsRecord(os.Stdout, output, metrics)
+	}
+
+	width, isTerminal := terminalWidth(os.Stdout)
+	var frame bytes.Buffer
+	fmt.Fprintln(&frame, watchHeader("autobox metrics "+shortID(simulationID), metricsInterval, time.Now(), width))
+	if err := writeMetricsTable(&frame, metrics); err != nil {
+		return err
+	}
+	if !isTerminal {
+		_, err := os.Stdout.Write(frame.Bytes())
+		return err
+	}
+	_, err := io.WriteString(os.Stdout, clearScreen+clipLines(frame.String(), width))
+	return err
+}
+
+// writeMetricsRecord appends one sample to a --watch json or yaml stream.
+func writeMetricsRecord(w io.Writer, format string, metrics *models.Metrics) error {
+	if format == "yaml" {
+		return outputYAMLDocuments(w, []*models.Metrics{metrics})
+	}
+	return writeJSONLine(w, metrics)
+}
+
+func allMetrics(ctx context.Context, client docker.SimulationManager) error {
+	switch metricsSort {
+	case "", "cpu", "mem", "net", "disk":
+	default:
+		return fmt.Errorf("invalid --sort %q (expected cpu, mem, net or disk)", metricsSort)
+	}
+
+	simulations, err := client.ListSimulations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list simulations: %w", err)
+	}
+
+	rows := []simulationMetrics{}
+	for _, sim := range filterRunningSimulations(simulations) {
+		metrics, err := client.GetSimulationMetrics(ctx, sim.ContainerID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Skipping %s: %v\n", color.YellowString("⚠"), sim.ID, err)
+			continue
+		}
+		rows = append(rows, simulationMetrics{ID: sim.ID, Name: sim.Name, Metrics: metrics})
+	}
+
+	if metricsSort != "" {
+		sortSimulationMetrics(rows, metricsSort, metricsReverse)
+	}
+
+	switch output {
+	case "json":
+		return outputJSON(rows)
+	case "yaml":
+		return outputYAML(rows)
+	case "csv":
+		return outputCSV(rows)
+	case "prometheus":
+		return writePrometheus(os.Stdout, rows)
+	default:
+		return outputAllMetricsTable(rows)
+	}
+}
+
+func metricsSortValue(m *models.Metrics, key string) float64 {
+	switch key {
+	case "cpu":
+		return m.CPUUsage
+	case "mem":
+		return m.MemoryUsage
+	case "net":
+		return float64(m.NetworkIO.BytesReceived + m.NetworkIO.BytesTransmitted)
+	case "disk":
+		return float64(m.DiskIO.BytesRead + m.DiskIO.BytesWritten)
+	default:
+		return 0
+	}
+}
+
+// sortSimulationMetrics orders rows by key, highest first unless reverse is
+// set. Ties keep a stable order by name so repeated runs render the same.
+func sortSimulationMetrics(rows []simulationMetrics, key string, reverse bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := metricsSortValue(rows[i].Metrics, key), metricsSortValue(rows[j].Metrics, key)
+		if a == b {
+			return rows[i].Name < rows[j].Name
+		}
+		if reverse {
+			return a < b
+		}
+		return a > b
+	})
+}
+
+func outputAllMetricsTable(rows []simulationMetrics) error {
+	if len(rows) == 0 {
+		fmt.Println(color.YellowString("No running simulations found"))
+		return nil
+	}
+
+	fmt.Printf("\n%s Metrics for %d simulation(s)\n\n", color.CyanString("▶"), len(rows))
+	fmt.Printf("%-12s  %-30s  %-8s  %-8s  %-21s  %-21s\n", "ID", "NAME", "CPU", "MEM", "NET RX/TX", "DISK R/W")
+	fmt.Println(strings.Repeat("-", 110))
+
+	for _, row := range rows {
+		printMetricsRow(color.CyanString(row.ID), truncate(row.Name, 30), row.Metrics)
+	}
+
+	fmt.Println(strings.Repeat("-", 110))
+	printMetricsRow("TOTAL", fmt.Sprintf("%d simulation(s)", len(rows)), totalMetrics(rows))
+	fmt.Println()
+
+	return nil
+}
+
+func printMetricsRow(id, name string, m *models.Metrics) {
+	fmt.Printf("%-12s  %-30s  %-8s  %-8s  %-21s  %-21s\n",
+		id,
+		name,
+		fmt.Sprintf("%.2f%%", m.CPUUsage),
+		fmt.Sprintf("%.2f%%", m.MemoryUsage),
+		formatBytes(m.NetworkIO.BytesReceived)+" / "+formatBytes(m.NetworkIO.BytesTransmitted),
+		formatBytes(m.DiskIO.BytesRead)+" / "+formatBytes(m.DiskIO.BytesWritten),
+	)
+}
+
+// totalMetrics aggregates rows for the TOTAL line: network and disk I/O are
+// summed, while CPU and memory, being percentages, are averaged.
+func totalMetrics(rows []simulationMetrics) *models.Metrics {
+	total := &models.Metrics{}
+	if len(rows) == 0 {
+		return total
+	}
+
+	for _, row := range rows {
+		m := row.Metrics
+		total.CPUUsage += m.CPUUsage
+		total.MemoryUsage += m.MemoryUsage
+		total.NetworkIO.BytesReceived += m.NetworkIO.BytesReceived
+		total.NetworkIO.BytesTransmitted += m.NetworkIO.BytesTransmitted
+		total.NetworkIO.PacketsReceived += m.NetworkIO.PacketsReceived
+		total.NetworkIO.PacketsTransmitted += m.NetworkIO.PacketsTransmitted
+		total.DiskIO.BytesRead += m.DiskIO.BytesRead
+		total.DiskIO.BytesWritten += m.DiskIO.BytesWritten
+	}
+	total.CPUUsage /= float64(len(rows))
+	total.MemoryUsage /= float64(len(rows))
+	return total
+}
+
 func outputMetricsTable(metrics *models.Metrics) error {
-	fmt.Printf("\n%s Simulation Metrics\n", color.CyanString("▶"))
-	fmt.Println(strings.Repeat("─", 50))
+	return writeMetricsTable(os.Stdout, metrics)
+}
 
-	fmt.Printf("\n%s Resource Usage\n", color.YellowString("→"))
-	fmt.Printf("  %-20s: %s\n", "CPU Usage", formatPercentage(metrics.CPUUsage))
-	fmt.Printf("  %-20s: %s\n", "Memory Usage", formatPercentage(metrics.MemoryUsage))
+func writeMetricsTable(w io.Writer, metrics *models.Metrics) error {
+	fmt.Fprintf(w, "\n%s Simulation Metrics\n", color.CyanString("▶"))
+	fmt.Fprintln(w, strings.Repeat("─", 50))
 
-	fmt.Printf("\n%s Network I/O\n", color.YellowString("→"))
-	fmt.Printf("  %-20s: %s\n", "Bytes Received", formatBytes(metrics.NetworkIO.BytesReceived))
-	fmt.Printf("  %-20s: %s\n", "Bytes Transmitted", formatBytes(metrics.NetworkIO.BytesTransmitted))
-	fmt.Printf("  %-20s: %d\n", "Packets Received", metrics.NetworkIO.PacketsReceived)
-	fmt.Printf("  %-20s: %d\n", "Packets Transmitted", metrics.NetworkIO.PacketsTransmitted)
+	fmt.Fprintf(w, "\n%s Resource Usage\n", color.YellowString("→"))
+	fmt.Fprintf(w, "  %-20s: %s\n", "CPU Usage", formatPercentage(metrics.CPUUsage))
+	fmt.Fprintf(w, "  %-20s: %s\n", "Memory Usage", formatPercentage(metrics.MemoryUsage))
 
-	fmt.Printf("\n%s Disk I/O\n", color.YellowString("→"))
-	fmt.Printf("  %-20s: %s\n", "Bytes Read", formatBytes(metrics.DiskIO.BytesRead))
-	fmt.Printf("  %-20s: %s\n", "Bytes Written", formatBytes(metrics.DiskIO.BytesWritten))
+	fmt.Fprintf(w, "\n%s Network I/O\n", color.YellowString("→"))
+	fmt.Fprintf(w, "  %-20s: %s\n", "Bytes Received", formatBytes(metrics.NetworkIO.BytesReceived))
+	fmt.Fprintf(w, "  %-20s: %s\n", "Bytes Transmitted", formatBytes(metrics.NetworkIO.BytesTransmitted))
+	fmt.Fprintf(w, "  %-20s: %d\n", "Packets Received", metrics.NetworkIO.PacketsReceived)
+	fmt.Fprintf(w, "  %-20s: %d\n", "Packets Transmitted", metrics.NetworkIO.PacketsTransmitted)
+
+	fmt.Fprintf(w, "\n%s Disk I/O\n", color.YellowString("→"))
+	fmt.Fprintf(w, "  %-20s: %s\n", "Bytes Read", formatBytes(metrics.DiskIO.BytesRead))
+	fmt.Fprintf(w, "  %-20s: %s\n", "Bytes Written", formatBytes(metrics.DiskIO.BytesWritten))
 
 	if len(metrics.Custom) > 0 {
-		fmt.Printf("\n%s Custom Metrics\n", color.YellowString("→"))
+		fmt.Fprintf(w, "\n%s Custom Metrics\n", color.YellowString("→"))
 		for key, value := range metrics.Custom {
-			fmt.Printf("  %-20s: %v\n", key, value)
+			fmt.Fprintf(w, "  %-20s: %v\n", key, value)
 		}
 	}
 
-	fmt.Printf("\n%s Timestamp: %s\n", color.WhiteString("•"), metrics.Timestamp.Format("2006-01-02 15:04:05"))
-	fmt.Println()
+	fmt.Fprintf(w, "\n%s Timestamp: %s\n", color.WhiteString("•"), metrics.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(w)
 
 	return nil
 }
@@ -107,4 +408,4 @@ func formatBytes(bytes uint64) string {
 	default:
 		return fmt.Sprintf("%d B", bytes)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the ~/.autobox layout to the current schema version",
+	Long: `Upgrade the on-disk ~/.autobox layout to the schema version this CLI expects.
+
+The version is recorded as the top-level version key of autobox.yaml. Each
+action (moved directory, skipped file, renamed settings key) is printed as it
+happens. Existing files are never overwritten, and autobox.yaml is edited in
+place with its comments kept, so running migrate more than once is safe.
+
+The move from the legacy ~/.autobox/configs/ directory to config/ also runs
+automatically before any other command.
+
+Examples:
+  autobox migrate`,
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	current, err := config.LayoutVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read layout version: %w", err)
+	}
+
+	if current == config.SchemaVersion {
+		fmt.Printf("%s Already at schema version %d\n", color.GreenString("✓"), current)
+		return nil
+	}
+
+	err = config.Migrate(func(action string) {
+		fmt.Printf("%s %s\n", color.YellowString("→"), action)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+
+	fmt.Printf("%s Migrated from schema version %d to %d\n", color.GreenString("✓"), current, config.SchemaVersion)
+	return nil
+}
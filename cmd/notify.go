@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/Autobox-AI/autobox-cli/internal/webhook"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+// completionEvent is the X-Autobox-Event of a --notify request.
+const completionEvent = "complete"
+
+// completionPayload is the body POSTed by --notify once a simulation has
+// finished. exit_code is null when the engine reported a result without
+// its container exiting.
+type completionPayload struct {
+	ID              string                  `json:"id"`
+	Name            string                  `json:"name"`
+	Status          models.SimulationStatus `json:"status"`
+	ExitCode        *int                    `json:"exit_code"`
+	DurationSeconds float64                 `json:"duration_seconds"`
+	StartedAt       *time.Time              `json:"started_at,omitempty"`
+	FinishedAt      *time.Time              `json:"finished_at,omitempty"`
+}
+
+func newCompletionPayload(sim *models.Simulation) completionPayload {
+	payload := completionPayload{
+		ID:         sim.ID,
+		Name:       sim.Name,
+		Status:     sim.Status,
+		ExitCode:   sim.ExitCode,
+		StartedAt:  sim.StartedAt,
+		FinishedAt: sim.FinishedAt,
+	}
+	if sim.StartedAt != nil && sim.FinishedAt != nil && !sim.StartedAt.IsZero() && sim.FinishedAt.After(*sim.StartedAt) {
+		payload.DurationSeconds = sim.FinishedAt.Sub(*sim.StartedAt).Seconds()
+	}
+	return payload
+}
+
+// notifyCompletion POSTs a finished simulation to target, retrying with
+// backoff as set by webhook.timeout and webhook.retries.
+func notifyCompletion(ctx context.Context, target string, sim *models.Simulation) error {
+	settings := config.Get().Webhook
+	notifier := webhook.New(target, settings.Timeout, settings.Retries)
+	return notifier.Notify(ctx, completionEvent, newCompletionPayload(sim))
+}
+
+// startCompletionNotifier runs "autobox wait ID --notify URL" in the
+// background, so a detached run is still reported when it finishes. The
+// process outlives this one, and the terminal it was started from; its PID
+// is returned.
+func startCompletionNotifier(simulationID, target string) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the autobox executable: %w", err)
+	}
+
+	args := []string{"wait", simulationID, "--notify", target}
+	if cfgFile != "" {
+		args = append(args, "--config", cfgFile)
+	}
+	// The notifier must not hold on to this terminal: it gets no stdio of
+	// its own and runs outside the terminal's process group.
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = devNull, devNull, devNull
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start the completion notifier: %w", err)
+	}
+	pid := cmd.Process.Pid
+	return pid, cmd.Process.Release()
+}
+
+// completionTracker detects simulations that finish between polls. Only a
+// simulation seen unfinished is reported, so one that had already finished
+// when tracking started is not.
+type completionTracker struct {
+	unfinished map[string]bool
+}
+
+func newCompletionTracker() *completionTracker {
+	return &completionTracker{unfinished: map[string]bool{}}
+}
+
+// observe records sim's latest state and reports whether it has just
+// finished.
+func (t *completionTracker) observe(sim *models.Simulation) bool {
+	if !isTerminalStatus(sim.Status) {
+		t.unfinished[sim.ContainerID] = true
+		return false
+	}
+	if !t.unfinished[sim.ContainerID] {
+		return false
+	}
+	delete(t.unfinished, sim.ContainerID)
+	return true
+}
+
+// tracking reports whether sim was unfinished when last observed.
+func (t *completionTracker) tracking(sim *models.Simulation) bool {
+	return t.unfinished[sim.ContainerID]
+}
+
+// forget drops simulations that are no longer listed, such as ones removed
+// while running.
+func (t *completionTracker) forget(simulations []*models.Simulation) {
+	listed := make(map[string]bool, len(simulations))
+	for _, sim := range simulations {
+		listed[sim.ContainerID] = true
+	}
+	for id := range t.unfinished {
+		if !listed[id] {
+			delete(t.unfinished, id)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+func TestCompletionPayloadJSON(t *testing.T) {
+	started := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	finished := started.Add(90 * time.Second)
+	exitCode := 3
+
+	tests := []struct {
+		name string
+		sim  *models.Simulation
+		want string
+	}{
+		{
+			name: "Exited",
+			sim: &models.Simulation{ID: "abc123def456", Name: "gift_choice", Status: models.StatusFailed,
+				StartedAt: &started, FinishedAt: &finished, ExitCode: &exitCode},
+			want: `{"id":"abc123def456","name":"gift_choice","status":"failed","exit_code":3,"duration_seconds":90,` +
+				`"started_at":"2024-01-15T14:30:00Z","finished_at":"2024-01-15T14:31:30Z"}`,
+		},
+		{
+			name: "Reported by the engine before exiting",
+			sim:  &models.Simulation{ID: "abc123def456", Name: "gift_choice", Status: models.StatusCompleted, StartedAt: &started},
+			want: `{"id":"abc123def456","name":"gift_choice","status":"completed","exit_code":null,"duration_seconds":0,` +
+				`"started_at":"2024-01-15T14:30:00Z"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(newCompletionPayload(tt.sim))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("payload = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionTracker(t *testing.T) {
+	tracker := newCompletionTracker()
+	sim := func(id string, status models.SimulationStatus) *models.Simulation {
+		return &models.Simulation{ContainerID: id, Status: status}
+	}
+
+	if tracker.observe(sim("done", models.StatusCompleted)) {
+		t.Error("observe() reported a simulation that was already finished")
+	}
+	if tracker.observe(sim("a", models.StatusRunning)) || tracker.observe(sim("b", models.StatusPaused)) {
+		t.Error("observe() reported an unfinished simulation")
+	}
+	if !tracker.tracking(sim("a", models.StatusRunning)) {
+		t.Error("tracking() = false for a running simulation")
+	}
+
+	if !tracker.observe(sim("a", models.StatusFailed)) {
+		t.Error("observe() didn't report a simulation that failed")
+	}
+	if tracker.observe(sim("a", models.StatusFailed)) {
+		t.Error("observe() reported the same failure twice")
+	}
+
+	tracker.forget([]*models.Simulation{sim("a", models.StatusFailed)})
+	if tracker.observe(sim("b", models.StatusStopped)) {
+		t.Error("observe() reported a simulation that was removed while unfinished")
+	}
+}
@@ -1,26 +1,165 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Autobox-AI/autobox-cli/pkg/models"
 	"github.com/fatih/color"
+	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
 func outputJSON(data interface{}) error {
+	indent, err := jsonIndent(jsonIndentWidth, jsonIndentChar)
+	if err != nil {
+		return err
+	}
 	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
+	encoder.SetIndent("", indent)
 	return encoder.Encode(data)
 }
 
+// jsonIndent builds the indent string for --json-indent and
+// --json-indent-char. A width of 0 gives compact output.
+func jsonIndent(width int, char string) (string, error) {
+	if width < 0 {
+		return "", fmt.Errorf("--json-indent must not be negative")
+	}
+	switch char {
+	case "space":
+		return strings.Repeat(" ", width), nil
+	case "tab":
+		return strings.Repeat("\t", width), nil
+	default:
+		return "", fmt.Errorf("invalid --json-indent-char %q (expected space or tab)", char)
+	}
+}
+
+// writeJSONLine writes data as a single compact line, for newline-delimited
+// streams that consumers read one record at a time.
+func writeJSONLine(w io.Writer, data interface{}) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
 func outputYAML(data interface{}) error {
 	encoder := yaml.NewEncoder(os.Stdout)
 	encoder.SetIndent(2)
 	return encoder.Encode(data)
 }
 
+// outputYAMLDocuments writes each item as its own "---"-prefixed YAML
+// document, for tools that consume multi-document streams. Documents are
+// indented like outputYAML's.
+func outputYAMLDocuments[T any](w io.Writer, items []T) error {
+	for _, item := range items {
+		if _, err := fmt.Fprint(w, "---\n"); err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// supportsCSV reports whether cmd can render --output csv. Only commands that
+// print simulations or metrics have a flat shape to write.
+func supportsCSV(cmd *cobra.Command) bool {
+	switch cmd {
+	case listCmd, statusCmd, metricsCmd:
+		return true
+	}
+	return false
+}
+
+func outputCSV(data interface{}) error {
+	return writeCSV(os.Stdout, data)
+}
+
+// writeCSV flattens simulations or metrics into a header row followed by one
+// record per item.
+func writeCSV(w io.Writer, data interface{}) error {
+	writer := csv.NewWriter(w)
+	switch v := data.(type) {
+	case []*models.Simulation:
+		writer.Write(simulationCSVHeader)
+		for _, sim := range v {
+			writer.Write(simulationCSVRecord(sim))
+		}
+	case *models.Simulation:
+		writer.Write(simulationCSVHeader)
+		writer.Write(simulationCSVRecord(v))
+	case *models.Metrics:
+		writer.Write(metricsCSVHeader)
+		writer.Write(metricsCSVRecord(v))
+	case []simulationMetrics:
+		writer.Write(append([]string{"id", "name"}, metricsCSVHeader...))
+		for _, row := range v {
+			writer.Write(append([]string{row.ID, row.Name}, metricsCSVRecord(row.Metrics)...))
+		}
+	default:
+		return fmt.Errorf("csv output is not supported for %T", data)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+var simulationCSVHeader = []string{"id", "name", "status", "container_id", "container_name", "image", "created_at", "started_at", "finished_at", "exit_code", "restart_count"}
+
+func simulationCSVRecord(sim *models.Simulation) []string {
+	exitCode := ""
+	if sim.ExitCode != nil {
+		exitCode = strconv.Itoa(*sim.ExitCode)
+	}
+	return []string{
+		sim.ID,
+		sim.Name,
+		string(sim.Status),
+		sim.ContainerID,
+		sim.ContainerName,
+		sim.Config.Image,
+		csvTime(&sim.CreatedAt),
+		csvTime(sim.StartedAt),
+		csvTime(sim.FinishedAt),
+		exitCode,
+		strconv.Itoa(sim.RestartCount),
+	}
+}
+
+var metricsCSVHeader = []string{"cpu_usage", "memory_usage", "net_rx_bytes", "net_tx_bytes", "disk_read_bytes", "disk_write_bytes", "timestamp"}
+
+func metricsCSVRecord(m *models.Metrics) []string {
+	return []string{
+		strconv.FormatFloat(m.CPUUsage, 'f', 2, 64),
+		strconv.FormatFloat(m.MemoryUsage, 'f', 2, 64),
+		strconv.FormatUint(m.NetworkIO.BytesReceived, 10),
+		strconv.FormatUint(m.NetworkIO.BytesTransmitted, 10),
+		strconv.FormatUint(m.DiskIO.BytesRead, 10),
+		strconv.FormatUint(m.DiskIO.BytesWritten, 10),
+		csvTime(&m.Timestamp),
+	}
+}
+
+// csvTime formats t as RFC 3339, leaving the cell empty when it is unset.
+func csvTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 func truncate(s string, max int) string {
 	if len(s) <= max {
 		return s
@@ -28,6 +167,29 @@ func truncate(s string, max int) string {
 	return s[:max-3] + "..."
 }
 
+// shortID abbreviates a container ID the way docker ps does, leaving IDs that
+// are already short untouched.
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// formatCommand joins args for display, quoting any that would otherwise be
+// ambiguous when copied back into a shell.
+func formatCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			quoted[i] = strconv.Quote(arg)
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
 func colorizeStatus(status models.SimulationStatus) string {
 	switch status {
 	case models.StatusRunning:
@@ -38,6 +200,8 @@ func colorizeStatus(status models.SimulationStatus) string {
 		return color.RedString(string(status))
 	case models.StatusStopped:
 		return color.YellowString(string(status))
+	case models.StatusPaused:
+		return color.MagentaString(string(status))
 	default:
 		return string(status)
 	}
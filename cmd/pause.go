@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause SIMULATION_ID|NAME",
+	Short: "Freeze a running simulation",
+	Long: `Freeze every process in a running simulation without stopping it, for
+example to inspect host state. The simulation keeps its memory and carries on
+where it left off after 'autobox unpause'.
+
+Examples:
+  autobox pause abc123def456`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPauseOrUnpause(args[0], "Pausing", "paused", (*docker.Client).PauseSimulation)
+	},
+}
+
+var unpauseCmd = &cobra.Command{
+	Use:   "unpause SIMULATION_ID|NAME",
+	Short: "Resume a paused simulation",
+	Long: `Resume a simulation frozen with 'autobox pause'.
+
+Examples:
+  autobox unpause abc123def456`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPauseOrUnpause(args[0], "Resuming", "resumed", (*docker.Client).UnpauseSimulation)
+	},
+}
+
+func runPauseOrUnpause(ref, action, done string, apply func(*docker.Client, context.Context, string) error) error {
+	ctx := context.Background()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	simulationID, err := resolveSimulationID(ctx, client, ref)
+	if err != nil {
+		return err
+	}
+
+	lock, err := lockSimulation(ctx, client, simulationID)
+	if err != nil {
+		if docker.IsNotFound(err) {
+			return fmt.Errorf("simulation %s not found", ref)
+		}
+		return err
+	}
+	defer lock.Release()
+
+	fmt.Printf("%s %s simulation %s...\n", color.YellowString("→"), action, ref)
+	if err := apply(client, ctx, simulationID); err != nil {
+		return err
+	}
+
+	sim, err := client.InspectSimulation(ctx, simulationID)
+	if err != nil {
+		return fmt.Errorf("failed to get simulation status: %w", err)
+	}
+	fmt.Printf("%s Simulation %s %s (status: %s)\n",
+		color.GreenString("✓"), color.CyanString(sim.ID), done, colorizeStatus(sim.Status))
+	return nil
+}
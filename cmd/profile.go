@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+)
+
+// profileInterval is how often --profile-resources samples the container.
+const profileInterval = 2 * time.Second
+
+// profileSummary aggregates the samples written by --profile-resources.
+// Network and disk counters are cumulative in Docker, so the totals are
+// those of the last sample.
+type profileSummary struct {
+	Samples     int
+	PeakCPU     float64
+	PeakMemory  float64
+	cpuTotal    float64
+	First, Last time.Time
+	NetworkIO   models.NetworkStats
+	DiskIO      models.DiskStats
+}
+
+func (s *profileSummary) add(m *models.Metrics) {
+	s.Samples++
+	if s.Samples == 1 {
+		s.First = m.Timestamp
+	}
+	s.Last = m.Timestamp
+	s.PeakCPU = max(s.PeakCPU, m.CPUUsage)
+	s.PeakMemory = max(s.PeakMemory, m.MemoryUsage)
+	s.cpuTotal += m.CPUUsage
+	s.NetworkIO = m.NetworkIO
+	s.DiskIO = m.DiskIO
+}
+
+func (s *profileSummary) AverageCPU() float64 {
+	if s.Samples == 0 {
+		return 0
+	}
+	return s.cpuTotal / float64(s.Samples)
+}
+
+// resourceProfiler samples a container's metrics in the background and
+// appends them to a JSONL file, one models.Metrics object per line.
+type resourceProfiler struct {
+	file    *os.File
+	cancel  context.CancelFunc
+	done    chan struct{}
+	mu      sync.Mutex
+	summary profileSummary
+	err     error
+}
+
+func startResourceProfiler(ctx context.Context, client *docker.Client, containerID, path string, interval time.Duration) (*resourceProfiler, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create profile file: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p := &resourceProfiler{file: file, cancel: cancel, done: make(chan struct{})}
+	go p.run(ctx, client, containerID, interval)
+	return p, nil
+}
+
+func (p *resourceProfiler) run(ctx context.Context, client *docker.Client, containerID string, interval time.Duration) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	encoder := json.NewEncoder(p.file)
+	for {
+		running, err := client.IsSimulationRunning(ctx, containerID)
+		if ctx.Err() != nil || (err == nil && !running) {
+			return
+		}
+
+		// A failed sample, e.g. while the container is exiting, is skipped;
+		// the caller's wait reports how the simulation finished.
+		if metrics, err := client.GetSimulationMetrics(ctx, containerID); err == nil {
+			if err := encoder.Encode(metrics); err != nil {
+				p.fail(fmt.Errorf("failed to write profile: %w", err))
+				return
+			}
+			p.mu.Lock()
+			p.summary.add(metrics)
+			p.mu.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *resourceProfiler) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// Stop ends sampling, closes the file and returns what was collected.
+func (p *resourceProfiler) Stop() (profileSummary, error) {
+	p.cancel()
+	<-p.done
+
+	closeErr := p.file.Close()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.summary, p.err
+	}
+	if closeErr != nil {
+		return p.summary, fmt.Errorf("failed to write profile: %w", closeErr)
+	}
+	return p.summary, nil
+}
+
+func printProfileSummary(path string, summary profileSummary) {
+	fmt.Printf("\n%s Resource Profile\n", color.CyanString("▶"))
+	fmt.Println(strings.Repeat("─", 50))
+	if summary.Samples == 0 {
+		fmt.Println(color.YellowString("  No samples collected; the simulation may have exited too quickly"))
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("  %-20s: %d over %s\n", "Samples", summary.Samples, formatDuration(summary.Last.Sub(summary.First)))
+	fmt.Printf("  %-20s: %.2f%% (average %.2f%%)\n", "Peak CPU", summary.PeakCPU, summary.AverageCPU())
+	fmt.Printf("  %-20s: %.2f%%\n", "Peak Memory", summary.PeakMemory)
+	fmt.Printf("  %-20s: %s / %s\n", "Network RX/TX",
+		formatBytes(summary.NetworkIO.BytesReceived), formatBytes(summary.NetworkIO.BytesTransmitted))
+	fmt.Printf("  %-20s: %s / %s\n", "Disk Read/Write",
+		formatBytes(summary.DiskIO.BytesRead), formatBytes(summary.DiskIO.BytesWritten))
+	fmt.Printf("  %-20s: %s\n", "Samples File", path)
+	fmt.Println()
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+// prometheusMetric is one metric family in the exposition output.
+type prometheusMetric struct {
+	name  string
+	kind  string
+	help  string
+	value func(m *models.Metrics) float64
+}
+
+var prometheusMetrics = []prometheusMetric{
+	{"autobox_cpu_usage_percent", "gauge", "CPU usage of the simulation container, in percent.",
+		func(m *models.Metrics) float64 { return m.CPUUsage }},
+	{"autobox_memory_usage_percent", "gauge", "Memory usage of the simulation container, in percent of its limit.",
+		func(m *models.Metrics) float64 { return m.MemoryUsage }},
+	{"autobox_network_receive_bytes_total", "counter", "Bytes received by the simulation container.",
+		func(m *models.Metrics) float64 { return float64(m.NetworkIO.BytesReceived) }},
+	{"autobox_network_transmit_bytes_total", "counter", "Bytes sent by the simulation container.",
+		func(m *models.Metrics) float64 { return float64(m.NetworkIO.BytesTransmitted) }},
+	{"autobox_network_receive_packets_total", "counter", "Packets received by the simulation container.",
+		func(m *models.Metrics) float64 { return float64(m.NetworkIO.PacketsReceived) }},
+	{"autobox_network_transmit_packets_total", "counter", "Packets sent by the simulation container.",
+		func(m *models.Metrics) float64 { return float64(m.NetworkIO.PacketsTransmitted) }},
+	{"autobox_disk_read_bytes_total", "counter", "Bytes read from disk by the simulation container.",
+		func(m *models.Metrics) float64 { return float64(m.DiskIO.BytesRead) }},
+	{"autobox_disk_written_bytes_total", "counter", "Bytes written to disk by the simulation container.",
+		func(m *models.Metrics) float64 { return float64(m.DiskIO.BytesWritten) }},
+	{"autobox_metrics_timestamp_seconds", "gauge", "When the sample was taken, as a Unix timestamp.",
+		func(m *models.Metrics) float64 { return float64(m.Timestamp.UnixMilli()) / 1000 }},
+}
+
+// writePrometheus renders metrics snapshots in the Prometheus text
+// exposition format, one family per metric with a sample for each
+// simulation. Samples carry no timestamp, which the node exporter's
+// textfile collector rejects; the sample time is its own gauge instead.
+func writePrometheus(w io.Writer, rows []simulationMetrics) error {
+	var b strings.Builder
+	for _, metric := range prometheusMetrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n", metric.name, metric.help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", metric.name, metric.kind)
+		for _, row := range rows {
+			fmt.Fprintf(&b, "%s{id=%s,name=%s} %s\n", metric.name,
+				prometheusLabel(row.ID), prometheusLabel(row.Name),
+				strconv.FormatFloat(metric.value(row.Metrics), 'f', -1, 64))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// prometheusLabel quotes a label value, escaping the backslashes, quotes and
+// newlines that the exposition format requires.
+func prometheusLabel(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return `"` + value + `"`
+}
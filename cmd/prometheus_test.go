@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	rows := []simulationMetrics{
+		{ID: "abc123def456", Name: "gift_choice", Metrics: &models.Metrics{
+			CPUUsage:    12.5,
+			MemoryUsage: 40.25,
+			NetworkIO:   models.NetworkStats{BytesReceived: 1024, BytesTransmitted: 2048, PacketsReceived: 10, PacketsTransmitted: 20},
+			DiskIO:      models.DiskStats{BytesRead: 4096, BytesWritten: 8192},
+			Timestamp:   time.Date(2024, 1, 15, 14, 30, 0, 500_000_000, time.UTC),
+		}},
+		{ID: "fed987cba654", Name: `say "hi" \ bye`, Metrics: &models.Metrics{
+			CPUUsage:  0,
+			Timestamp: time.Date(2024, 1, 15, 14, 30, 1, 0, time.UTC),
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := writePrometheus(&buf, rows); err != nil {
+		t.Fatalf("writePrometheus() error = %v", err)
+	}
+
+	want, err := os.ReadFile(filepath.Join("testdata", "metrics.prom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != string(want) {
+		t.Errorf("writePrometheus() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneForce   bool
+	pruneUntil   time.Duration
+	pruneStatus  string
+	pruneVolumes bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove finished simulations",
+	Long: `Remove every completed, failed or stopped simulation container. Running,
+paused and pending simulations are never touched.
+
+--until only removes simulations that finished more than that long ago, and
+--status only those in one state. As with terminate, anonymous volumes go with
+the containers and named volumes are kept unless --volumes is given.
+
+Examples:
+  autobox prune
+  autobox prune --until 7d --force
+  autobox prune --status failed
+  autobox prune --volumes`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch models.SimulationStatus(pruneStatus) {
+		case "", models.StatusCompleted, models.StatusFailed, models.StatusStopped:
+		default:
+			return fmt.Errorf("invalid --status %q (expected completed, failed or stopped)", pruneStatus)
+		}
+		if pruneUntil < 0 {
+			return fmt.Errorf("--until must not be negative")
+		}
+		return nil
+	},
+	RunE: runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().BoolVarP(&pruneForce, "force", "f", false, "Don't ask for confirmation")
+	pruneCmd.Flags().Var(newExtendedDuration(0, &pruneUntil), "until", "Only remove simulations finished more than this long ago (e.g. 24h, 7d)")
+	pruneCmd.Flags().StringVar(&pruneStatus, "status", "", "Only remove simulations in this state (completed, failed, stopped)")
+	pruneCmd.Flags().BoolVar(&pruneVolumes, "volumes", false, "Also remove named volumes mounted by the simulations")
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	simulations, err := client.ListSimulations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list simulations: %w", err)
+	}
+
+	var cutoff time.Time
+	if pruneUntil > 0 {
+		cutoff = time.Now().Add(-pruneUntil)
+	}
+
+	// The list API has no finish time, so candidates are inspected. One that
+	// can't be is kept rather than pruned blind, and reported.
+	var prunable []*models.Simulation
+	skipped := 0
+	for _, sim := range simulations {
+		if !isTerminalStatus(sim.Status) {
+			continue
+		}
+		full, err := client.InspectSimulation(ctx, sim.ContainerID)
+		if docker.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Skipping %s (%s): %v\n", color.YellowString("⚠"), sim.ID, sim.Name, err)
+			skipped++
+			continue
+		}
+		if isPrunable(full, models.SimulationStatus(pruneStatus), cutoff) {
+			prunable = append(prunable, full)
+		}
+	}
+
+	if len(prunable) == 0 {
+		fmt.Println("No simulations to prune")
+		return skippedError(skipped)
+	}
+
+	if !pruneForce {
+		fmt.Printf("%s This will remove %d finished simulation(s). Continue? [y/N]: ",
+			color.YellowString("⚠"), len(prunable))
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	removed, failed, namedVolumes := 0, 0, 0
+	for _, sim := range prunable {
+		n, err := pruneLocked(ctx, client, sim)
+		namedVolumes += n
+		if err != nil {
+			fmt.Printf("%s Failed to remove %s: %v\n", color.RedString("✗"), sim.ID, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s Removed %s (%s, %s)\n", color.GreenString("✓"), sim.ID, sim.Name, sim.Status)
+		removed++
+	}
+
+	// Anonymous volumes go with their containers and aren't counted.
+	summary := fmt.Sprintf("Removed %d simulation(s)", removed)
+	if pruneVolumes {
+		summary += fmt.Sprintf(" and %d named volume(s)", namedVolumes)
+	}
+	fmt.Printf("\n%s %s, %d failed, %d skipped\n", color.GreenString("Summary:"), summary, failed, skipped)
+	if failed > 0 {
+		return fmt.Errorf("%d simulation(s) could not be removed", failed)
+	}
+	return skippedError(skipped)
+}
+
+// skippedError reports simulations prune couldn't inspect, so a script
+// doesn't take a partial prune for a complete one.
+func skippedError(skipped int) error {
+	if skipped > 0 {
+		return fmt.Errorf("%d simulation(s) could not be inspected and were skipped", skipped)
+	}
+	return nil
+}
+
+// isPrunable reports whether sim has finished, in status if one is given,
+// and finished before cutoff if that is set. Without a known finish time a
+// simulation is kept when there is a cutoff.
+func isPrunable(sim *models.Simulation, status models.SimulationStatus, cutoff time.Time) bool {
+	if !isTerminalStatus(sim.Status) {
+		return false
+	}
+	if status != "" && sim.Status != status {
+		return false
+	}
+	if cutoff.IsZero() {
+		return true
+	}
+	return sim.FinishedAt != nil && !sim.FinishedAt.IsZero() && sim.FinishedAt.Before(cutoff)
+}
+
+func pruneLocked(ctx context.Context, client *docker.Client, sim *models.Simulation) (int, error) {
+	lock, err := lockSimulation(ctx, client, sim.ContainerID)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Release()
+
+	volumes, err := client.RemoveSimulationVolumes(ctx, sim.ContainerID, docker.RemoveOptions{NamedVolumes: pruneVolumes})
+	if err != nil {
+		return len(volumes), err
+	}
+	removeRunFiles(runDirectory(sim))
+	recordTermination(sim)
+	return len(volumes), nil
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+)
+
+// secretPattern compiles pattern, normally output.secret_pattern. An
+// invalid pattern falls back to the default with a warning, so a typo in the
+// config never shows secrets it was meant to hide.
+func secretPattern(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		pattern = config.DefaultSecretPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Invalid output.secret_pattern %q, using the default: %v\n", color.YellowString("⚠"), pattern, err)
+		return regexp.MustCompile(config.DefaultSecretPattern)
+	}
+	return re
+}
+
+// maskSecret hides all but the first 3 and last 4 characters of value, as in
+// "sk-…Xy9z". Values too short to keep anything back are masked entirely.
+func maskSecret(value string) string {
+	runes := []rune(value)
+	if len(runes) < 12 {
+		return "****"
+	}
+	return string(runes[:3]) + "…" + string(runes[len(runes)-4:])
+}
+
+// redactEnvironment returns env as sorted KEY=VALUE lines, with the values of
+// keys matching secret masked.
+func redactEnvironment(env map[string]string, secret *regexp.Regexp) []string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := env[k]
+		if secret.MatchString(k) {
+			v = maskSecret(v)
+		}
+		lines = append(lines, k+"="+v)
+	}
+	return lines
+}
+
+// redactSimulations masks the secret environment values of simulations in
+// place, for output formats that print the config as it is stored.
+func redactSimulations(simulations []*models.Simulation, secret *regexp.Regexp) {
+	for _, sim := range simulations {
+		if len(sim.Config.Environment) == 0 {
+			continue
+		}
+		env := make(map[string]string, len(sim.Config.Environment))
+		for k, v := range sim.Config.Environment {
+			if secret.MatchString(k) {
+				v = maskSecret(v)
+			}
+			env[k] = v
+		}
+		sim.Config.Environment = env
+	}
+}
+
+// redactEnvList masks the secret values in a KEY=VALUE list, as Docker
+// reports a container's environment, keeping the order.
+func redactEnvList(env []string, secret *regexp.Regexp) []string {
+	redacted := make([]string, 0, len(env))
+	for _, entry := range env {
+		if k, v, ok := strings.Cut(entry, "="); ok && secret.MatchString(k) {
+			entry = k + "=" + maskSecret(v)
+		}
+		redacted = append(redacted, entry)
+	}
+	return redacted
+}
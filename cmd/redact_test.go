@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"sk-proj-abcdefgh1234", "sk-…1234"},
+		{"abcdefghijkl", "abc…ijkl"},
+		{"abcdefghijk", "****"},
+		{"hunter2", "****"},
+		{"x", "****"},
+		{"", "****"},
+		{"ключ-секрет-значение", "клю…ение"},
+	}
+	for _, tt := range tests {
+		if got := maskSecret(tt.value); got != tt.want {
+			t.Errorf("maskSecret(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRedactEnvironment(t *testing.T) {
+	env := map[string]string{
+		"OPENAI_API_KEY": "sk-proj-abcdefgh1234",
+		"github_token":   "ghp_0123456789abcdef",
+		"DB_PASSWORD":    "hunter2",
+		"CLIENT_SECRET":  "",
+		"LOG_LEVEL":      "debug",
+		"MONKEY":         "also masked, the pattern is a substring match",
+	}
+	got := redactEnvironment(env, regexp.MustCompile(config.DefaultSecretPattern))
+	want := []string{
+		"CLIENT_SECRET=****",
+		"DB_PASSWORD=****",
+		"LOG_LEVEL=debug",
+		"MONKEY=als…atch",
+		"OPENAI_API_KEY=sk-…1234",
+		"github_token=ghp…cdef",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("redactEnvironment() = %q, want %q", got, want)
+	}
+
+	got = redactEnvironment(env, regexp.MustCompile(`^LOG_`))
+	if got[2] != "LOG_LEVEL=****" || got[4] != "OPENAI_API_KEY=sk-proj-abcdefgh1234" {
+		t.Errorf("redactEnvironment() with a custom pattern = %q", got)
+	}
+}
+
+func TestSecretPattern(t *testing.T) {
+	for _, pattern := range []string{"", "([unclosed"} {
+		if got := secretPattern(pattern).String(); got != config.DefaultSecretPattern {
+			t.Errorf("secretPattern(%q) = %q, want the default", pattern, got)
+		}
+	}
+	if got := secretPattern("^AWS_").String(); got != "^AWS_" {
+		t.Errorf("secretPattern() = %q, want the configured pattern", got)
+	}
+}
+
+func TestRedactSimulations(t *testing.T) {
+	sim := &models.Simulation{Config: models.SimulationConfig{
+		Environment: map[string]string{"OPENAI_API_KEY": "sk-proj-abcdefgh1234", "LOG_LEVEL": "debug"},
+	}}
+	redactSimulations([]*models.Simulation{sim, {}}, regexp.MustCompile(config.DefaultSecretPattern))
+
+	want := map[string]string{"OPENAI_API_KEY": "sk-…1234", "LOG_LEVEL": "debug"}
+	if !reflect.DeepEqual(sim.Config.Environment, want) {
+		t.Errorf("Environment = %v, want %v", sim.Config.Environment, want)
+	}
+}
+
+func TestRedactEnvList(t *testing.T) {
+	env := []string{"OPENAI_API_KEY=sk-proj-abcdefgh1234", "PATH=/usr/bin", "DB_PASSWORD=a=b", "NOVALUE"}
+	got := redactEnvList(env, regexp.MustCompile(config.DefaultSecretPattern))
+	want := []string{"OPENAI_API_KEY=sk-…1234", "PATH=/usr/bin", "DB_PASSWORD=****", "NOVALUE"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("redactEnvList() = %q, want %q", got, want)
+	}
+	if env[0] != "OPENAI_API_KEY=sk-proj-abcdefgh1234" {
+		t.Errorf("redactEnvList() changed its input: %q", env)
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var resourcesCmd = &cobra.Command{
+	Use:   "resources",
+	Short: "Show host capacity against simulation usage",
+	Long: `Show the CPUs and memory of the Docker host, what running simulations use
+and have reserved through limits, and the headroom left for new simulations.
+
+A warning is printed when the memory limits of running simulations add up to
+more than the host has (overcommit).
+
+Examples:
+  autobox resources
+  autobox resources --output json`,
+	Args: cobra.NoArgs,
+	RunE: runResources,
+}
+
+type simulationResources struct {
+	ID               string  `json:"id" yaml:"id"`
+	Name             string  `json:"name" yaml:"name"`
+	CPUs             float64 `json:"cpus" yaml:"cpus"`
+	MemoryBytes      uint64  `json:"memory_bytes" yaml:"memory_bytes"`
+	CPULimit         float64 `json:"cpu_limit,omitempty" yaml:"cpu_limit,omitempty"`
+	MemoryLimitBytes int64   `json:"memory_limit_bytes,omitempty" yaml:"memory_limit_bytes,omitempty"`
+}
+
+type resourcesReport struct {
+	HostCPUs            int                   `json:"host_cpus" yaml:"host_cpus"`
+	HostMemoryBytes     int64                 `json:"host_memory_bytes" yaml:"host_memory_bytes"`
+	Simulations         []simulationResources `json:"simulations" yaml:"simulations"`
+	CPUsUsed            float64               `json:"cpus_used" yaml:"cpus_used"`
+	MemoryUsedBytes     uint64                `json:"memory_used_bytes" yaml:"memory_used_bytes"`
+	MemoryLimitBytes    int64                 `json:"memory_limit_bytes" yaml:"memory_limit_bytes"`
+	CPUHeadroom         float64               `json:"cpu_headroom" yaml:"cpu_headroom"`
+	MemoryHeadroomBytes int64                 `json:"memory_headroom_bytes" yaml:"memory_headroom_bytes"`
+	MemoryOvercommitted bool                  `json:"memory_overcommitted" yaml:"memory_overcommitted"`
+}
+
+func runResources(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	host, err := client.HostResources(ctx)
+	if err != nil {
+		return err
+	}
+
+	simulations, err := client.ListSimulations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list simulations: %w", err)
+	}
+
+	var usage []simulationResources
+	for _, sim := range simulations {
+		if sim.Status != models.StatusRunning {
+			continue
+		}
+		resources, err := client.ContainerResources(ctx, sim.ContainerID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s Skipping %s: %v\n", color.YellowString("⚠"), sim.ID, err)
+			continue
+		}
+		usage = append(usage, simulationResources{
+			ID:               sim.ID,
+			Name:             sim.Name,
+			CPUs:             resources.CPUs,
+			MemoryBytes:      resources.MemoryBytes,
+			CPULimit:         resources.CPULimit,
+			MemoryLimitBytes: resources.MemoryLimitBytes,
+		})
+	}
+
+	report := summarizeResources(*host, usage)
+	if report.MemoryOvercommitted {
+		fmt.Fprintf(os.Stderr, "%s Memory limits of running simulations (%s) exceed host memory (%s)\n",
+			color.YellowString("⚠"), formatBytes(uint64(report.MemoryLimitBytes)), formatBytes(uint64(report.HostMemoryBytes)))
+	}
+
+	switch output {
+	case "json":
+		return outputJSON(report)
+	case "yaml":
+		return outputYAML(report)
+	default:
+		outputResourcesTable(report)
+		return nil
+	}
+}
+
+// summarizeResources totals what running simulations use. Headroom is host
+// capacity minus actual usage, while overcommit compares the configured
+// memory limits against the host.
+func summarizeResources(host docker.HostResources, simulations []simulationResources) resourcesReport {
+	report := resourcesReport{
+		HostCPUs:        host.CPUs,
+		HostMemoryBytes: host.MemoryBytes,
+		Simulations:     simulations,
+	}
+	if report.Simulations == nil {
+		report.Simulations = []simulationResources{}
+	}
+
+	for _, sim := range simulations {
+		report.CPUsUsed += sim.CPUs
+		report.MemoryUsedBytes += sim.MemoryBytes
+		report.MemoryLimitBytes += sim.MemoryLimitBytes
+	}
+
+	report.CPUHeadroom = float64(host.CPUs) - report.CPUsUsed
+	report.MemoryHeadroomBytes = host.MemoryBytes - int64(report.MemoryUsedBytes)
+	report.MemoryOvercommitted = host.MemoryBytes > 0 && report.MemoryLimitBytes > host.MemoryBytes
+	return report
+}
+
+func outputResourcesTable(report resourcesReport) {
+	if len(report.Simulations) > 0 {
+		fmt.Printf("\n%-12s  %-30s  %-8s  %-10s  %-10s  %-10s\n", "ID", "NAME", "CPUS", "MEM", "CPU LIMIT", "MEM LIMIT")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, sim := range report.Simulations {
+			cpuLimit, memLimit := "-", "-"
+			if sim.CPULimit > 0 {
+				cpuLimit = fmt.Sprintf("%.2f", sim.CPULimit)
+			}
+			if sim.MemoryLimitBytes > 0 {
+				memLimit = formatBytes(uint64(sim.MemoryLimitBytes))
+			}
+			fmt.Printf("%-12s  %-30s  %-8.2f  %-10s  %-10s  %-10s\n",
+				color.CyanString(sim.ID),
+				truncate(sim.Name, 30),
+				sim.CPUs,
+				formatBytes(sim.MemoryBytes),
+				cpuLimit,
+				memLimit,
+			)
+		}
+	}
+
+	fmt.Printf("\n%s Host Resources\n", color.CyanString("▶"))
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Printf("  %-20s: %d simulation(s)\n", "Running", len(report.Simulations))
+	fmt.Printf("  %-20s: %.2f of %d used, %s free\n", "CPUs",
+		report.CPUsUsed, report.HostCPUs, colorizeHeadroom(fmt.Sprintf("%.2f", report.CPUHeadroom), report.CPUHeadroom > 0))
+
+	memHeadroom := "0 B"
+	if report.MemoryHeadroomBytes > 0 {
+		memHeadroom = formatBytes(uint64(report.MemoryHeadroomBytes))
+	}
+	fmt.Printf("  %-20s: %s of %s used, %s free\n", "Memory",
+		formatBytes(report.MemoryUsedBytes), formatBytes(uint64(report.HostMemoryBytes)),
+		colorizeHeadroom(memHeadroom, report.MemoryHeadroomBytes > 0))
+
+	if report.MemoryLimitBytes > 0 {
+		limits := formatBytes(uint64(report.MemoryLimitBytes))
+		if report.MemoryOvercommitted {
+			limits = color.RedString(limits + " (overcommitted)")
+		}
+		fmt.Printf("  %-20s: %s\n", "Memory Limits", limits)
+	}
+	fmt.Println()
+}
+
+func colorizeHeadroom(value string, ok bool) string {
+	if ok {
+		return color.GreenString(value)
+	}
+	return color.RedString(value)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var restartTimeout time.Duration
+
+var restartCmd = &cobra.Command{
+	Use:   "restart SIMULATION_ID|NAME",
+	Short: "Restart a stopped or running simulation",
+	Long: `Restart an Autobox simulation container with the same configuration it was
+launched with.
+
+A running engine receives SIGTERM and is killed with SIGKILL if it hasn't
+exited within --timeout. A stopped simulation is simply started again.
+
+Examples:
+  autobox restart abc123def456
+  autobox restart abc123def456 --timeout 5s`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateRestartTimeout(restartTimeout)
+	},
+	RunE: runRestart,
+}
+
+func init() {
+	restartCmd.Flags().Var(newExtendedDuration(30*time.Second, &restartTimeout), "timeout", "How long to wait for a running engine to exit before killing it")
+}
+
+func validateRestartTimeout(timeout time.Duration) error {
+	if timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative")
+	}
+	return nil
+}
+
+func runRestart(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	ref := args[0]
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	simulationID, err := resolveSimulationID(ctx, client, ref)
+	if err != nil {
+		return err
+	}
+
+	lock, err := lockSimulation(ctx, client, simulationID)
+	if err != nil {
+		if docker.IsNotFound(err) {
+			return fmt.Errorf("simulation %s not found", ref)
+		}
+		return err
+	}
+	defer lock.Release()
+
+	fmt.Printf("%s Restarting simulation %s...\n", color.YellowString("→"), ref)
+
+	started := time.Now()
+	if err := client.RestartSimulation(ctx, simulationID, restartTimeout); err != nil {
+		return fmt.Errorf("failed to restart simulation: %w", err)
+	}
+
+	sim, err := client.InspectSimulation(ctx, simulationID)
+	if err != nil {
+		return fmt.Errorf("failed to get simulation status: %w", err)
+	}
+
+	fmt.Printf("%s Simulation %s restarted (took %s)\n",
+		color.GreenString("✓"), color.CyanString(sim.ID), time.Since(started).Round(time.Millisecond))
+	fmt.Printf("  Name:   %s\n", sim.Name)
+	fmt.Printf("  Status: %s\n", colorizeStatus(sim.Status))
+	return nil
+}
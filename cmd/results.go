@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	resultsOut    string
+	resultsPath   string
+	resultsFormat string
+)
+
+var resultsCmd = &cobra.Command{
+	Use:   "results SIMULATION_ID|NAME",
+	Short: "Copy a simulation's results file out of its container",
+	Long: `Copy the results file a simulation wrote inside its container to the host,
+or to stdout. This works once the simulation has finished, until the container
+is removed.
+
+The file is the "output" path of the simulation config the container was
+launched with (e.g. /app/logs/results.json); --path reads another file
+instead. --result-format converts the JSON results to YAML or CSV.
+
+Examples:
+  autobox results gift_choice
+  autobox results abc123def456 --out results.json
+  autobox results abc123def456 --result-format csv --out results.csv
+  autobox results abc123def456 --path /app/logs/agents.json`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return checkResultFormat(resultsFormat)
+	},
+	RunE: runResults,
+}
+
+func init() {
+	resultsCmd.Flags().StringVar(&resultsOut, "out", "", "Write the results to this file instead of stdout")
+	resultsCmd.Flags().StringVar(&resultsPath, "path", "", "Path of the file inside the container (default: the config's output)")
+	resultsCmd.Flags().StringVar(&resultsFormat, "result-format", "", "Convert the results to json, yaml or csv (default: as written)")
+}
+
+func runResults(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	simulationID, err := resolveSimulationID(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	path := resultsPath
+	if path == "" {
+		sim, err := client.InspectSimulation(ctx, simulationID)
+		if err != nil {
+			return fmt.Errorf("failed to get simulation status: %w", err)
+		}
+		if sim.Config.ConfigPath == "" {
+			return fmt.Errorf("simulation %s has no recorded config path; pass --path", args[0])
+		}
+
+		var config bytes.Buffer
+		if err := client.CopyFileFromSimulation(ctx, simulationID, sim.Config.ConfigPath, &config); err != nil {
+			return fmt.Errorf("failed to read the simulation config: %w", err)
+		}
+		path, err = simulationOutputPath(config.Bytes())
+		if err != nil {
+			return fmt.Errorf("%s: %w", sim.Config.ConfigPath, err)
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if resultsOut != "" {
+		f, err := os.Create(resultsOut)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", resultsOut, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if resultsFormat == "" {
+		err = client.CopyFileFromSimulation(ctx, simulationID, path, out)
+	} else {
+		var data bytes.Buffer
+		if err = client.CopyFileFromSimulation(ctx, simulationID, path, &data); err == nil {
+			err = transcodeResults(data.Bytes(), resultsFormat, out)
+		}
+	}
+	if err != nil {
+		if resultsOut != "" {
+			os.Remove(resultsOut)
+		}
+		return err
+	}
+
+	if resultsOut != "" {
+		fmt.Fprintf(os.Stderr, "%s Copied %s to %s\n", color.GreenString("✓"), path, resultsOut)
+	}
+	return nil
+}
+
+// simulationOutputPath returns the "output" path of a simulation config.
+func simulationOutputPath(config []byte) (string, error) {
+	var fields struct {
+		Output string `json:"output"`
+	}
+	if err := json.Unmarshal(config, &fields); err != nil {
+		return "", fmt.Errorf("simulation config is not valid JSON: %w", err)
+	}
+	if fields.Output == "" {
+		return "", fmt.Errorf("simulation config has no output path; pass --path")
+	}
+	return fields.Output, nil
+}
+
+// checkResultFormat rejects an unknown --result-format before anything is
+// copied out of the container or --out is created.
+func checkResultFormat(format string) error {
+	switch format {
+	case "", "json", "yaml", "csv":
+		return nil
+	default:
+		return fmt.Errorf("unsupported result format %q (use json, yaml, or csv)", format)
+	}
+}
+
+// transcodeResults converts an engine results.json document into the
+// requested format. CSV is only defined for a top-level array of objects (or
+// a single object), since anything deeper has no obvious row structure.
+func transcodeResults(data []byte, format string, w io.Writer) error {
+	if err := checkResultFormat(format); err != nil {
+		return err
+	}
+
+	var results interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&results); err != nil {
+		return fmt.Errorf("results file is not valid JSON: %w", err)
+	}
+
+	switch format {
+	case "", "json":
+		_, err := w.Write(data)
+		return err
+	case "yaml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(yamlFriendly(results)); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return writeResultsCSV(results, w)
+	}
+}
+
+func writeResultsCSV(results interface{}, w io.Writer) error {
+	var rows []map[string]interface{}
+	switch v := results.(type) {
+	case map[string]interface{}:
+		rows = []map[string]interface{}{v}
+	case []interface{}:
+		for i, item := range v {
+			row, ok := item.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("results cannot be converted to CSV: element %d is not an object", i)
+			}
+			rows = append(rows, row)
+		}
+	default:
+		return fmt.Errorf("results cannot be converted to CSV: expected an array of objects")
+	}
+
+	columnSet := make(map[string]bool)
+	for _, row := range rows {
+		for key := range row {
+			columnSet[key] = true
+		}
+	}
+	columns := make([]string, 0, len(columnSet))
+	for key := range columnSet {
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			record[i] = csvCell(row[col])
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprintf("%t", v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(encoded)
+	}
+}
+
+// yamlFriendly converts json.Number values so the YAML encoder emits them as
+// numbers rather than quoted strings.
+func yamlFriendly(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = yamlFriendly(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = yamlFriendly(item)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTranscodeResultsCSV(t *testing.T) {
+	data := []byte(`[{"agent": "alice", "score": 3}, {"agent": "bob", "score": 4.5, "tags": ["x"]}]`)
+
+	var buf bytes.Buffer
+	if err := transcodeResults(data, "csv", &buf); err != nil {
+		t.Fatalf("transcodeResults() error = %v", err)
+	}
+
+	expected := "agent,score,tags\nalice,3,\nbob,4.5,\"[\"\"x\"\"]\"\n"
+	if buf.String() != expected {
+		t.Errorf("transcodeResults csv = %q, want %q", buf.String(), expected)
+	}
+}
+
+func TestTranscodeResultsYAML(t *testing.T) {
+	data := []byte(`{"name": "gift_choice", "rounds": 3}`)
+
+	var buf bytes.Buffer
+	if err := transcodeResults(data, "yaml", &buf); err != nil {
+		t.Fatalf("transcodeResults() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "rounds: 3\n") {
+		t.Errorf("transcodeResults yaml = %q, want numeric rounds", buf.String())
+	}
+}
+
+func TestTranscodeResultsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+	}{
+		{"Invalid JSON", `{not json`, "yaml"},
+		{"Scalar array to CSV", `[1, 2, 3]`, "csv"},
+		{"Scalar to CSV", `"done"`, "csv"},
+		{"Unknown format", `{}`, "xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := transcodeResults([]byte(tt.data), tt.format, &buf); err == nil {
+				t.Errorf("transcodeResults(%s, %s) expected error", tt.data, tt.format)
+			}
+		})
+	}
+}
+
+func TestCheckResultFormat(t *testing.T) {
+	for _, format := range []string{"", "json", "yaml", "csv"} {
+		if err := checkResultFormat(format); err != nil {
+			t.Errorf("checkResultFormat(%q) = %v, want nil", format, err)
+		}
+	}
+	if err := checkResultFormat("xml"); err == nil || !strings.Contains(err.Error(), "json, yaml, or csv") {
+		t.Errorf("checkResultFormat(xml) = %v, want the supported formats listed", err)
+	}
+}
+
+func TestSimulationOutputPath(t *testing.T) {
+	path, err := simulationOutputPath([]byte(`{"name": "gift_choice", "agents": [], "output": "/app/logs/results.json"}`))
+	if err != nil || path != "/app/logs/results.json" {
+		t.Errorf("simulationOutputPath() = %q, %v, want /app/logs/results.json", path, err)
+	}
+
+	if _, err := simulationOutputPath([]byte(`{"name": "gift_choice"}`)); err == nil || !strings.Contains(err.Error(), "--path") {
+		t.Errorf("simulationOutputPath() without output = %v, want a hint to pass --path", err)
+	}
+	if _, err := simulationOutputPath([]byte(`{`)); err == nil {
+		t.Error("simulationOutputPath() of invalid JSON succeeded, want an error")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -14,8 +15,21 @@ var (
 	verbose bool
 	noColor bool
 	output  string
+
+	jsonIndentWidth int
+	jsonIndentChar  string
 )
 
+// newSimulationManager connects to Docker for commands that only need the
+// operations in docker.SimulationManager. Tests replace it with a fake.
+var newSimulationManager = func() (docker.SimulationManager, error) {
+	client, err := docker.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "autobox",
 	Short: "Autobox CLI - Manage AI simulation containers",
@@ -23,13 +37,36 @@ var rootCmd = &cobra.Command{
 	
 It provides functionality to launch, monitor, and manage simulation containers
 running the Autobox Engine, with support for metrics collection and status tracking.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if output == "csv" && !supportsCSV(cmd) {
+			return fmt.Errorf("--output csv is not supported by %q (only list, status and metrics)", cmd.CommandPath())
+		}
+		if output == "prometheus" && cmd != metricsCmd {
+			return fmt.Errorf("--output prometheus is not supported by %q (only metrics)", cmd.CommandPath())
+		}
+		// Checked up front so a bad value fails before any work, rather than
+		// once there is a result to print.
+		if _, err := jsonIndent(jsonIndentWidth, jsonIndentChar); err != nil {
+			return err
+		}
 		if noColor {
 			color.NoColor = true
 		}
 		if err := config.Init(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error initializing config: %v\n", err)
 		}
+		if cmd != migrateCmd {
+			err := config.MigrateLegacyLayout(func(action string) {
+				fmt.Fprintf(os.Stderr, "%s %s\n", color.YellowString("→"), action)
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s Failed to migrate the legacy ~/.autobox/configs/ directory: %v\n", color.YellowString("⚠"), err)
+			}
+			if warning := config.CheckLayoutVersion(); warning != "" {
+				fmt.Fprintf(os.Stderr, "%s %s\n", color.YellowString("⚠"), warning)
+			}
+		}
+		return nil
 	},
 }
 
@@ -44,7 +81,13 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.autobox/autobox.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
-	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "output format (table|json|yaml)")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "output format (table|json|yaml, csv for list, status and metrics, or prometheus for metrics)")
+	rootCmd.PersistentFlags().IntVar(&jsonIndentWidth, "json-indent", 2, "indent width for JSON output (0 for compact)")
+	rootCmd.PersistentFlags().StringVar(&jsonIndentChar, "json-indent-char", "space", "indent character for JSON output (space|tab)")
+
+	docker.WarningHandler = func(message string) {
+		fmt.Fprintf(os.Stderr, "%s %s\n", color.YellowString("⚠"), message)
+	}
 
 	addCommands()
 }
@@ -52,10 +95,31 @@ func init() {
 func addCommands() {
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(waitCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(watchCmd)
 	rootCmd.AddCommand(metricsCmd)
+	rootCmd.AddCommand(serveMetricsCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(topCmd)
+	rootCmd.AddCommand(resourcesCmd)
+	rootCmd.AddCommand(historyCmd)
 	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(resultsCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(cpCmd)
 	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(restartCmd)
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(unpauseCmd)
 	rootCmd.AddCommand(terminateCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(exportConfigCmd)
+	rootCmd.AddCommand(importConfigCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(versionCmd)
-}
\ No newline at end of file
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Autobox-AI/autobox-cli/internal/config"
 	"github.com/Autobox-AI/autobox-cli/internal/docker"
@@ -22,11 +26,54 @@ var (
 	runServer      string
 	runVolumes     []string
 	runEnv         []string
+	runEnvFiles    []string
 	runName        string
 	runDetach      bool
 	runListSims    bool
+	runShowOrphans bool
+	runNetwork     string
+	runEngineArgs  []string
+	runEntrypoint  string
+	runDetachAfter time.Duration
+	runTee         string
+	runTeeAppend   bool
+	runOnComplete  string
+	runOnFailure   string
+	runMeta        string
+	runPrivileged  bool
+	runCapAdd      []string
+	runCapDrop     []string
+	runPull        string
+	runPullRetries int
+	runQuietPull   bool
+	runProfilePath string
+	runStrictName  bool
+	runStdoutFile  string
+	runLabels      []string
+	runCPUs        float64
+	runMemory      string
+	runRestartMode string
+	runCreateDef   bool
+	runSkipVersion bool
+	runWebhook     string
+	runNotify      string
+	runManifest    string
+	runParallel    int
 )
 
+const defaultSimulationConfig = `{
+  "name": "default-simulation",
+  "agents": [],
+  "duration": 3600,
+  "output": "/app/logs/results.json"
+}`
+
+const defaultMetricsConfig = `{
+  "enabled": true,
+  "interval": 60,
+  "collectors": ["cpu", "memory", "network", "disk"]
+}`
+
 var runCmd = &cobra.Command{
 	Use:   "run [simulation-name]",
 	Short: "Run a new simulation",
@@ -35,6 +82,24 @@ var runCmd = &cobra.Command{
 You can either provide a simulation name to use pre-configured settings from ~/.autobox/config/,
 or specify configuration files directly using flags.
 
+With --manifest, every simulation listed in a YAML file is launched detached,
+--parallel (or --max-parallel) at a time, each once the ones named in its
+depends_on are running, or healthy if their image has a health check. The
+launch order is printed first. Each entry names a pre-configured simulation
+and can set its own image, env (merged over --env) and volumes (added to
+--volume); the other flags apply to all of them:
+
+  simulations:
+    - name: market
+      image: autobox-engine:v1.2
+      env:
+        LOG_LEVEL: debug
+    - name: report
+      depends_on: [market]
+
+A failed launch doesn't stop the others, apart from simulations that depend on
+it; the command exits non-zero if any failed.
+
 Examples:
   # Run a named simulation (loads from ~/.autobox/config/simulations/ and metrics/)
   autobox run gift_choice
@@ -43,19 +108,66 @@ Examples:
   # Run with custom config files
   autobox run --config simulation.json --metrics metrics.json
 
+  # Add Docker labels for other tooling (keys under com.autobox. are reserved)
+  autobox run gift_choice --label team=research --label cost-center=42
+
+  # Attach structured metadata, filterable with list --meta-filter
+  autobox run gift_choice --meta '{"experiment": 42, "owner": "me"}'
+
+  # Run a local command when the simulation finishes. Hooks are Go templates
+  # with .ID, .Name, .Status and .ExitCode, executed through the system shell
+  # with your privileges: only use trusted templates, and wrap values that may
+  # contain spaces or shell metacharacters in {{quote ...}}.
+  autobox run gift_choice --on-complete './collect.sh {{.ID}}' --on-failure 'notify {{quote .Name}} {{.ExitCode}}'
+
+  # Keep a runaway simulation from taking over the host
+  autobox run gift_choice --cpus 1.5 --memory 2g
+
+  # Retry a flaky simulation up to 5 times if the engine exits non-zero
+  autobox run gift_choice --restart on-failure:5
+
+  # Pin the engine image by digest for reproducibility
+  autobox run gift_choice --image autobox-engine@sha256:<digest>
+
+  # Keep structured stdout in a file and show only stderr logs
+  autobox run gift_choice --stdout-file data.jsonl
+
+  # Record resource usage for benchmarking and print peaks at the end
+  autobox run gift_choice --profile-resources profile.jsonl
+
+  # Watch startup for 30 seconds, then detach
+  autobox run gift_choice --detach-after 30s
+
+  # Get a POST with the final status and exit code once it finishes
+  autobox run gift_choice --detach --notify https://hooks.example.com/autobox
+
   # Run with custom image and environment
   autobox run --image autobox-engine:v1.0 --name "test-simulation"
   autobox run --env OPENAI_API_KEY=sk-... --volume ./config:/app/config
 
+  # Keep secrets off the command line
+  autobox run gift_choice --env-file .env
+
+  # Debug the image with a shell instead of the engine. With --entrypoint
+  # only --engine-arg values are passed, not --config/--metrics/--server.
+  autobox run gift_choice --entrypoint /bin/sh --engine-arg -c --engine-arg 'ls /app/config'
+
+  # Share another container's network namespace
+  autobox run gift_choice --network container:abc123def456
+
+  # Launch several simulations from a manifest, two at a time
+  autobox run --manifest runs.yaml --parallel 2
+
   # List available simulations
-  autobox run --list`,
+  autobox run --list
+  autobox run --list --show-orphans`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSimulation,
 }
 
 func init() {
-	home, _ := os.UserHomeDir()
-	defaultVolume := fmt.Sprintf("%s/.autobox/config:/app/config", home)
+	paths, _ := config.Paths()
+	defaultVolume := fmt.Sprintf("%s:/app/config", paths.Config)
 
 	runCmd.Flags().StringVarP(&runImage, "image", "i", "autobox-engine:latest", "Docker image to use")
 	runCmd.Flags().StringVarP(&runConfig, "config", "c", "", "Path to simulation config file (overrides simulation name)")
@@ -63,32 +175,142 @@ func init() {
 	runCmd.Flags().StringVarP(&runServer, "server", "s", "", "Path to server config file (overrides default)")
 	runCmd.Flags().StringSliceVarP(&runVolumes, "volume", "V", []string{defaultVolume}, "Volume mounts (format: host:container)")
 	runCmd.Flags().StringSliceVarP(&runEnv, "env", "e", []string{}, "Environment variables (format: KEY=VALUE)")
+	runCmd.Flags().StringArrayVar(&runEnvFiles, "env-file", []string{}, "Read environment variables from a file of KEY=VALUE lines (repeatable; --env takes precedence)")
 	runCmd.Flags().StringVarP(&runName, "name", "n", "", "Container name (overrides simulation name)")
 	runCmd.Flags().BoolVarP(&runDetach, "detach", "d", false, "Run in detached mode")
+	runCmd.Flags().StringVar(&runTee, "tee", "", "Also write followed logs to this file (ANSI colors stripped)")
+	runCmd.Flags().BoolVar(&runTeeAppend, "tee-append", false, "Append to the --tee file instead of truncating it")
+	runCmd.Flags().StringArrayVar(&runLabels, "label", []string{}, "Docker label to add to the container (format: KEY=VALUE, repeatable)")
+	runCmd.Flags().StringVar(&runMeta, "meta", "", "JSON object of metadata to attach to the simulation")
+	runCmd.Flags().StringVar(&runOnComplete, "on-complete", "", "Shell command to run when the simulation exits successfully (Go template)")
+	runCmd.Flags().StringVar(&runOnFailure, "on-failure", "", "Shell command to run when the simulation fails (Go template)")
+	runCmd.Flags().Var(newExtendedDuration(0, &runDetachAfter), "detach-after", "Follow logs for this long, then detach leaving the simulation running")
+	runCmd.Flags().StringVar(&runNetwork, "network", "", "Network mode (e.g. bridge, host, container:<id>)")
+	runCmd.Flags().StringVar(&runWebhook, "webhook", "", "URL to POST the simulation JSON to on launch and terminate (default webhook.url from config)")
+	runCmd.Flags().StringVar(&runNotify, "notify", "", "URL to POST the final status, exit code and duration to when the simulation finishes, even if detached")
+	runCmd.Flags().BoolVar(&runSkipVersion, "skip-version-check", false, "Don't check the engine version in the image against the supported range")
+	runCmd.Flags().BoolVar(&runCreateDef, "create-default", false, "Create starter simulation.json/metrics.json in ~/.autobox/config if missing")
+	runCmd.Flags().StringVar(&runPull, "pull", "missing", "When to pull the image: never, missing or always")
+	runCmd.Flags().BoolVar(&runQuietPull, "quiet-pull", false, "Don't show per-layer progress when pulling the image")
+	runCmd.Flags().IntVar(&runPullRetries, "pull-retries", 3, "Retries with exponential backoff when pulling a missing image fails transiently")
+	runCmd.Flags().BoolVar(&runPrivileged, "privileged", false, "Give the container full access to the host (use with care)")
+	runCmd.Flags().StringSliceVar(&runCapAdd, "cap-add", []string{}, "Linux capabilities to add (e.g. NET_ADMIN)")
+	runCmd.Flags().StringSliceVar(&runCapDrop, "cap-drop", []string{}, "Linux capabilities to drop (e.g. ALL)")
+	runCmd.Flags().Float64Var(&runCPUs, "cpus", 0, "Limit the simulation to this many CPU cores (e.g. 1.5; default unlimited)")
+	runCmd.Flags().StringVar(&runMemory, "memory", "", "Limit the simulation's memory (e.g. 512m, 2g; default unlimited)")
+	runCmd.Flags().StringVar(&runRestartMode, "restart", "no", "Restart policy: no, on-failure[:max-retries], always or unless-stopped")
+	runCmd.Flags().StringVar(&runEntrypoint, "entrypoint", "", "Override the image entrypoint; the default --config/--metrics/--server command is then dropped, leaving only --engine-arg values")
+	runCmd.Flags().StringArrayVar(&runEngineArgs, "engine-arg", []string{}, "Extra argument appended to the engine command (repeatable)")
+	runCmd.Flags().StringVar(&runStdoutFile, "stdout-file", "", "Follow the run, writing the container's stdout to this file and showing only stderr")
+	runCmd.Flags().StringVar(&runProfilePath, "profile-resources", "", "Sample metrics every 2s into this JSONL file until the simulation exits, then print peak usage")
+	runCmd.Flags().BoolVar(&runStrictName, "strict-name", false, "Use the simulation name as the file name exactly, without lowercasing or replacing - with _ (config: simulation.strict_names)")
+	runCmd.Flags().StringVar(&runManifest, "manifest", "", "Launch every simulation described in this YAML file, detached")
+	runCmd.Flags().IntVar(&runParallel, "parallel", 1, "With --manifest, launch up to this many simulations at a time (0 for no limit)")
+	runCmd.Flags().IntVar(&runParallel, "max-parallel", 1, "Same as --parallel")
 	runCmd.Flags().BoolVarP(&runListSims, "list", "l", false, "List available simulations")
+	runCmd.Flags().BoolVar(&runShowOrphans, "show-orphans", false, "With --list, also show simulations missing their metrics config")
+	runCmd.MarkFlagsMutuallyExclusive("parallel", "max-parallel")
+}
+
+var imageDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// validateImageReference checks digest-pinned references ("repo@sha256:...")
+// up front, since Docker's own error for a malformed digest is unhelpful.
+func validateImageReference(ref string) error {
+	if ref == "" {
+		return fmt.Errorf("image reference cannot be empty")
+	}
+
+	repo, digest, pinned := strings.Cut(ref, "@")
+	if !pinned {
+		return nil
+	}
+	if repo == "" {
+		return fmt.Errorf("invalid image reference %q: missing repository before '@'", ref)
+	}
+	if !imageDigestPattern.MatchString(digest) {
+		return fmt.Errorf("invalid image digest %q: expected sha256:<64 lowercase hex characters>", digest)
+	}
+	return nil
 }
 
 func runSimulation(cmd *cobra.Command, args []string) error {
 	if runListSims {
-		simulations, err := config.ListAvailableSimulations()
-		if err != nil {
-			return fmt.Errorf("failed to list simulations: %w", err)
+		return listAvailableSimulations()
+	}
+
+	if runManifest != "" {
+		if err := validateManifestFlags(args); err != nil {
+			return err
 		}
+	}
 
-		if len(simulations) == 0 {
-			fmt.Println("No simulations found in ~/.autobox/config/")
-			fmt.Println("\nTo create a simulation, add matching JSON files in:")
-			fmt.Println("  ~/.autobox/config/simulations/<name>.json")
-			fmt.Println("  ~/.autobox/config/metrics/<name>.json")
-			return nil
+	if err := validateImageReference(runImage); err != nil {
+		return err
+	}
+
+	if err := validateExitHooks(); err != nil {
+		return err
+	}
+	if err := validateTeePath(runTee); err != nil {
+		return err
+	}
+
+	if runStdoutFile != "" && (runDetach || runDetachAfter > 0) {
+		return fmt.Errorf("--stdout-file follows the whole run and can't be combined with --detach or --detach-after")
+	}
+	if runProfilePath != "" && (runDetach || runDetachAfter > 0) {
+		return fmt.Errorf("--profile-resources needs to follow the run and can't be combined with --detach or --detach-after")
+	}
+	if runPullRetries < 0 {
+		return fmt.Errorf("--pull-retries must not be negative")
+	}
+	if _, err := docker.ParsePullPolicy(runPull); err != nil {
+		return fmt.Errorf("--pull: %w", err)
+	}
+	if err := docker.ValidateCPULimit(runCPUs); err != nil {
+		return fmt.Errorf("--cpus: %w", err)
+	}
+	if runMemory != "" {
+		if _, err := docker.ParseMemoryLimit(runMemory); err != nil {
+			return fmt.Errorf("--memory: %w", err)
 		}
+	}
+	if _, err := docker.ParseRestartPolicy(runRestartMode); err != nil {
+		return fmt.Errorf("--restart: %w", err)
+	}
 
-		fmt.Println("Available simulations:")
-		for _, sim := range simulations {
-			fmt.Printf("  • %s\n", sim)
+	metadata, err := parseMetadata(runMeta)
+	if err != nil {
+		return err
+	}
+
+	labels, err := parseLabels("--label", runLabels)
+	if err != nil {
+		return err
+	}
+
+	webhookURL, err := resolveWebhookURL(runWebhook)
+	if err != nil {
+		return err
+	}
+	if runNotify != "" {
+		if err := validateWebhookURL(runNotify); err != nil {
+			return err
 		}
-		fmt.Println("\nRun a simulation with: autobox run <simulation-name>")
-		return nil
+	}
+
+	capAdd, err := normalizeCapabilities("--cap-add", runCapAdd)
+	if err != nil {
+		return err
+	}
+	capDrop, err := normalizeCapabilities("--cap-drop", runCapDrop)
+	if err != nil {
+		return err
+	}
+
+	if runPrivileged {
+		fmt.Fprintln(os.Stderr, color.RedString("⚠ WARNING: --privileged gives the simulation full access to the host's devices and kernel. Only run trusted images this way."))
 	}
 
 	ctx := context.Background()
@@ -105,28 +327,83 @@ func runSimulation(cmd *cobra.Command, args []string) error {
 
 	var simName string
 	var configPath, metricsPath, serverPath string
-	home, _ := os.UserHomeDir()
+	// runDir holds the expanded configs, if any; they are removed again
+	// unless a simulation is launched with them.
+	var runDir string
+	launched := false
+	defer func() {
+		if !launched {
+			removeRunFiles(runDir)
+		}
+	}()
+	paths, err := config.Paths()
+	if err != nil {
+		return err
+	}
 
-	if len(args) > 0 && runConfig == "" && runMetricsPath == "" {
-		simulationName := args[0]
+	if runStrictName {
+		config.Set("simulation.strict_names", true)
+	}
 
-		if err := config.ValidateSimulationConfig(simulationName); err != nil {
-			return fmt.Errorf("simulation validation failed: %w", err)
+	// --env-file values come first so --env can override them.
+	envMap := make(map[string]string)
+	for _, path := range runEnvFiles {
+		fileEnv, err := config.ParseEnvFile(path)
+		if err != nil {
+			return err
+		}
+		for key, value := range fileEnv {
+			envMap[key] = value
 		}
+	}
+	for _, env := range runEnv {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) == 2 {
+			envMap[parts[0]] = expandFlagValue("--env", parts[1])
+		}
+	}
 
-		configSet, err := config.LoadSimulationConfig(simulationName)
-		if err != nil {
-			return fmt.Errorf("failed to load simulation '%s': %w", simulationName, err)
+	volumes := []string{}
+	if !(len(runVolumes) == 1 && runVolumes[0] == "") {
+		for _, volume := range runVolumes {
+			volumes = append(volumes, expandFlagValue("--volume", volume))
 		}
+	}
 
-		simName = simulationName
-		configPath = "/app/config/simulations/" + filepath.Base(configSet.SimulationPath)
-		metricsPath = "/app/config/metrics/" + filepath.Base(configSet.MetricsPath)
+	simConfig := models.SimulationConfig{
+		Image:         runImage,
+		Environment:   envMap,
+		Volumes:       volumes,
+		NetworkMode:   runNetwork,
+		Entrypoint:    runEntrypoint,
+		EngineArgs:    runEngineArgs,
+		Metadata:      metadata,
+		Labels:        labels,
+		Privileged:    runPrivileged,
+		CapAdd:        capAdd,
+		CapDrop:       capDrop,
+		CPULimit:      runCPUs,
+		MemoryLimit:   runMemory,
+		RestartPolicy: runRestartMode,
+		Webhook:       webhookURL,
+	}
 
-		if configSet.ServerPath != "" {
-			serverPath = "/app/config/server.json"
+	if runManifest != "" {
+		return runFromManifest(ctx, client, runManifest, simConfig)
+	}
+
+	if len(args) > 0 && runConfig == "" && runMetricsPath == "" {
+		simulationName := args[0]
+
+		configSet, err := loadNamedSimulation(simulationName)
+		if err != nil {
+			return err
 		}
 
+		simName = simulationName
+		runDir = configSet.RunDirectory
+		configPath, metricsPath, serverPath = namedSimulationPaths(configSet)
+
 		fmt.Printf("%s Loading simulation '%s'...\n", color.YellowString("→"), simulationName)
 		if verbose {
 			fmt.Printf("  Simulation: %s\n", configSet.SimulationPath)
@@ -140,17 +417,9 @@ func runSimulation(cmd *cobra.Command, args []string) error {
 			configPath = runConfig
 		} else {
 			configPath = "/app/config/simulation.json"
-			simulationFile := filepath.Join(home, ".autobox", "config", "simulation.json")
-			if _, err := os.Stat(simulationFile); os.IsNotExist(err) {
-				defaultSimConfig := `{
-  "name": "default-simulation",
-  "agents": [],
-  "duration": 3600,
-  "output": "/app/logs/results.json"
-}`
-				if err := os.WriteFile(simulationFile, []byte(defaultSimConfig), 0644); err != nil {
-					return fmt.Errorf("failed to create default simulation config: %w", err)
-				}
+			simulationFile := filepath.Join(paths.Config, "simulation.json")
+			if err := ensureDefaultConfig(simulationFile, defaultSimulationConfig, "--config"); err != nil {
+				return err
 			}
 		}
 
@@ -158,16 +427,9 @@ func runSimulation(cmd *cobra.Command, args []string) error {
 			metricsPath = runMetricsPath
 		} else {
 			metricsPath = "/app/config/metrics.json"
-			metricsFile := filepath.Join(home, ".autobox", "config", "metrics.json")
-			if _, err := os.Stat(metricsFile); os.IsNotExist(err) {
-				defaultMetricsConfig := `{
-  "enabled": true,
-  "interval": 60,
-  "collectors": ["cpu", "memory", "network", "disk"]
-}`
-				if err := os.WriteFile(metricsFile, []byte(defaultMetricsConfig), 0644); err != nil {
-					return fmt.Errorf("failed to create default metrics config: %w", err)
-				}
+			metricsFile := filepath.Join(paths.Config, "metrics.json")
+			if err := ensureDefaultConfig(metricsFile, defaultMetricsConfig, "--metrics"); err != nil {
+				return err
 			}
 		}
 
@@ -180,7 +442,7 @@ func runSimulation(cmd *cobra.Command, args []string) error {
 		if configPath != "" && simName == "" {
 			localConfigPath := configPath
 			if strings.HasPrefix(configPath, "/app/config/") {
-				localConfigPath = filepath.Join(home, ".autobox", "config", strings.TrimPrefix(configPath, "/app/config/"))
+				localConfigPath = filepath.Join(paths.Config, strings.TrimPrefix(configPath, "/app/config/"))
 			}
 
 			if configData, err := os.ReadFile(localConfigPath); err == nil {
@@ -200,28 +462,10 @@ func runSimulation(cmd *cobra.Command, args []string) error {
 		simName = fmt.Sprintf("simulation-%d", os.Getpid())
 	}
 
-	envMap := make(map[string]string)
-	for _, env := range runEnv {
-		parts := strings.SplitN(env, "=", 2)
-		if len(parts) == 2 {
-			envMap[parts[0]] = parts[1]
-		}
-	}
-
-	volumes := runVolumes
-	if len(volumes) == 1 && volumes[0] == "" {
-		volumes = []string{}
-	}
-
-	simConfig := models.SimulationConfig{
-		Name:        simName,
-		ConfigPath:  configPath,
-		MetricsPath: metricsPath,
-		ServerPath:  serverPath,
-		Image:       runImage,
-		Environment: envMap,
-		Volumes:     volumes,
-	}
+	simConfig.Name = simName
+	simConfig.ConfigPath = configPath
+	simConfig.MetricsPath = metricsPath
+	simConfig.ServerPath = serverPath
 
 	fmt.Printf("%s Running simulation...\n", color.YellowString("→"))
 	if verbose {
@@ -235,33 +479,480 @@ func runSimulation(cmd *cobra.Command, args []string) error {
 		if len(volumes) > 0 {
 			fmt.Printf("  Volumes: %s\n", strings.Join(volumes, ", "))
 		}
+		if runNetwork != "" {
+			fmt.Printf("  Network: %s\n", runNetwork)
+		}
+		if runEntrypoint != "" {
+			fmt.Printf("  Entrypoint: %s\n", runEntrypoint)
+		}
+		if len(runEngineArgs) > 0 {
+			fmt.Printf("  Engine Args: %s\n", formatCommand(runEngineArgs))
+		}
+		if len(capAdd) > 0 {
+			fmt.Printf("  Cap Add: %s\n", strings.Join(capAdd, ", "))
+		}
+		if len(capDrop) > 0 {
+			fmt.Printf("  Cap Drop: %s\n", strings.Join(capDrop, ", "))
+		}
+		if runCPUs > 0 {
+			fmt.Printf("  CPUs: %g\n", runCPUs)
+		}
+		if runMemory != "" {
+			fmt.Printf("  Memory: %s\n", runMemory)
+		}
+		if runRestartMode != "no" {
+			fmt.Printf("  Restart: %s\n", runRestartMode)
+		}
+		if len(envMap) > 0 {
+			fmt.Printf("  Environment:\n")
+			for _, line := range redactEnvironment(envMap, secretPattern(config.Get().Output.SecretPattern)) {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+
+	if err := ensureImage(ctx, client, runImage); err != nil {
+		return err
+	}
+
+	if !runSkipVersion {
+		simConfig.EngineVersion = checkEngineVersion(ctx, client, runImage)
 	}
 
 	simulation, err := client.LaunchSimulation(ctx, simConfig)
 	if err != nil {
 		return fmt.Errorf("failed to run simulation: %w", err)
 	}
+	launched = true
+
+	warnDigestChange(simName, runImage, simulation.Config.ImageDigest)
+	recordLaunch(simulation, simName, runImage)
+	notifyWebhook(ctx, webhookURL, "launch", simulation)
 
 	fmt.Printf("%s Simulation running successfully!\n", color.GreenString("✓"))
 	fmt.Printf("  ID: %s\n", color.CyanString(simulation.ID))
-	fmt.Printf("  Container: %s\n", simulation.ContainerID[:12])
+	fmt.Printf("  Container: %s (%s)\n", simulation.ContainerID[:12], simulation.ContainerName)
 	fmt.Printf("  Status: %s\n", colorizeStatus(simulation.Status))
 
-	if !runDetach {
+	if runNotify != "" {
+		// A background waiter, so the notification is sent however this
+		// command ends: detached, interrupted or followed to the end.
+		if pid, err := startCompletionNotifier(simulation.ContainerID, runNotify); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", color.YellowString("⚠"), err)
+		} else {
+			fmt.Printf("  Notify: %s when finished (background process %d)\n", runNotify, pid)
+		}
+	}
+
+	if runDetach {
+		return nil
+	}
+
+	out, closeTee, err := teeOutput(runTee, runTeeAppend)
+	if err != nil {
+		return err
+	}
+	defer closeTee()
+
+	var profiler *resourceProfiler
+	if runProfilePath != "" {
+		profiler, err = startResourceProfiler(ctx, client, simulation.ContainerID, runProfilePath, profileInterval)
+		if err != nil {
+			return err
+		}
+	}
+
+	if runStdoutFile != "" {
+		fmt.Printf("\n%s Following stderr, writing stdout to %s...\n\n", color.YellowString("→"), runStdoutFile)
+		err = followSplitOutput(ctx, client, simulation.ContainerID, runStdoutFile, out)
+	} else if runDetachAfter > 0 {
+		fmt.Printf("\n%s Following logs for %s before detaching...\n\n", color.YellowString("→"), runDetachAfter)
+		err = followLogsFor(ctx, client, simulation.ContainerID, runDetachAfter, out)
+	} else {
 		fmt.Printf("\n%s Following logs (press Ctrl+C to detach)...\n\n", color.YellowString("→"))
-		return followLogs(ctx, client, simulation.ContainerID)
+		var detached bool
+		detached, err = followLogs(ctx, client, simulation.ContainerID, out)
+		if err == nil && detached {
+			if profiler != nil {
+				profiler.Stop()
+			}
+			return nil
+		}
+	}
+	if err != nil {
+		if profiler != nil {
+			profiler.Stop()
+		}
+		return err
+	}
+
+	if profiler != nil {
+		if err := finishProfile(ctx, client, simulation, profiler); err != nil {
+			return err
+		}
+	}
+
+	if runOnComplete == "" && runOnFailure == "" {
+		return nil
+	}
+	return runExitHooks(ctx, client, simulation)
+}
+
+// loadNamedSimulation validates and loads a simulation from the config
+// directory, as "autobox run <name>" does.
+func loadNamedSimulation(name string) (*config.SimulationConfigSet, error) {
+	if err := config.ValidateSimulationConfig(name); err != nil {
+		return nil, fmt.Errorf("simulation validation failed: %w", err)
+	}
+	configSet, err := config.LoadSimulationConfig(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load simulation '%s': %w", name, err)
+	}
+	if configSet.Legacy {
+		return nil, fmt.Errorf("simulation '%s' is still in the legacy ~/.autobox/configs/ directory, which isn't mounted into simulations; run 'autobox migrate' to move it", name)
+	}
+	warnUnresolved(fmt.Sprintf("simulation '%s'", name), configSet.UnresolvedVariables)
+	if configSet.Expanded {
+		if err := configSet.WriteExpanded(); err != nil {
+			return nil, fmt.Errorf("failed to write the expanded configs of simulation '%s': %w", name, err)
+		}
+	}
+	return configSet, nil
+}
+
+// expandFlagValue substitutes environment variables in a flag value that the
+// shell left alone, for example because it was quoted.
+func expandFlagValue(flag, value string) string {
+	expanded, unknown := config.ExpandEnv(value)
+	warnUnresolved(flag, unknown)
+	return expanded
+}
+
+func warnUnresolved(source string, names []string) {
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "%s %s references $%s, which is not set; leaving it as written\n", color.YellowString("⚠"), source, name)
+	}
+}
+
+// namedSimulationPaths maps a loaded simulation's files to where the default
+// config volume mounts them in the container.
+func namedSimulationPaths(configSet *config.SimulationConfigSet) (configPath, metricsPath, serverPath string) {
+	configPath = containerConfigPath(configSet.SimulationPath)
+	metricsPath = containerConfigPath(configSet.MetricsPath)
+	if configSet.ServerPath != "" {
+		serverPath = containerConfigPath(configSet.ServerPath)
+	}
+	return configPath, metricsPath, serverPath
+}
+
+// containerConfigPath maps a file under the config directory to its path
+// under /app/config.
+func containerConfigPath(hostPath string) string {
+	paths, err := config.Paths()
+	if err != nil {
+		return "/app/config/" + filepath.Base(hostPath)
+	}
+	rel, err := filepath.Rel(paths.Config, hostPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "/app/config/" + filepath.Base(hostPath)
+	}
+	return "/app/config/" + filepath.ToSlash(rel)
+}
+
+// runDirectory returns the host directory holding the expanded configs sim
+// was launched with, or "" if it was given the config files as written.
+func runDirectory(sim *models.Simulation) string {
+	paths, err := config.Paths()
+	if err != nil {
+		return ""
+	}
+	rest, ok := strings.CutPrefix(sim.Config.ConfigPath, containerConfigPath(paths.Runs)+"/")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, "/")
+	if name == "" || name == "." || name == ".." {
+		return ""
+	}
+	return filepath.Join(paths.Runs, name)
+}
+
+// removeRunFiles deletes the expanded configs of a simulation whose
+// container is gone. A failure is only a warning, since the simulation
+// itself was removed.
+func removeRunFiles(dir string) {
+	if dir == "" {
+		return
+	}
+	if err := config.RemoveRun(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Failed to remove the expanded configs: %v\n", color.YellowString("⚠"), err)
+	}
+}
+
+// finishProfile waits for the simulation to exit so --profile-resources
+// covers the whole run, then prints the summary.
+func finishProfile(ctx context.Context, client *docker.Client, simulation *models.Simulation, profiler *resourceProfiler) error {
+	fmt.Printf("\n%s Profiling resources until simulation %s exits...\n", color.YellowString("→"), simulation.ID)
+	_, waitErr := client.WaitForExit(ctx, simulation.ContainerID)
+
+	summary, err := profiler.Stop()
+	if waitErr != nil {
+		return waitErr
+	}
+	if err != nil {
+		return err
+	}
+	printProfileSummary(runProfilePath, summary)
+	return nil
+}
+
+// ensureDefaultConfig checks that a config used without --config/--metrics
+// exists. A starter file is only written when --create-default asks for it.
+func ensureDefaultConfig(path, content, flag string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+
+	if !runCreateDef {
+		return fmt.Errorf("no config found at %s\n\nRun a named simulation with 'autobox run <name>' (see --list), pass %s, or use --create-default to create a starter config", path, flag)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to create default config %s: %w", path, err)
+	}
+	fmt.Printf("%s Created default config %s\n", color.YellowString("→"), path)
+	return nil
+}
+
+// ensureImage pulls the image as --pull says: by default only when it isn't
+// available locally. Connection errors are retried --pull-retries times; a
+// missing image or denied access fails right away. Layer progress is shown
+// unless --quiet-pull is set.
+func ensureImage(ctx context.Context, client *docker.Client, ref string) error {
+	policy, err := docker.ParsePullPolicy(runPull)
+	if err != nil {
+		return fmt.Errorf("--pull: %w", err)
+	}
+
+	pulling := false
+	opts := docker.PullOptions{
+		Retries: runPullRetries,
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			fmt.Printf("%s Pull failed (%v), retrying in %s (%d/%d)...\n",
+				color.YellowString("⚠"), err, delay, attempt, runPullRetries)
+		},
+		OnStart: func() {
+			pulling = true
+			fmt.Printf("%s Pulling image %s...\n", color.YellowString("→"), ref)
+		},
+	}
+	if !runQuietPull {
+		opts.OnProgress = newPullProgressPrinter(os.Stdout).print
+	}
+
+	pulled, err := client.EnsureImage(ctx, ref, policy, opts)
+	if err != nil {
+		if pulling {
+			fmt.Printf("%s Pull of %s failed\n", color.RedString("✗"), ref)
+		}
+		return err
+	}
+	if pulled {
+		fmt.Printf("%s Pulled %s\n", color.GreenString("✓"), ref)
+	}
+	return nil
+}
+
+// pullProgressPrinter writes one line per layer status change. The daemon
+// repeats "Downloading" and "Extracting" many times a second with an updated
+// progress bar; only the first of each run is printed so the output stays
+// readable when it isn't a terminal.
+type pullProgressPrinter struct {
+	w    io.Writer
+	last map[string]string
+}
+
+func newPullProgressPrinter(w io.Writer) *pullProgressPrinter {
+	return &pullProgressPrinter{w: w, last: make(map[string]string)}
+}
+
+func (p *pullProgressPrinter) print(id, status, progress string) {
+	if p.last[id] == status {
+		return
+	}
+	p.last[id] = status
+
+	if id == "" {
+		fmt.Fprintf(p.w, "  %s\n", status)
+		return
+	}
+	fmt.Fprintf(p.w, "  %s: %s\n", id, status)
+}
+
+func parseMetadata(raw string) (map[string]interface{}, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+		return nil, fmt.Errorf("--meta must be a JSON object: %w", err)
+	}
+	return metadata, nil
+}
+
+func validateExitHooks() error {
+	// Both flags return before the container exits, so a hook would never run.
+	if (runDetach || runDetachAfter > 0) && (runOnComplete != "" || runOnFailure != "") {
+		return fmt.Errorf("--on-complete and --on-failure cannot be used with --detach or --detach-after")
+	}
+	for _, hook := range []string{runOnComplete, runOnFailure} {
+		if hook == "" {
+			continue
+		}
+		if _, err := renderHook(hook, hookData{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runExitHooks(ctx context.Context, client *docker.Client, simulation *models.Simulation) error {
+	fmt.Printf("\n%s Waiting for simulation %s to finish...\n", color.YellowString("→"), simulation.ID)
+
+	exitCode, err := client.WaitForExit(ctx, simulation.ContainerID)
+	if err != nil {
+		return err
+	}
+
+	status := models.StatusCompleted
+	if exitCode != 0 {
+		status = models.StatusFailed
+	}
+	if finished, err := client.InspectSimulation(ctx, simulation.ContainerID); err == nil {
+		status = finished.Status
+	}
+
+	hook, hookName := runOnComplete, "on-complete"
+	if status == models.StatusFailed {
+		hook, hookName = runOnFailure, "on-failure"
+	}
+	if hook == "" {
+		return nil
+	}
+
+	command, err := renderHook(hook, hookData{
+		ID:       simulation.ID,
+		Name:     simulation.Config.Name,
+		Status:   string(status),
+		ExitCode: exitCode,
+	})
+	if err != nil {
+		return err
 	}
 
+	fmt.Printf("%s Running %s hook: %s\n", color.YellowString("→"), hookName, command)
+	if err := runHook(command); err != nil {
+		return fmt.Errorf("%s hook failed: %w", hookName, err)
+	}
 	return nil
 }
 
-func followLogs(ctx context.Context, client *docker.Client, containerID string) error {
-	logs, err := client.GetSimulationLogs(ctx, containerID, 100)
+func listAvailableSimulations() error {
+	entries, err := config.ListSimulationsWithStatus()
 	if err != nil {
-		return fmt.Errorf("failed to get logs: %w", err)
+		return fmt.Errorf("failed to list simulations: %w", err)
+	}
+
+	var runnable, orphans []config.SimulationEntry
+	for _, entry := range entries {
+		if entry.Complete {
+			runnable = append(runnable, entry)
+		} else {
+			orphans = append(orphans, entry)
+		}
+	}
+
+	if len(runnable) == 0 {
+		fmt.Println("No simulations found in ~/.autobox/config/")
+		fmt.Println("\nTo create a simulation, add matching JSON files in:")
+		fmt.Println("  ~/.autobox/config/simulations/<name>.json")
+		fmt.Println("  ~/.autobox/config/metrics/<name>.json")
+	} else {
+		fmt.Println("Available simulations:")
+		for _, sim := range runnable {
+			fmt.Printf("  • %s\n", sim.Name)
+		}
+	}
+
+	if len(orphans) > 0 {
+		if runShowOrphans {
+			fmt.Println("\nIncomplete simulations (not runnable):")
+			for _, sim := range orphans {
+				fmt.Printf("  %s %s %s\n", color.YellowString("⚠"), sim.Name,
+					color.YellowString("(missing %s)", sim.Missing))
+			}
+		} else {
+			fmt.Printf("\n%s %d incomplete simulation(s) hidden, use --show-orphans to see them\n",
+				color.YellowString("⚠"), len(orphans))
+		}
+	}
+
+	if len(runnable) > 0 {
+		fmt.Println("\nRun a simulation with: autobox run <simulation-name>")
+	}
+	return nil
+}
+
+// followLogs streams logs until the simulation exits. Ctrl+C detaches,
+// leaving the simulation running, and is reported through detached.
+func followLogs(ctx context.Context, client *docker.Client, containerID string, out io.Writer) (detached bool, err error) {
+	followCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	if err := streamLogs(followCtx, client, containerID, docker.LogOptions{Tail: 100}, out); err != nil {
+		return false, err
+	}
+	if followCtx.Err() != nil && ctx.Err() == nil {
+		fmt.Printf("\n%s Detached; simulation %s is still running\n",
+			color.GreenString("✓"), color.CyanString(containerID[:12]))
+		return true, nil
+	}
+	return false, nil
+}
+
+// followSplitOutput follows the simulation until it exits, writing its stdout
+// to path and its stderr to out.
+func followSplitOutput(ctx context.Context, client *docker.Client, containerID, path string, out io.Writer) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create stdout file: %w", err)
+	}
+
+	if err := client.StreamSimulationOutput(ctx, containerID, file, out); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write stdout file: %w", err)
 	}
-	fmt.Print(logs)
 	return nil
 }
 
+// followLogsFor streams logs until the duration elapses or the simulation
+// exits, whichever comes first. Hitting the deadline is a successful detach.
+func followLogsFor(ctx context.Context, client *docker.Client, containerID string, d time.Duration, out io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
 
+	if err := streamLogs(ctx, client, containerID, docker.LogOptions{Tail: 100}, out); err != nil {
+		return err
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		fmt.Printf("\n%s Detached after %s; simulation %s is still running\n",
+			color.GreenString("✓"), d, color.CyanString(containerID[:12]))
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// launchNode is one simulation in a launch graph. DependsOn names the
+// simulations that have to be up before this one starts.
+type launchNode struct {
+	Name      string
+	DependsOn []string
+}
+
+// launchOrder sorts nodes so that every simulation comes after its
+// dependencies. Independent simulations keep their input order. Unknown
+// dependencies, duplicate names and cycles are reported as errors, with the
+// cycle spelled out.
+func launchOrder(nodes []launchNode) ([]string, error) {
+	index := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		if _, dup := index[node.Name]; dup {
+			return nil, fmt.Errorf("simulation %q is listed more than once", node.Name)
+		}
+		index[node.Name] = i
+	}
+	for _, node := range nodes {
+		for _, dep := range node.DependsOn {
+			if _, ok := index[dep]; !ok {
+				return nil, fmt.Errorf("simulation %q depends on unknown simulation %q", node.Name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(nodes))
+	order := make([]string, 0, len(nodes))
+	var path []string
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for j, name := range path {
+				if name == nodes[i].Name {
+					start = j
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), nodes[i].Name)
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[i] = visiting
+		path = append(path, nodes[i].Name)
+		for _, dep := range nodes[i].DependsOn {
+			if err := visit(index[dep]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = done
+		order = append(order, nodes[i].Name)
+		return nil
+	}
+
+	for i := range nodes {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// scheduleLaunches calls launch for every node once all of its dependencies
+// have launched successfully, running at most maxParallel launches at a time
+// (no limit if maxParallel <= 0). launch must return only once the simulation
+// is ready for dependents; runFromManifest waits for it to be running, or
+// healthy. A node whose dependency failed is not launched. The result maps each failed or skipped node to its
+// error; it is empty when everything launched.
+func scheduleLaunches(ctx context.Context, nodes []launchNode, maxParallel int, launch func(ctx context.Context, name string) error) (map[string]error, error) {
+	if _, err := launchOrder(nodes); err != nil {
+		return nil, err
+	}
+
+	finished := make(map[string]chan struct{}, len(nodes))
+	for _, node := range nodes {
+		finished[node.Name] = make(chan struct{})
+	}
+
+	var slots chan struct{}
+	if maxParallel > 0 {
+		slots = make(chan struct{}, maxParallel)
+	}
+
+	var (
+		mu       sync.Mutex
+		failures = make(map[string]error)
+		wg       sync.WaitGroup
+	)
+	failed := func(name string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return failures[name]
+	}
+
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(node launchNode) {
+			defer wg.Done()
+			defer close(finished[node.Name])
+
+			err := func() error {
+				for _, dep := range node.DependsOn {
+					select {
+					case <-finished[dep]:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+					if failed(dep) != nil {
+						return fmt.Errorf("not launched: dependency %q failed", dep)
+					}
+				}
+
+				if slots != nil {
+					select {
+					case slots <- struct{}{}:
+						defer func() { <-slots }()
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+				return launch(ctx, node.Name)
+			}()
+
+			if err != nil {
+				mu.Lock()
+				failures[node.Name] = err
+				mu.Unlock()
+			}
+		}(node)
+	}
+
+	wg.Wait()
+	return failures, nil
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLaunchOrder(t *testing.T) {
+	nodes := []launchNode{
+		{Name: "report", DependsOn: []string{"market", "climate"}},
+		{Name: "market", DependsOn: []string{"db"}},
+		{Name: "climate"},
+		{Name: "db"},
+	}
+
+	got, err := launchOrder(nodes)
+	if err != nil {
+		t.Fatalf("launchOrder: %v", err)
+	}
+	want := []string{"db", "market", "climate", "report"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("launchOrder = %v, want %v", got, want)
+	}
+}
+
+func TestLaunchOrderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []launchNode
+		want  string
+	}{
+		{
+			name:  "cycle",
+			nodes: []launchNode{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"c"}}, {Name: "c", DependsOn: []string{"a"}}},
+			want:  "dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name:  "self",
+			nodes: []launchNode{{Name: "a", DependsOn: []string{"a"}}},
+			want:  "dependency cycle: a -> a",
+		},
+		{
+			name:  "unknown",
+			nodes: []launchNode{{Name: "a", DependsOn: []string{"ghost"}}},
+			want:  `depends on unknown simulation "ghost"`,
+		},
+		{
+			name:  "duplicate",
+			nodes: []launchNode{{Name: "a"}, {Name: "a"}},
+			want:  "listed more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := launchOrder(tt.nodes)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("launchOrder error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleLaunchesOrdering(t *testing.T) {
+	nodes := []launchNode{
+		{Name: "report", DependsOn: []string{"market", "climate"}},
+		{Name: "market"},
+		{Name: "climate"},
+	}
+
+	var mu sync.Mutex
+	launched := map[string]time.Time{}
+	failures, err := scheduleLaunches(context.Background(), nodes, 0, func(ctx context.Context, name string) error {
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		launched[name] = time.Now()
+		mu.Unlock()
+		return nil
+	})
+	if err != nil || len(failures) != 0 {
+		t.Fatalf("scheduleLaunches = %v, %v; want no failures", failures, err)
+	}
+	if !launched["report"].After(launched["market"]) || !launched["report"].After(launched["climate"]) {
+		t.Errorf("report launched before its dependencies: %v", launched)
+	}
+}
+
+func TestScheduleLaunchesMaxParallel(t *testing.T) {
+	nodes := []launchNode{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+
+	var inFlight, peak int32
+	_, err := scheduleLaunches(context.Background(), nodes, 2, func(ctx context.Context, name string) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("scheduleLaunches: %v", err)
+	}
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+}
+
+func TestScheduleLaunchesSkipsDependents(t *testing.T) {
+	nodes := []launchNode{
+		{Name: "db"},
+		{Name: "market", DependsOn: []string{"db"}},
+		{Name: "report", DependsOn: []string{"market"}},
+		{Name: "climate"},
+	}
+
+	var launched sync.Map
+	failures, err := scheduleLaunches(context.Background(), nodes, 1, func(ctx context.Context, name string) error {
+		launched.Store(name, true)
+		if name == "db" {
+			return errors.New("image not found")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("scheduleLaunches: %v", err)
+	}
+
+	if len(failures) != 3 || failures["climate"] != nil {
+		t.Errorf("failures = %v, want db, market and report", failures)
+	}
+	for _, name := range []string{"market", "report"} {
+		if _, ok := launched.Load(name); ok {
+			t.Errorf("%s was launched despite a failed dependency", name)
+		}
+	}
+	if !strings.Contains(failures["report"].Error(), `dependency "market" failed`) {
+		t.Errorf("report error = %v, want it to name market", failures["report"])
+	}
+}
+
+func TestScheduleLaunchesRejectsCycles(t *testing.T) {
+	nodes := []launchNode{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"a"}}}
+	called := false
+	_, err := scheduleLaunches(context.Background(), nodes, 0, func(ctx context.Context, name string) error {
+		called = true
+		return nil
+	})
+	if err == nil || called {
+		t.Errorf("scheduleLaunches on a cycle: err = %v, launched = %v; want an error and no launches", err, called)
+	}
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveMetricsAddr     string
+	serveMetricsInterval time.Duration
+)
+
+var serveMetricsCmd = &cobra.Command{
+	Use:   "serve-metrics",
+	Short: "Serve metrics for running simulations to Prometheus over HTTP",
+	Long: `Start an HTTP server that exposes /metrics in the Prometheus text format,
+with a labelled series for every running simulation, so autobox can run as a
+scrape target next to the engine.
+
+Stats are streamed from Docker for each running simulation, and the list of
+running simulations is refreshed every --interval, so new runs appear and
+finished ones drop out. The metric names are the same as metrics --output
+prometheus. Ctrl+C or SIGTERM shuts the server down gracefully.
+
+Examples:
+  autobox serve-metrics
+  autobox serve-metrics --addr 127.0.0.1:9753 --interval 10s`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if serveMetricsInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		return nil
+	},
+	RunE: runServeMetrics,
+}
+
+func init() {
+	serveMetricsCmd.Flags().StringVar(&serveMetricsAddr, "addr", ":9753", "Address to listen on")
+	serveMetricsCmd.Flags().Var(newExtendedDuration(5*time.Second, &serveMetricsInterval), "interval", "How often to look for started and finished simulations")
+}
+
+func runServeMetrics(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	listener, err := net.Listen("tcp", serveMetricsAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", serveMetricsAddr, err)
+	}
+
+	live := newLiveMetrics(client)
+	go live.run(ctx, serveMetricsInterval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricsHandler(live))
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	fmt.Printf("%s Serving metrics on http://%s/metrics (Ctrl+C to stop)\n", color.CyanString("▶"), listener.Addr())
+
+	select {
+	case err := <-served:
+		return fmt.Errorf("metrics server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down the metrics server: %w", err)
+	}
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+	fmt.Println("Stopped serving metrics")
+	return nil
+}
+
+// metricsSource supplies the latest metrics of each simulation to serve.
+type metricsSource interface {
+	Snapshot() []simulationMetrics
+}
+
+func metricsHandler(source metricsSource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writePrometheus(w, source.Snapshot())
+	})
+}
+
+// liveMetrics follows the stats stream of every running simulation and keeps
+// the latest sample of each, keyed by container ID.
+type liveMetrics struct {
+	client *docker.Client
+
+	mu      sync.Mutex
+	streams map[string]*metricsStream
+}
+
+// metricsStream is one followed simulation. latest is nil until the first
+// sample arrives.
+type metricsStream struct {
+	id     string
+	name   string
+	cancel context.CancelFunc
+	latest *simulationMetrics
+}
+
+func newLiveMetrics(client *docker.Client) *liveMetrics {
+	return &liveMetrics{client: client, streams: map[string]*metricsStream{}}
+}
+
+// Snapshot returns the latest sample of every followed simulation, ordered
+// by name so that scrapes list series in a stable order.
+func (l *liveMetrics) Snapshot() []simulationMetrics {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rows := make([]simulationMetrics, 0, len(l.streams))
+	for _, s := range l.streams {
+		if s.latest != nil {
+			rows = append(rows, *s.latest)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Name != rows[j].Name {
+			return rows[i].Name < rows[j].Name
+		}
+		return rows[i].ID < rows[j].ID
+	})
+	return rows
+}
+
+// run refreshes the followed simulations every interval until ctx is done.
+func (l *liveMetrics) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := l.refresh(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", color.YellowString("⚠"), err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh starts following simulations that are newly running and stops
+// following those that no longer are.
+func (l *liveMetrics) refresh(ctx context.Context) error {
+	simulations, err := l.client.ListSimulations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list simulations: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	running := map[string]bool{}
+	for _, sim := range filterRunningSimulations(simulations) {
+		running[sim.ContainerID] = true
+		if _, ok := l.streams[sim.ContainerID]; !ok {
+			l.follow(ctx, sim.ContainerID, sim.ID, sim.Name)
+		}
+	}
+	for containerID, s := range l.streams {
+		if !running[containerID] {
+			s.cancel()
+			delete(l.streams, containerID)
+		}
+	}
+	return nil
+}
+
+// follow starts streaming a simulation's stats. The caller holds l.mu. When
+// the stream ends the simulation is dropped, so a later refresh can follow
+// it again if it was restarted.
+func (l *liveMetrics) follow(ctx context.Context, containerID, id, name string) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	s := &metricsStream{id: id, name: name, cancel: cancel}
+	l.streams[containerID] = s
+
+	go func() {
+		defer cancel()
+		samples, errs := l.client.StreamSimulationMetrics(streamCtx, containerID)
+		for m := range samples {
+			l.mu.Lock()
+			s.latest = &simulationMetrics{ID: s.id, Name: s.name, Metrics: m}
+			l.mu.Unlock()
+		}
+		if err := <-errs; err != nil {
+			fmt.Fprintf(os.Stderr, "%s Stopped following %s: %v\n", color.YellowString("⚠"), id, err)
+		}
+
+		l.mu.Lock()
+		if l.streams[containerID] == s {
+			delete(l.streams, containerID)
+		}
+		l.mu.Unlock()
+	}()
+}
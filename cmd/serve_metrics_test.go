@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+type fakeMetricsSource []simulationMetrics
+
+func (f fakeMetricsSource) Snapshot() []simulationMetrics {
+	return f
+}
+
+func TestMetricsHandler(t *testing.T) {
+	source := fakeMetricsSource{
+		{ID: "abc123def456", Name: "gift_choice", Metrics: &models.Metrics{CPUUsage: 12.5, Timestamp: time.Unix(1705329000, 0)}},
+		{ID: "fed987cba654", Name: "summer_camp", Metrics: &models.Metrics{CPUUsage: 80, DiskIO: models.DiskStats{BytesRead: 4096}}},
+	}
+
+	rec := httptest.NewRecorder()
+	metricsHandler(source).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the Prometheus text format", got)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE autobox_cpu_usage_percent gauge\n",
+		`autobox_cpu_usage_percent{id="abc123def456",name="gift_choice"} 12.5` + "\n",
+		`autobox_cpu_usage_percent{id="fed987cba654",name="summer_camp"} 80` + "\n",
+		`autobox_disk_read_bytes_total{id="fed987cba654",name="summer_camp"} 4096` + "\n",
+		`autobox_metrics_timestamp_seconds{id="abc123def456",name="gift_choice"} 1705329000` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("GET /metrics has no %q:\n%s", want, body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	metricsHandler(fakeMetricsSource{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if strings.Contains(rec.Body.String(), "{") {
+		t.Errorf("GET /metrics with no simulations has samples:\n%s", rec.Body.String())
+	}
+}
+
+func TestLiveMetricsSnapshot(t *testing.T) {
+	live := newLiveMetrics(nil)
+	live.streams["c2"] = &metricsStream{latest: &simulationMetrics{ID: "c2", Name: "beta", Metrics: &models.Metrics{}}}
+	live.streams["c1"] = &metricsStream{latest: &simulationMetrics{ID: "c1", Name: "alpha", Metrics: &models.Metrics{}}}
+	live.streams["c0"] = &metricsStream{latest: &simulationMetrics{ID: "c0", Name: "beta", Metrics: &models.Metrics{}}}
+	live.streams["c3"] = &metricsStream{id: "c3", name: "gamma"}
+
+	var got []string
+	for _, row := range live.Snapshot() {
+		got = append(got, row.ID)
+	}
+	if strings.Join(got, ",") != "c1,c0,c2" {
+		t.Errorf("Snapshot() = %v, want [c1 c0 c2] (by name, then ID, without simulations that have no sample yet)", got)
+	}
+}
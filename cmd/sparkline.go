@@ -0,0 +1,110 @@
+package cmd
+
+import "strings"
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+const defaultSparkWindow = 20
+
+// sparkline renders values as a row of Unicode block characters. Values are
+// scaled against a zero baseline (or the minimum, if negative) so that a flat
+// 90% CPU series doesn't look identical to a flat idle one.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := 0.0, values[0]
+	for _, v := range values {
+		if v < low {
+			low = v
+		}
+		if v > high {
+			high = v
+		}
+	}
+
+	var b strings.Builder
+	span := high - low
+	top := len(sparkBlocks) - 1
+	for _, v := range values {
+		idx := 0
+		if span > 0 {
+			idx = int((v - low) / span * float64(top))
+		}
+		if idx < 0 {
+			idx = 0
+		}
+		if idx > top {
+			idx = top
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// sampleWindow keeps the most recent N samples for sparkline rendering.
+type sampleWindow struct {
+	size   int
+	values []float64
+}
+
+func newSampleWindow(size int) *sampleWindow {
+	if size <= 0 {
+		size = defaultSparkWindow
+	}
+	return &sampleWindow{size: size, values: make([]float64, 0, size)}
+}
+
+func (w *sampleWindow) Add(value float64) {
+	if len(w.values) == w.size {
+		copy(w.values, w.values[1:])
+		w.values = w.values[:w.size-1]
+	}
+	w.values = append(w.values, value)
+}
+
+func (w *sampleWindow) Values() []float64 {
+	return w.values
+}
+
+// metricTrends keeps a window of recent CPU and memory samples for each
+// simulation, for the sparkline columns of top --spark.
+type metricTrends struct {
+	size   int
+	cpu    map[string]*sampleWindow
+	memory map[string]*sampleWindow
+}
+
+func newMetricTrends(size int) *metricTrends {
+	return &metricTrends{size: size, cpu: map[string]*sampleWindow{}, memory: map[string]*sampleWindow{}}
+}
+
+// observe adds the latest sample of every row, and forgets simulations that
+// are no longer shown so that the windows don't grow without bound.
+func (t *metricTrends) observe(rows []simulationMetrics) {
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		seen[row.ID] = true
+		if t.cpu[row.ID] == nil {
+			t.cpu[row.ID] = newSampleWindow(t.size)
+			t.memory[row.ID] = newSampleWindow(t.size)
+		}
+		t.cpu[row.ID].Add(row.Metrics.CPUUsage)
+		t.memory[row.ID].Add(row.Metrics.MemoryUsage)
+	}
+	for id := range t.cpu {
+		if !seen[id] {
+			delete(t.cpu, id)
+			delete(t.memory, id)
+		}
+	}
+}
+
+// lines returns the CPU and memory sparklines of the simulation with id.
+func (t *metricTrends) lines(id string) (cpu, memory string) {
+	if t.cpu[id] == nil {
+		return "", ""
+	}
+	return sparkline(t.cpu[id].Values()), sparkline(t.memory[id].Values())
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		expected string
+	}{
+		{"Empty", nil, ""},
+		{"Single zero", []float64{0}, "▁"},
+		{"Flat zero", []float64{0, 0, 0}, "▁▁▁"},
+		{"Flat high", []float64{90, 90}, "██"},
+		{"Ascending", []float64{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{"Scaled to max", []float64{0, 50, 100}, "▁▄█"},
+		{"Negative baseline", []float64{-10, 0, 10}, "▁▄█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := sparkline(tt.values)
+			if result != tt.expected {
+				t.Errorf("sparkline(%v) = %q, want %q", tt.values, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSampleWindow(t *testing.T) {
+	w := newSampleWindow(3)
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		w.Add(v)
+	}
+
+	values := w.Values()
+	if len(values) != 3 {
+		t.Fatalf("sampleWindow: got %d values, want 3", len(values))
+	}
+
+	expected := []float64{3, 4, 5}
+	for i, v := range values {
+		if v != expected[i] {
+			t.Errorf("sampleWindow[%d]: got %v, want %v", i, v, expected[i])
+		}
+	}
+}
+
+func TestMetricTrends(t *testing.T) {
+	trends := newMetricTrends(3)
+	sample := func(id string, cpu, memory float64) simulationMetrics {
+		return simulationMetrics{ID: id, Metrics: &models.Metrics{CPUUsage: cpu, MemoryUsage: memory}}
+	}
+
+	trends.observe([]simulationMetrics{sample("a", 100, 10), sample("b", 5, 5)})
+	trends.observe([]simulationMetrics{sample("a", 0, 10)})
+	trends.observe([]simulationMetrics{sample("a", 50, 10)})
+	trends.observe([]simulationMetrics{sample("a", 100, 10)})
+
+	cpu, memory := trends.lines("a")
+	if cpu != "▁▄█" || memory != "███" {
+		t.Errorf("lines(a) = %q, %q; want the last 3 samples", cpu, memory)
+	}
+	if cpu, _ := trends.lines("b"); cpu != "" {
+		t.Errorf("lines(b) = %q, want b forgotten once it left the table", cpu)
+	}
+
+	var out bytes.Buffer
+	writeTopTable(&out, []simulationMetrics{sample("a", 100, 10)}, trends)
+	if !strings.Contains(out.String(), "CPU TREND") || !strings.Contains(out.String(), "▁▄█") {
+		t.Errorf("writeTopTable() with trends = %q, want the trend columns", out.String())
+	}
+	out.Reset()
+	writeTopTable(&out, []simulationMetrics{sample("a", 100, 10)}, nil)
+	if strings.Contains(out.String(), "TREND") {
+		t.Errorf("writeTopTable() without trends = %q, want no trend columns", out.String())
+	}
+}
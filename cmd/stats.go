@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/internal/state"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var statsSummaryOnly bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show aggregate statistics across all simulations",
+	Long: `Show aggregate statistics across all Autobox simulations, running and finished.
+Runs whose containers have since been removed are counted from the run
+history in ~/.autobox/state.
+
+Reports the count of simulations by status, the average and median runtime of
+completed simulations, and the failure rate (failed / finished).
+
+Examples:
+  autobox stats
+  autobox stats --summary
+  autobox stats --summary --output json`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsSummaryOnly, "summary", false, "Only show the aggregate summary")
+}
+
+type runSummary struct {
+	Total                 int                             `json:"total" yaml:"total"`
+	ByStatus              map[models.SimulationStatus]int `json:"by_status" yaml:"by_status"`
+	CompletedRuns         int                             `json:"completed_runs" yaml:"completed_runs"`
+	AverageRuntimeSeconds float64                         `json:"average_runtime_seconds" yaml:"average_runtime_seconds"`
+	MedianRuntimeSeconds  float64                         `json:"median_runtime_seconds" yaml:"median_runtime_seconds"`
+	FailureRate           float64                         `json:"failure_rate" yaml:"failure_rate"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	simulations, err := inspectAllSimulations(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	records, err := state.ReadHistory()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Couldn't read the run history, so removed simulations aren't counted: %v\n", color.YellowString("⚠"), err)
+	}
+	simulations = mergeHistory(simulations, records)
+
+	summary := summarizeRuns(simulations)
+
+	switch output {
+	case "json":
+		return outputJSON(summary)
+	case "yaml":
+		return outputYAML(summary)
+	default:
+		if !statsSummaryOnly {
+			outputRuntimeTable(simulations)
+		}
+		outputSummaryTable(summary)
+		return nil
+	}
+}
+
+// inspectAllSimulations lists simulations and inspects each one, since the
+// list endpoint doesn't carry start/finish timestamps.
+func inspectAllSimulations(ctx context.Context, client *docker.Client) ([]*models.Simulation, error) {
+	listed, err := client.ListSimulations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list simulations: %w", err)
+	}
+
+	simulations := make([]*models.Simulation, 0, len(listed))
+	for _, sim := range listed {
+		inspected, err := client.InspectSimulation(ctx, sim.ContainerID)
+		if err != nil {
+			// Removed between list and inspect; the listed view is still useful.
+			simulations = append(simulations, sim)
+			continue
+		}
+		simulations = append(simulations, inspected)
+	}
+	return simulations, nil
+}
+
+// mergeHistory adds the runs recorded in the history whose containers are
+// gone to the live simulations. A container that is still there is only
+// counted once, from Docker, which knows its current state. A run whose exit
+// was never recorded is counted as removed.
+func mergeHistory(simulations []*models.Simulation, records []state.HistoryRecord) []*models.Simulation {
+	live := make(map[string]bool, len(simulations))
+	for _, sim := range simulations {
+		live[shortID(sim.ContainerID)] = true
+	}
+
+	merged := simulations
+	for _, record := range records {
+		if live[shortID(record.ContainerID)] {
+			continue
+		}
+		outcome := record.Outcome
+		if !record.Finished() {
+			outcome = state.OutcomeRemoved
+		}
+		startedAt := record.StartedAt
+		merged = append(merged, &models.Simulation{
+			ID:          shortID(record.ContainerID),
+			ContainerID: record.ContainerID,
+			Name:        record.Name,
+			Status:      models.SimulationStatus(outcome),
+			CreatedAt:   record.StartedAt,
+			StartedAt:   &startedAt,
+			FinishedAt:  record.FinishedAt,
+			ExitCode:    record.ExitCode,
+		})
+	}
+	return merged
+}
+
+func simulationRuntime(sim *models.Simulation) (time.Duration, bool) {
+	if sim.StartedAt == nil || sim.FinishedAt == nil || sim.StartedAt.IsZero() || sim.FinishedAt.IsZero() {
+		return 0, false
+	}
+	runtime := sim.FinishedAt.Sub(*sim.StartedAt)
+	if runtime < 0 {
+		return 0, false
+	}
+	return runtime, true
+}
+
+func summarizeRuns(simulations []*models.Simulation) runSummary {
+	summary := runSummary{
+		Total:    len(simulations),
+		ByStatus: make(map[models.SimulationStatus]int),
+	}
+
+	var runtimes []time.Duration
+	for _, sim := range simulations {
+		summary.ByStatus[sim.Status]++
+		if sim.Status != models.StatusCompleted {
+			continue
+		}
+		if runtime, ok := simulationRuntime(sim); ok {
+			runtimes = append(runtimes, runtime)
+		}
+	}
+
+	summary.CompletedRuns = len(runtimes)
+	if len(runtimes) > 0 {
+		sort.Slice(runtimes, func(i, j int) bool { return runtimes[i] < runtimes[j] })
+
+		var total time.Duration
+		for _, r := range runtimes {
+			total += r
+		}
+		summary.AverageRuntimeSeconds = (total / time.Duration(len(runtimes))).Seconds()
+
+		mid := len(runtimes) / 2
+		if len(runtimes)%2 == 0 {
+			summary.MedianRuntimeSeconds = ((runtimes[mid-1] + runtimes[mid]) / 2).Seconds()
+		} else {
+			summary.MedianRuntimeSeconds = runtimes[mid].Seconds()
+		}
+	}
+
+	finished := summary.ByStatus[models.StatusCompleted] + summary.ByStatus[models.StatusFailed]
+	if finished > 0 {
+		summary.FailureRate = float64(summary.ByStatus[models.StatusFailed]) / float64(finished)
+	}
+
+	return summary
+}
+
+func outputRuntimeTable(simulations []*models.Simulation) {
+	if len(simulations) == 0 {
+		fmt.Println(color.YellowString("No simulations found"))
+		return
+	}
+
+	fmt.Printf("\n%-12s  %-30s  %-12s  %-12s\n", "ID", "NAME", "STATUS", "RUNTIME")
+	fmt.Println(strings.Repeat("-", 72))
+	for _, sim := range simulations {
+		runtime := "-"
+		if d, ok := simulationRuntime(sim); ok {
+			runtime = formatDuration(d)
+		} else if sim.StartedAt != nil && sim.Status == models.StatusRunning {
+			runtime = formatDuration(time.Since(*sim.StartedAt))
+		}
+		fmt.Printf("%-12s  %-30s  %-12s  %-12s\n",
+			color.CyanString(sim.ID),
+			truncate(sim.Name, 30),
+			colorizeStatus(sim.Status),
+			runtime,
+		)
+	}
+}
+
+func outputSummaryTable(summary runSummary) {
+	fmt.Printf("\n%s Run Summary\n", color.CyanString("▶"))
+	fmt.Println(strings.Repeat("─", 50))
+
+	fmt.Printf("  %-20s: %d\n", "Total", summary.Total)
+	statuses := make([]string, 0, len(summary.ByStatus))
+	for status := range summary.ByStatus {
+		statuses = append(statuses, string(status))
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		s := models.SimulationStatus(status)
+		fmt.Printf("  %-20s: %d\n", colorizeStatus(s), summary.ByStatus[s])
+	}
+
+	if summary.CompletedRuns > 0 {
+		fmt.Printf("  %-20s: %s\n", "Average Runtime", formatDuration(time.Duration(summary.AverageRuntimeSeconds*float64(time.Second))))
+		fmt.Printf("  %-20s: %s\n", "Median Runtime", formatDuration(time.Duration(summary.MedianRuntimeSeconds*float64(time.Second))))
+	}
+	fmt.Printf("  %-20s: %.1f%%\n", "Failure Rate", summary.FailureRate*100)
+	fmt.Println()
+}
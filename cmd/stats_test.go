@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/state"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+func TestSummarizeRuns(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	finishedAfter := func(d time.Duration) (*time.Time, *time.Time) {
+		start := base
+		end := base.Add(d)
+		return &start, &end
+	}
+
+	s1, f1 := finishedAfter(10 * time.Second)
+	s2, f2 := finishedAfter(20 * time.Second)
+	s3, f3 := finishedAfter(60 * time.Second)
+	s4, f4 := finishedAfter(5 * time.Second)
+
+	simulations := []*models.Simulation{
+		{Status: models.StatusCompleted, StartedAt: s1, FinishedAt: f1},
+		{Status: models.StatusCompleted, StartedAt: s2, FinishedAt: f2},
+		{Status: models.StatusCompleted, StartedAt: s3, FinishedAt: f3},
+		{Status: models.StatusFailed, StartedAt: s4, FinishedAt: f4},
+		{Status: models.StatusRunning, StartedAt: s1},
+	}
+
+	summary := summarizeRuns(simulations)
+
+	if summary.Total != 5 {
+		t.Errorf("Total: got %d, want 5", summary.Total)
+	}
+	if summary.ByStatus[models.StatusCompleted] != 3 {
+		t.Errorf("Completed: got %d, want 3", summary.ByStatus[models.StatusCompleted])
+	}
+	if summary.CompletedRuns != 3 {
+		t.Errorf("CompletedRuns: got %d, want 3", summary.CompletedRuns)
+	}
+	if summary.AverageRuntimeSeconds != 30 {
+		t.Errorf("AverageRuntimeSeconds: got %v, want 30", summary.AverageRuntimeSeconds)
+	}
+	if summary.MedianRuntimeSeconds != 20 {
+		t.Errorf("MedianRuntimeSeconds: got %v, want 20", summary.MedianRuntimeSeconds)
+	}
+	if summary.FailureRate != 0.25 {
+		t.Errorf("FailureRate: got %v, want 0.25", summary.FailureRate)
+	}
+}
+
+func TestSummarizeRunsEmpty(t *testing.T) {
+	summary := summarizeRuns(nil)
+
+	if summary.Total != 0 || summary.CompletedRuns != 0 || summary.FailureRate != 0 {
+		t.Errorf("summarizeRuns(nil) = %+v, want zero summary", summary)
+	}
+}
+
+func TestMergeHistory(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+	live := []*models.Simulation{
+		{ID: "aaaaaaaaaaaa", ContainerID: "aaaaaaaaaaaa1111", Status: models.StatusRunning},
+	}
+	records := []state.HistoryRecord{
+		{ContainerID: "aaaaaaaaaaaa1111", Name: "live", StartedAt: start, Outcome: state.OutcomeRunning},
+		{ContainerID: "bbbbbbbbbbbb2222", Name: "done", StartedAt: start, FinishedAt: &end, Outcome: string(models.StatusCompleted)},
+		{ContainerID: "cccccccccccc3333", Name: "lost", StartedAt: start, Outcome: state.OutcomeRunning},
+	}
+
+	merged := mergeHistory(live, records)
+	if len(merged) != 3 {
+		t.Fatalf("mergeHistory() returned %d simulations, want 3 with the live one counted once", len(merged))
+	}
+	if merged[0] != live[0] {
+		t.Error("mergeHistory() replaced the live simulation with its history record")
+	}
+	if merged[1].Name != "done" || merged[1].Status != models.StatusCompleted {
+		t.Errorf("removed run = %+v, want it completed", merged[1])
+	}
+	if merged[2].Status != models.SimulationStatus(state.OutcomeRemoved) {
+		t.Errorf("run without a recorded exit = %s, want removed", merged[2].Status)
+	}
+
+	summary := summarizeRuns(merged)
+	if summary.Total != 3 || summary.CompletedRuns != 1 || summary.AverageRuntimeSeconds != 60 {
+		t.Errorf("summarizeRuns() = %+v, want the removed run's runtime counted", summary)
+	}
+}
@@ -3,20 +3,27 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Autobox-AI/autobox-cli/internal/config"
 	"github.com/Autobox-AI/autobox-cli/internal/docker"
 	"github.com/Autobox-AI/autobox-cli/pkg/models"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var (
+	statusYAMLDocs    bool
+	statusWithMetrics bool
+)
+
 var statusCmd = &cobra.Command{
-	Use:   "status [SIMULATION_ID]",
+	Use:   "status [SIMULATION_ID|NAME]",
 	Short: "Get the status of a simulation",
 	Long: `Get detailed status information about an Autobox simulation.
 If no simulation ID is provided, shows a list of running simulations to choose from.
@@ -25,15 +32,26 @@ Examples:
   autobox status                        # Select from running simulations
   autobox status abc123def456           # Show specific simulation
   autobox status abc123def456 --output json
-  autobox status abc123def456 -v`,
+  autobox status abc123def456 -v
+  autobox status abc123def456 --output yaml --yaml-documents
+  autobox status abc123def456 --output json --with-metrics`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runStatus,
 }
 
+func init() {
+	statusCmd.Flags().BoolVar(&statusWithMetrics, "with-metrics", false, "Include a metrics snapshot for running simulations")
+	statusCmd.Flags().BoolVar(&statusYAMLDocs, "yaml-documents", false, "With --output yaml, start the document with a --- separator")
+}
+
 func runStatus(cmd *cobra.Command, args []string) error {
+	if statusYAMLDocs && output != "yaml" {
+		return fmt.Errorf("--yaml-documents requires --output yaml")
+	}
+
 	ctx := context.Background()
 
-	client, err := docker.NewClient()
+	client, err := newSimulationManager()
 	if err != nil {
 		return fmt.Errorf("failed to create Docker client: %w", err)
 	}
@@ -67,7 +85,10 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 	} else {
-		simulationID = args[0]
+		simulationID, err = resolveSimulationID(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
 	}
 
 	simulation, err := client.GetSimulationStatus(ctx, simulationID)
@@ -75,16 +96,52 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get simulation status: %w", err)
 	}
 
+	if statusWithMetrics {
+		if err := attachMetrics(ctx, client, simulation); err != nil {
+			return err
+		}
+	}
+
+	// The table masks environment values itself as it prints them.
+	if output == "json" || output == "yaml" || output == "csv" {
+		redactSimulations([]*models.Simulation{simulation}, secretPattern(config.Get().Output.SecretPattern))
+	}
+
 	switch output {
 	case "json":
 		return outputJSON(simulation)
 	case "yaml":
+		if statusYAMLDocs {
+			return outputYAMLDocuments(os.Stdout, []*models.Simulation{simulation})
+		}
 		return outputYAML(simulation)
+	case "csv":
+		return outputCSV(simulation)
 	default:
 		return outputStatusTable(simulation)
 	}
 }
 
+// attachMetrics fills in simulation.Metrics with a live snapshot. Docker only
+// reports stats for running containers, so a finished simulation is left
+// without metrics rather than failing the whole command.
+func attachMetrics(ctx context.Context, client docker.SimulationManager, simulation *models.Simulation) error {
+	running, err := client.IsSimulationRunning(ctx, simulation.ContainerID)
+	if err != nil {
+		return fmt.Errorf("failed to get simulation status: %w", err)
+	}
+	if !running {
+		return nil
+	}
+
+	metrics, err := client.GetSimulationMetrics(ctx, simulation.ContainerID)
+	if err != nil {
+		return fmt.Errorf("failed to get simulation metrics: %w", err)
+	}
+	simulation.Metrics = metrics
+	return nil
+}
+
 func selectSimulation(simulations []*models.Simulation) (string, error) {
 	fmt.Printf("\n%s Select a running simulation:\n\n", color.CyanString("▶"))
 
@@ -130,6 +187,50 @@ func selectSimulation(simulations []*models.Simulation) (string, error) {
 	return selected.ID, nil
 }
 
+// describeExitCode translates common container exit codes into the likely
+// cause, using Docker's 128+signal convention for signal deaths.
+func describeExitCode(code int, oomKilled bool) string {
+	switch {
+	case oomKilled:
+		return "killed: out of memory"
+	case code == 0:
+		return "success"
+	case code == 1:
+		return "general error"
+	case code == 2:
+		return "misuse of command or invalid arguments"
+	case code == 125:
+		return "container failed to run"
+	case code == 126:
+		return "command cannot execute"
+	case code == 127:
+		return "command not found"
+	case code == 130:
+		return "SIGINT (interrupted)"
+	case code == 137:
+		return "SIGKILL (likely OOM or forced stop)"
+	case code == 139:
+		return "SIGSEGV (segmentation fault)"
+	case code == 143:
+		return "SIGTERM (graceful stop)"
+	case code > 128 && code < 160:
+		return fmt.Sprintf("terminated by signal %d", code-128)
+	default:
+		return "application error"
+	}
+}
+
+func colorizeExitCode(code int, text string) string {
+	switch code {
+	case 0:
+		return color.GreenString(text)
+	case 143:
+		return color.YellowString(text)
+	default:
+		return color.RedString(text)
+	}
+}
+
 func outputStatusTable(simulation *models.Simulation) error {
 	fmt.Printf("\n%s Simulation Status\n", color.CyanString("▶"))
 	fmt.Println(strings.Repeat("─", 50))
@@ -137,7 +238,21 @@ func outputStatusTable(simulation *models.Simulation) error {
 	fmt.Printf("%-15s: %s\n", "ID", color.CyanString(simulation.ID))
 	fmt.Printf("%-15s: %s\n", "Name", simulation.Name)
 	fmt.Printf("%-15s: %s\n", "Container ID", simulation.ContainerID[:12])
+	if simulation.ContainerName != "" {
+		fmt.Printf("%-15s: %s\n", "Container Name", simulation.ContainerName)
+	}
 	fmt.Printf("%-15s: %s\n", "Status", colorizeStatus(simulation.Status))
+
+	if simulation.ExitCode != nil && (simulation.Status == models.StatusFailed || simulation.Status == models.StatusCompleted) {
+		fmt.Printf("%-15s: %d (%s)\n", "Exit Code", *simulation.ExitCode,
+			colorizeExitCode(*simulation.ExitCode, describeExitCode(*simulation.ExitCode, simulation.OOMKilled)))
+	}
+	if simulation.OOMKilled && (simulation.Status == models.StatusFailed || simulation.Status == models.StatusCompleted) {
+		fmt.Printf("%-15s: %s\n", "OOMKilled", color.RedString("true (raise --memory or reduce the workload)"))
+	}
+	if simulation.RestartCount > 0 {
+		fmt.Printf("%-15s: %s\n", "Restarts", colorizeRestartCount(simulation.RestartCount))
+	}
 	fmt.Printf("%-15s: %s\n", "Created", simulation.CreatedAt.Format(time.RFC3339))
 
 	if simulation.StartedAt != nil {
@@ -153,21 +268,67 @@ func outputStatusTable(simulation *models.Simulation) error {
 		fmt.Printf("%-15s: %s\n", "Running For", duration.Round(time.Second))
 	}
 
+	if simulation.Metrics != nil {
+		fmt.Printf("%-15s: %s\n", "CPU Usage", formatPercentage(simulation.Metrics.CPUUsage))
+		fmt.Printf("%-15s: %s\n", "Memory Usage", formatPercentage(simulation.Metrics.MemoryUsage))
+	}
+
 	if verbose {
 		fmt.Printf("\n%s Configuration\n", color.CyanString("▶"))
 		fmt.Println(strings.Repeat("─", 50))
 		fmt.Printf("%-15s: %s\n", "Image", simulation.Config.Image)
+		if simulation.Config.ImageDigest != "" {
+			fmt.Printf("%-15s: %s\n", "Image Digest", simulation.Config.ImageDigest)
+		}
+		if simulation.Config.EngineVersion != "" {
+			fmt.Printf("%-15s: %s\n", "Engine Version", simulation.Config.EngineVersion)
+		}
 		fmt.Printf("%-15s: %s\n", "Config Path", simulation.Config.ConfigPath)
 		fmt.Printf("%-15s: %s\n", "Metrics Path", simulation.Config.MetricsPath)
 
+		if simulation.Config.Entrypoint != "" {
+			fmt.Printf("%-15s: %s\n", "Entrypoint", simulation.Config.Entrypoint)
+		}
+		if len(simulation.Command) > 0 {
+			fmt.Printf("%-15s: %s\n", "Command", formatCommand(simulation.Command))
+		}
+
 		if len(simulation.Config.Volumes) > 0 {
 			fmt.Printf("%-15s: %s\n", "Volumes", strings.Join(simulation.Config.Volumes, ", "))
 		}
+		if simulation.Config.NetworkMode != "" {
+			fmt.Printf("%-15s: %s\n", "Network", simulation.Config.NetworkMode)
+		}
+		if simulation.Config.CPULimit > 0 {
+			fmt.Printf("%-15s: %g\n", "CPU Limit", simulation.Config.CPULimit)
+		}
+		if simulation.Config.MemoryLimit != "" {
+			fmt.Printf("%-15s: %s\n", "Memory Limit", simulation.Config.MemoryLimit)
+		}
+		if policy := simulation.Config.RestartPolicy; policy != "" && policy != "no" {
+			fmt.Printf("%-15s: %s\n", "Restart Policy", policy)
+		}
+
+		if simulation.Config.Privileged {
+			fmt.Printf("%-15s: %s\n", "Privileged", color.RedString("yes"))
+		}
+		if len(simulation.Config.CapAdd) > 0 {
+			fmt.Printf("%-15s: %s\n", "Cap Add", strings.Join(simulation.Config.CapAdd, ", "))
+		}
+		if len(simulation.Config.CapDrop) > 0 {
+			fmt.Printf("%-15s: %s\n", "Cap Drop", strings.Join(simulation.Config.CapDrop, ", "))
+		}
+
+		if len(simulation.Config.Metadata) > 0 {
+			if pretty, err := json.MarshalIndent(simulation.Config.Metadata, "  ", "  "); err == nil {
+				fmt.Printf("%-15s:\n  %s\n", "Metadata", pretty)
+			}
+		}
 
 		if len(simulation.Config.Environment) > 0 {
 			fmt.Printf("%-15s:\n", "Environment")
-			for k, v := range simulation.Config.Environment {
-				fmt.Printf("  %s=%s\n", k, v)
+			for _, line := range redactEnvironment(simulation.Config.Environment, secretPattern(config.Get().Output.SecretPattern)) {
+				fmt.Printf("  %s\n", line)
 			}
 		}
 	}
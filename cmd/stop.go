@@ -3,39 +3,163 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var (
+	stopGraceful bool
+	stopKill     bool
+	stopYes      bool
+)
+
+// stopConfirmThreshold is how many pattern matches stop accepts without
+// asking for confirmation.
+const stopConfirmThreshold = 3
+
 var stopCmd = &cobra.Command{
-	Use:   "stop [SIMULATION_ID]",
+	Use:   "stop [SIMULATION_ID|NAME|NAME_PATTERN]",
 	Short: "Stop a running simulation",
 	Long: `Stop a running Autobox simulation container.
-	
+
+By default the engine receives SIGTERM and is killed with SIGKILL if it hasn't
+exited after 30 seconds (--graceful). Use --kill to send SIGKILL immediately to
+an unresponsive engine.
+
+An argument containing *, ? or [ is matched as a glob against the names of
+running simulations, and every match is stopped. More than 3 matches asks for
+confirmation unless --yes is given.
+
 Examples:
-  autobox stop abc123def456`,
+  autobox stop abc123def456
+  autobox stop abc123def456 --kill
+  autobox stop 'exp_*' --yes`,
 	Args: cobra.ExactArgs(1),
 	RunE: runStop,
 }
 
+func init() {
+	stopCmd.Flags().BoolVar(&stopGraceful, "graceful", false, "Send SIGTERM, then SIGKILL after the timeout (default)")
+	stopCmd.Flags().BoolVar(&stopKill, "kill", false, "Send SIGKILL immediately")
+	stopCmd.Flags().BoolVarP(&stopYes, "yes", "y", false, "Don't ask for confirmation when a pattern matches many simulations")
+	stopCmd.MarkFlagsMutuallyExclusive("graceful", "kill")
+}
+
 func runStop(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	simulationID := args[0]
+	ref := args[0]
 
-	client, err := docker.NewClient()
+	client, err := newSimulationManager()
 	if err != nil {
 		return fmt.Errorf("failed to create Docker client: %w", err)
 	}
 	defer client.Close()
 
-	fmt.Printf("%s Stopping simulation %s...\n", color.YellowString("→"), simulationID)
+	if strings.ContainsAny(ref, "*?[") {
+		return stopMatching(ctx, client, ref)
+	}
 
-	if err := client.StopSimulation(ctx, simulationID); err != nil {
+	simulationID, err := resolveSimulationID(ctx, client, ref)
+	if err != nil {
+		return err
+	}
+
+	mode, stop := stopMode(client)
+	fmt.Printf("%s Stopping simulation %s (%s)...\n", color.YellowString("→"), ref, mode)
+
+	started := time.Now()
+	if err := stopLocked(ctx, client, simulationID, stop); err != nil {
 		return fmt.Errorf("failed to stop simulation: %w", err)
 	}
 
-	fmt.Printf("%s Simulation stopped successfully\n", color.GreenString("✓"))
+	fmt.Printf("%s Simulation stopped successfully (%s, took %s)\n",
+		color.GreenString("✓"), mode, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+func stopMatching(ctx context.Context, client docker.SimulationManager, pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+	}
+
+	simulations, err := client.ListSimulations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list simulations: %w", err)
+	}
+
+	matched := filterByNamePattern(filterRunningSimulations(simulations), pattern)
+	if len(matched) == 0 {
+		fmt.Printf("No running simulations match %q\n", pattern)
+		return nil
+	}
+
+	if len(matched) > stopConfirmThreshold && !stopYes {
+		fmt.Printf("%s This will stop %d simulation(s) matching %q. Continue? [y/N]: ",
+			color.YellowString("⚠"), len(matched), pattern)
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	mode, stop := stopMode(client)
+	stopped := 0
+	failed := 0
+	for _, sim := range matched {
+		fmt.Printf("%s Stopping simulation %s (%s, %s)...\n",
+			color.YellowString("→"), sim.ID, sim.Name, mode)
+
+		if err := stopLocked(ctx, client, sim.ContainerID, stop); err != nil {
+			fmt.Printf("%s Failed to stop %s: %v\n", color.RedString("✗"), sim.ID, err)
+			failed++
+		} else {
+			fmt.Printf("%s Stopped %s\n", color.GreenString("✓"), sim.ID)
+			stopped++
+		}
+	}
+
+	fmt.Printf("\n%s Stopped %d simulation(s), %d failed\n",
+		color.GreenString("Summary:"), stopped, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d simulation(s) failed to stop", failed)
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// filterByNamePattern keeps simulations whose name label matches the glob.
+// The pattern must already have been validated with path.Match.
+func filterByNamePattern(simulations []*models.Simulation, pattern string) []*models.Simulation {
+	var matched []*models.Simulation
+	for _, sim := range simulations {
+		if ok, _ := path.Match(pattern, sim.Name); ok {
+			matched = append(matched, sim)
+		}
+	}
+	return matched
+}
+
+func stopMode(client docker.SimulationManager) (string, func(context.Context, string) error) {
+	if stopKill {
+		return "kill", client.KillSimulation
+	}
+	return "graceful", client.StopSimulation
+}
+
+func stopLocked(ctx context.Context, client docker.SimulationManager, simulationID string, stop func(context.Context, string) error) error {
+	lock, err := lockSimulation(ctx, client, simulationID)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	return stop(ctx, simulationID)
+}
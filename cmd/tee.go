@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// partialANSIEscape matches the start of an escape sequence that runs to the
+// end of a write, so the rest may come with the next one.
+var partialANSIEscape = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*)?$`)
+
+// maxPartialEscape bounds how much of an unfinished escape sequence is held
+// back; anything longer isn't a color code and is written as it is.
+const maxPartialEscape = 32
+
+// ansiStripWriter removes terminal color codes so tee'd log files stay
+// readable in editors and greppable. A sequence split across writes, as
+// streamed logs often are, is held back until the rest of it arrives.
+type ansiStripWriter struct {
+	w       io.Writer
+	pending []byte
+}
+
+func (a *ansiStripWriter) Write(p []byte) (int, error) {
+	data := append(a.pending, p...)
+	a.pending = nil
+	if loc := partialANSIEscape.FindIndex(data); loc != nil && len(data)-loc[0] <= maxPartialEscape {
+		a.pending = append([]byte{}, data[loc[0]:]...)
+		data = data[:loc[0]]
+	}
+	if _, err := a.w.Write(ansiEscapePattern.ReplaceAll(data, nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes out a held-back partial sequence, for when no more writes
+// will come to finish it.
+func (a *ansiStripWriter) Flush() error {
+	if len(a.pending) == 0 {
+		return nil
+	}
+	_, err := a.w.Write(a.pending)
+	a.pending = nil
+	return err
+}
+
+// validateTeePath checks that a --tee file can be created before anything
+// is started, so a typo is reported up front rather than after a launch or
+// a selection.
+func validateTeePath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return fmt.Errorf("--tee %s is a directory", path)
+	}
+	dir := filepath.Dir(path)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("invalid --tee %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("invalid --tee %s: %s is not a directory", path, dir)
+	}
+	return nil
+}
+
+// teeOutput returns stdout, optionally duplicated to an ANSI-stripped file.
+// The returned close function is always safe to call.
+func teeOutput(path string, appendMode bool) (io.Writer, func() error, error) {
+	if path == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendMode {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open tee file: %w", err)
+	}
+
+	stripped := &ansiStripWriter{w: file}
+	closeFile := func() error {
+		flushErr := stripped.Flush()
+		if err := file.Close(); err != nil {
+			return err
+		}
+		return flushErr
+	}
+	return io.MultiWriter(os.Stdout, stripped), closeFile, nil
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/Autobox-AI/autobox-cli/internal/docker"
 	"github.com/fatih/color"
@@ -10,16 +11,31 @@ import (
 )
 
 var (
-	terminateForce bool
-	terminateAll   bool
+	terminateForce   bool
+	terminateAll     bool
+	terminateVolumes bool
 )
 
+// terminateSummary is the --all result for --output json/yaml.
+type terminateSummary struct {
+	Terminated []string           `json:"terminated" yaml:"terminated"`
+	Failed     []terminateFailure `json:"failed" yaml:"failed"`
+}
+
+type terminateFailure struct {
+	ID    string `json:"id" yaml:"id"`
+	Error string `json:"error" yaml:"error"`
+}
+
 var terminateCmd = &cobra.Command{
-	Use:   "terminate [SIMULATION_ID]",
+	Use:   "terminate [SIMULATION_ID|NAME]",
 	Short: "Terminate and remove a simulation container",
 	Long: `Terminate and remove an Autobox simulation container completely.
 This command stops the container and removes it from Docker.
 
+Anonymous volumes are removed with the container. Named volumes are kept, as
+with docker rm, unless --volumes is given.
+
 Examples:
   # Terminate a specific simulation
   autobox terminate abc123def456
@@ -28,7 +44,13 @@ Examples:
   autobox terminate --all
 
   # Force terminate without confirmation
-  autobox terminate abc123def456 --force`,
+  autobox terminate abc123def456 --force
+
+  # Also remove the named volumes the simulation mounted
+  autobox terminate abc123def456 --volumes
+
+  # Machine-readable summary: {"terminated": [...], "failed": [{"id", "error"}]}
+  autobox terminate --all --force --output json`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if terminateAll && len(args) > 0 {
 			return fmt.Errorf("cannot specify simulation ID when using --all flag")
@@ -44,12 +66,13 @@ Examples:
 func init() {
 	terminateCmd.Flags().BoolVarP(&terminateForce, "force", "f", false, "Force terminate without confirmation")
 	terminateCmd.Flags().BoolVarP(&terminateAll, "all", "a", false, "Terminate all simulations")
+	terminateCmd.Flags().BoolVar(&terminateVolumes, "volumes", false, "Also remove named volumes mounted by the simulation")
 }
 
 func runTerminate(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	client, err := docker.NewClient()
+	client, err := newSimulationManager()
 	if err != nil {
 		return fmt.Errorf("failed to create Docker client: %w", err)
 	}
@@ -61,54 +84,81 @@ func runTerminate(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to list simulations: %w", err)
 		}
 
-		if len(simulations) == 0 {
+		// Keep stdout clean for structured output; the prompt goes to stderr.
+		structured := output == "json" || output == "yaml"
+
+		if len(simulations) == 0 && !structured {
 			fmt.Println("No simulations found")
 			return nil
 		}
 
-		if !terminateForce {
-			fmt.Printf("%s This will terminate and remove %d simulation(s). Continue? [y/N]: ",
+		if !terminateForce && len(simulations) > 0 {
+			prompt := os.Stdout
+			if structured {
+				prompt = os.Stderr
+			}
+			fmt.Fprintf(prompt, "%s This will terminate and remove %d simulation(s). Continue? [y/N]: ",
 				color.YellowString("⚠"), len(simulations))
 			var response string
 			fmt.Scanln(&response)
 			if response != "y" && response != "Y" {
-				fmt.Println("Aborted")
+				fmt.Fprintln(prompt, "Aborted")
 				return nil
 			}
 		}
 
-		terminated := 0
-		failed := 0
+		summary := terminateSummary{Terminated: []string{}, Failed: []terminateFailure{}}
 		for _, sim := range simulations {
-			fmt.Printf("%s Terminating simulation %s (%s)...\n",
-				color.YellowString("→"), sim.ID, sim.Name)
+			if !structured {
+				fmt.Printf("%s Terminating simulation %s (%s)...\n",
+					color.YellowString("→"), sim.ID, sim.Name)
+			}
 
-			if err := client.RemoveSimulation(ctx, sim.ContainerID, true); err != nil {
-				fmt.Printf("%s Failed to terminate %s: %v\n",
-					color.RedString("✗"), sim.ID, err)
-				failed++
+			if err := terminateLocked(ctx, client, sim.ContainerID); err != nil {
+				if !structured {
+					fmt.Printf("%s Failed to terminate %s: %v\n",
+						color.RedString("✗"), sim.ID, err)
+				}
+				summary.Failed = append(summary.Failed, terminateFailure{ID: sim.ID, Error: err.Error()})
 			} else {
-				fmt.Printf("%s Terminated %s\n", color.GreenString("✓"), sim.ID)
-				terminated++
+				if !structured {
+					fmt.Printf("%s Terminated %s\n", color.GreenString("✓"), sim.ID)
+				}
+				summary.Terminated = append(summary.Terminated, sim.ID)
 			}
 		}
 
-		fmt.Printf("\n%s Terminated %d simulation(s), %d failed\n",
-			color.GreenString("Summary:"), terminated, failed)
+		switch output {
+		case "json":
+			if err := outputJSON(summary); err != nil {
+				return err
+			}
+		case "yaml":
+			if err := outputYAML(summary); err != nil {
+				return err
+			}
+		default:
+			fmt.Printf("\n%s Terminated %d simulation(s), %d failed\n",
+				color.GreenString("Summary:"), len(summary.Terminated), len(summary.Failed))
+		}
 
-		if failed > 0 {
-			return fmt.Errorf("%d simulation(s) failed to be terminated", failed)
+		if len(summary.Failed) > 0 {
+			return fmt.Errorf("%d simulation(s) failed to be terminated", len(summary.Failed))
 		}
 		return nil
 	}
 
-	simulationID := args[0]
+	ref := args[0]
+	simulationID, err := resolveSimulationID(ctx, client, ref)
+	if err != nil {
+		return err
+	}
 
 	if !terminateForce {
 		sim, err := client.GetSimulationStatus(ctx, simulationID)
 		if err != nil {
 			fmt.Printf("%s Terminate and remove simulation %s? [y/N]: ",
-				color.YellowString("⚠"), simulationID)
+				color.YellowString("⚠"), ref)
 		} else {
 			fmt.Printf("%s Terminate and remove simulation %s (%s)? [y/N]: ",
 				color.YellowString("⚠"), sim.ID, sim.Name)
@@ -122,12 +172,38 @@ func runTerminate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("%s Terminating simulation %s...\n", color.YellowString("→"), simulationID)
+	fmt.Printf("%s Terminating simulation %s...\n", color.YellowString("→"), ref)
 
-	if err := client.RemoveSimulation(ctx, simulationID, true); err != nil {
+	if err := terminateLocked(ctx, client, simulationID); err != nil {
 		return fmt.Errorf("failed to terminate simulation: %w", err)
 	}
 
 	fmt.Printf("%s Simulation terminated and removed successfully\n", color.GreenString("✓"))
 	return nil
 }
+
+func terminateLocked(ctx context.Context, client docker.SimulationManager, simulationID string) error {
+	lock, err := lockSimulation(ctx, client, simulationID)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	sim, inspectErr := client.InspectSimulation(ctx, simulationID)
+
+	if err := client.RemoveSimulation(ctx, simulationID, docker.RemoveOptions{Force: true, NamedVolumes: terminateVolumes}); err != nil {
+		return err
+	}
+
+	if inspectErr == nil {
+		removeRunFiles(runDirectory(sim))
+		recordTermination(sim)
+
+		target := sim.Config.Webhook
+		if target == "" {
+			target, _ = resolveWebhookURL("")
+		}
+		notifyWebhook(ctx, target, "terminate", sim)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topInterval time.Duration
+	topSpark    bool
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show live CPU and memory usage of running simulations",
+	Long: `Show every running simulation with its current CPU and memory usage,
+busiest CPU first, refreshed every --interval until Ctrl+C.
+
+Stats for all simulations are fetched in parallel. A simulation that exits or
+is removed during a refresh simply drops out of the table; other failures are
+reported under it without stopping the refresh.
+
+With --spark, CPU and memory trend columns show the last 20 refreshes of
+each simulation as a sparkline.
+
+Examples:
+  autobox top
+  autobox top --interval 5s
+  autobox top --spark`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+func init() {
+	topCmd.Flags().Var(newExtendedDuration(2*time.Second, &topInterval), "interval", "Refresh interval")
+	topCmd.Flags().BoolVar(&topSpark, "spark", false, "Add sparklines of recent CPU and memory usage")
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	if topInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	var trends *metricTrends
+	if topSpark {
+		trends = newMetricTrends(defaultSparkWindow)
+	}
+
+	ticker := time.NewTicker(topInterval)
+	defer ticker.Stop()
+
+	for {
+		simulations, err := client.ListSimulations(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list simulations: %w", err)
+		}
+
+		// A failure for one simulation is shown under the table rather than
+		// ending the refresh.
+		running := filterRunningSimulations(simulations)
+		metrics, fetchErr := client.GetMultipleMetrics(ctx, containerIDs(running))
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		rows := topRows(running, metrics)
+		if trends != nil {
+			trends.observe(rows)
+		}
+		if err := drawTopFrame(os.Stdout, rows, trends, fetchErr, time.Now()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func containerIDs(simulations []*models.Simulation) []string {
+	ids := make([]string, len(simulations))
+	for i, sim := range simulations {
+		ids[i] = sim.ContainerID
+	}
+	return ids
+}
+
+// topRows pairs simulations with their metrics, skipping any without, and
+// sorts them by CPU usage, highest first.
+func topRows(simulations []*models.Simulation, metrics map[string]*models.Metrics) []simulationMetrics {
+	rows := []simulationMetrics{}
+	for _, sim := range simulations {
+		if m, ok := metrics[sim.ContainerID]; ok {
+			rows = append(rows, simulationMetrics{ID: sim.ID, Name: sim.Name, Metrics: m})
+		}
+	}
+	sortSimulationMetrics(rows, "cpu", false)
+	return rows
+}
+
+func drawTopFrame(out *os.File, rows []simulationMetrics, trends *metricTrends, fetchErr error, now time.Time) error {
+	width, isTerminal := terminalWidth(out)
+
+	var frame bytes.Buffer
+	fmt.Fprintln(&frame, watchHeader("autobox top", topInterval, now, width))
+	writeTopTable(&frame, rows, trends)
+	if fetchErr != nil {
+		for _, line := range strings.Split(fetchErr.Error(), "\n") {
+			fmt.Fprintf(&frame, "%s %s\n", color.YellowString("⚠"), line)
+		}
+	}
+
+	if !isTerminal {
+		_, err := fmt.Fprintln(out, frame.String())
+		return err
+	}
+	_, err := io.WriteString(out, clearScreen+clipLines(frame.String(), width))
+	return err
+}
+
+// writeTopTable prints the top table, with trend columns if trends is set.
+func writeTopTable(w io.Writer, rows []simulationMetrics, trends *metricTrends) {
+	if len(rows) == 0 {
+		fmt.Fprintln(w, color.YellowString("No running simulations found"))
+		return
+	}
+
+	var totalCPU float64
+	for _, row := range rows {
+		totalCPU += row.Metrics.CPUUsage
+	}
+	fmt.Fprintf(w, "\n%d running, %.2f%% CPU in total\n\n", len(rows), totalCPU)
+
+	if trends == nil {
+		fmt.Fprintf(w, "%-12s  %-30s  %-8s  %-8s\n", "ID", "NAME", "CPU", "MEM")
+		fmt.Fprintln(w, strings.Repeat("-", 64))
+	} else {
+		fmt.Fprintf(w, "%-12s  %-30s  %-8s  %-20s  %-8s  %s\n", "ID", "NAME", "CPU", "CPU TREND", "MEM", "MEM TREND")
+		fmt.Fprintln(w, strings.Repeat("-", 116))
+	}
+	for _, row := range rows {
+		cpu := fmt.Sprintf("%.2f%%", row.Metrics.CPUUsage)
+		memory := fmt.Sprintf("%.2f%%", row.Metrics.MemoryUsage)
+		if trends == nil {
+			fmt.Fprintf(w, "%-12s  %-30s  %-8s  %-8s\n", color.CyanString(row.ID), truncate(row.Name, 30), cpu, memory)
+			continue
+		}
+		cpuTrend, memoryTrend := trends.lines(row.ID)
+		fmt.Fprintf(w, "%-12s  %-30s  %-8s  %-20s  %-8s  %s\n",
+			color.CyanString(row.ID), truncate(row.Name, 30), cpu, cpuTrend, memory, memoryTrend)
+	}
+}
@@ -1,10 +1,26 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/internal/state"
 	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 func TestTruncate(t *testing.T) {
@@ -81,6 +97,7 @@ func TestCountByStatus(t *testing.T) {
 		{Status: models.StatusCompleted},
 		{Status: models.StatusFailed},
 		{Status: models.StatusRunning},
+		{Status: models.StatusPaused},
 	}
 
 	tests := []struct {
@@ -92,6 +109,7 @@ func TestCountByStatus(t *testing.T) {
 		{"Count completed", models.StatusCompleted, 1},
 		{"Count failed", models.StatusFailed, 1},
 		{"Count stopped", models.StatusStopped, 0},
+		{"Count paused", models.StatusPaused, 1},
 	}
 
 	for _, tt := range tests {
@@ -124,4 +142,1613 @@ func TestFilterRunningSimulations(t *testing.T) {
 			t.Errorf("filterRunningSimulations: got status %s, want %s", sim.Status, models.StatusRunning)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestFormatCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"Plain args", []string{"--config", "/app/config/sim.json"}, "--config /app/config/sim.json"},
+		{"Arg with space", []string{"--label", "two words"}, `--label "two words"`},
+		{"Empty arg", []string{"--flag", ""}, `--flag ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatCommand(tt.args)
+			if result != tt.expected {
+				t.Errorf("formatCommand(%q) = %q, want %q", tt.args, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateImageReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	tests := []struct {
+		name    string
+		ref     string
+		wantErr bool
+	}{
+		{"Tag", "autobox-engine:latest", false},
+		{"Registry with port", "localhost:5000/autobox-engine:v1", false},
+		{"Digest", "autobox-engine@" + digest, false},
+		{"Empty", "", true},
+		{"Missing repository", "@" + digest, true},
+		{"Short digest", "autobox-engine@sha256:abc", true},
+		{"Uppercase digest", "autobox-engine@sha256:" + strings.Repeat("A", 64), true},
+		{"Unsupported algorithm", "autobox-engine@md5:" + strings.Repeat("a", 32), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImageReference(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateImageReference(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnsiStripWriter(t *testing.T) {
+	var buf strings.Builder
+	w := &ansiStripWriter{w: &buf}
+
+	input := "\x1b[32mrunning\x1b[0m step \x1b[1;31m3\x1b[0m\n"
+	n, err := w.Write([]byte(input))
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if n != len(input) {
+		t.Errorf("Write() = %d, want %d", n, len(input))
+	}
+	if buf.String() != "running step 3\n" {
+		t.Errorf("ansiStripWriter wrote %q, want %q", buf.String(), "running step 3\n")
+	}
+}
+
+func TestAnsiStripWriterSplitSequence(t *testing.T) {
+	var buf strings.Builder
+	w := &ansiStripWriter{w: &buf}
+
+	for _, chunk := range []string{"step \x1b[3", "1mfailed\x1b", "[0m\n", "done \x1b"} {
+		if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+			t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+		}
+	}
+	if buf.String() != "step failed\ndone " {
+		t.Errorf("ansiStripWriter wrote %q, want the split sequences stripped", buf.String())
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "step failed\ndone \x1b" {
+		t.Errorf("after Flush() ansiStripWriter wrote %q, want the unfinished escape kept", buf.String())
+	}
+}
+
+func TestValidateTeePath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"", filepath.Join(dir, "run.log"), file} {
+		if err := validateTeePath(path); err != nil {
+			t.Errorf("validateTeePath(%q) error = %v", path, err)
+		}
+	}
+	for _, path := range []string{dir, filepath.Join(dir, "missing", "run.log"), filepath.Join(file, "run.log")} {
+		if err := validateTeePath(path); err == nil {
+			t.Errorf("validateTeePath(%q) = nil, want an error", path)
+		}
+	}
+}
+
+func TestDescribeExitCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      int
+		oomKilled bool
+		expected  string
+	}{
+		{"Success", 0, false, "success"},
+		{"General error", 1, false, "general error"},
+		{"SIGKILL", 137, false, "SIGKILL (likely OOM or forced stop)"},
+		{"SIGKILL with OOM", 137, true, "killed: out of memory"},
+		{"SIGTERM", 143, false, "SIGTERM (graceful stop)"},
+		{"Other signal", 134, false, "terminated by signal 6"},
+		{"Application error", 42, false, "application error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := describeExitCode(tt.code, tt.oomKilled)
+			if result != tt.expected {
+				t.Errorf("describeExitCode(%d, %v) = %q, want %q", tt.code, tt.oomKilled, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchesMetadata(t *testing.T) {
+	metadata := map[string]interface{}{
+		"experiment": float64(42),
+		"owner":      "me",
+		"tuned":      true,
+		"params":     map[string]interface{}{"lr": 0.01},
+	}
+
+	tests := []struct {
+		name     string
+		filters  map[string]string
+		expected bool
+	}{
+		{"Number", map[string]string{"experiment": "42"}, true},
+		{"String", map[string]string{"owner": "me"}, true},
+		{"Bool", map[string]string{"tuned": "true"}, true},
+		{"Nested", map[string]string{"params.lr": "0.01"}, true},
+		{"All must match", map[string]string{"experiment": "42", "owner": "you"}, false},
+		{"Missing key", map[string]string{"team": "x"}, false},
+		{"Path through scalar", map[string]string{"owner.name": "me"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := matchesMetadata(metadata, tt.filters)
+			if result != tt.expected {
+				t.Errorf("matchesMetadata(%v) = %v, want %v", tt.filters, result, tt.expected)
+			}
+		})
+	}
+
+	if matchesMetadata(nil, map[string]string{"experiment": "42"}) {
+		t.Errorf("matchesMetadata(nil) should not match a filter")
+	}
+}
+
+func TestUntilConditionMet(t *testing.T) {
+	sims := func(statuses ...models.SimulationStatus) []*models.Simulation {
+		var result []*models.Simulation
+		for _, s := range statuses {
+			result = append(result, &models.Simulation{Status: s})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name        string
+		condition   string
+		simulations []*models.Simulation
+		expected    bool
+	}{
+		{"All terminal", untilAllCompleted, sims(models.StatusCompleted, models.StatusFailed, models.StatusStopped), true},
+		{"One running", untilAllCompleted, sims(models.StatusCompleted, models.StatusRunning), false},
+		{"One pending", untilAllCompleted, sims(models.StatusCompleted, models.StatusPending), false},
+		{"None running with pending", untilNoneRunning, sims(models.StatusPending, models.StatusStopped), true},
+		{"Still running", untilNoneRunning, sims(models.StatusRunning), false},
+		{"Paused counts as running", untilNoneRunning, sims(models.StatusStopped, models.StatusPaused), false},
+		{"Paused is not completed", untilAllCompleted, sims(models.StatusCompleted, models.StatusPaused), false},
+		{"Empty", untilAllCompleted, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := untilConditionMet(tt.condition, tt.simulations)
+			if result != tt.expected {
+				t.Errorf("untilConditionMet(%s) = %v, want %v", tt.condition, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterByNamePattern(t *testing.T) {
+	simulations := []*models.Simulation{
+		{ID: "1", Name: "exp_alpha"},
+		{ID: "2", Name: "exp_beta"},
+		{ID: "3", Name: "baseline"},
+		{ID: "4", Name: "exp"},
+	}
+
+	tests := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"exp_*", []string{"1", "2"}},
+		{"exp*", []string{"1", "2", "4"}},
+		{"exp_?eta", []string{"2"}},
+		{"[bx]*", []string{"3"}},
+		{"nothing*", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			var ids []string
+			for _, sim := range filterByNamePattern(simulations, tt.pattern) {
+				ids = append(ids, sim.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("filterByNamePattern(%q) = %v, want %v", tt.pattern, ids, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOutputYAMLDocuments(t *testing.T) {
+	simulations := []*models.Simulation{
+		{ID: "abc", Name: "first", Status: models.StatusRunning},
+		{ID: "def", Name: "second", Status: models.StatusCompleted},
+	}
+
+	var buf bytes.Buffer
+	if err := outputYAMLDocuments(&buf, simulations); err != nil {
+		t.Fatalf("outputYAMLDocuments() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "---\n") {
+		t.Errorf("expected output to start with a document separator, got %q", buf.String())
+	}
+	if strings.Count(buf.String(), "---\n") != 2 {
+		t.Errorf("expected one separator per document, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "\n  image: ") || strings.Contains(buf.String(), "\n    image: ") {
+		t.Errorf("expected documents indented by 2 spaces like outputYAML, got %q", buf.String())
+	}
+
+	decoder := yaml.NewDecoder(&buf)
+	var names []string
+	for {
+		var sim models.Simulation
+		err := decoder.Decode(&sim)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		names = append(names, sim.Name)
+	}
+
+	if strings.Join(names, ",") != "first,second" {
+		t.Errorf("decoded documents = %v, want [first second]", names)
+	}
+}
+
+func TestNormalizeCapabilities(t *testing.T) {
+	caps, err := normalizeCapabilities("--cap-add", []string{"net_admin", "CAP_SYS_PTRACE", "ALL"})
+	if err != nil {
+		t.Fatalf("normalizeCapabilities() error = %v", err)
+	}
+	if strings.Join(caps, ",") != "NET_ADMIN,SYS_PTRACE,ALL" {
+		t.Errorf("normalizeCapabilities() = %v", caps)
+	}
+
+	if _, err := normalizeCapabilities("--cap-drop", []string{"NET_ADMINN"}); err == nil {
+		t.Errorf("expected an error for an unknown capability")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"0.1.0", "0.1.0", 0},
+		{"0.2.0", "0.10.0", -1},
+		{"1.0.0", "0.9.9", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.1", 1},
+		{"1.0", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			result := compareVersions(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+
+	if !engineVersionSupported("0.9.9") {
+		t.Errorf("expected 0.9.9 to be within the supported range")
+	}
+	if engineVersionSupported("1.0.0") {
+		t.Errorf("expected 1.0.0 to be above the supported range")
+	}
+	if engineVersionSupported("0.0.9") {
+		t.Errorf("expected 0.0.9 to be below the supported range")
+	}
+}
+
+func TestDockerSince(t *testing.T) {
+	tests := map[string]string{
+		"2d":                   "48h0m0s",
+		"1d12h":                "36h0m0s",
+		"1w":                   "168h0m0s",
+		"0":                    "0",
+		"90m":                  "90m",
+		"1700000000":           "1700000000",
+		"2025-01-01T00:00:00Z": "2025-01-01T00:00:00Z",
+		"":                     "",
+	}
+	for since, want := range tests {
+		if got := dockerSince(since); got != want {
+			t.Errorf("dockerSince(%q) = %q, want %q", since, got, want)
+		}
+	}
+}
+
+func TestValidateLogBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		tail       int
+		since      string
+		sinceStart bool
+		wantErr    bool
+	}{
+		{"Tail only", 100, "", false, false},
+		{"Tail and duration", 500, "10m", false, false},
+		{"Since only", 0, "1h30m", false, false},
+		{"RFC3339", 0, "2024-01-15T14:30:00Z", false, false},
+		{"Unix timestamp", 50, "1705329000", false, false},
+		{"Since start", 0, "", true, false},
+		{"No bound", 0, "", false, true},
+		{"Negative tail", -1, "10m", false, true},
+		{"Bad since", 100, "yesterday", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLogBounds(tt.tail, tt.since, tt.sinceStart)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateLogBounds(%d, %q, %v) error = %v, wantErr %v", tt.tail, tt.since, tt.sinceStart, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseExtendedDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"90s", 90 * time.Second, false},
+		{"1d", 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"1w2d3h4m", (9*24+3)*time.Hour + 4*time.Minute, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"-1d", -24 * time.Hour, false},
+		{"0", 0, false},
+		{"", 0, true},
+		{"1x", 0, true},
+		{"d", 0, true},
+		{"1d 2h", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseExtendedDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExtendedDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("parseExtendedDuration(%q) = %s, want %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortSimulationMetrics(t *testing.T) {
+	rows := func() []simulationMetrics {
+		return []simulationMetrics{
+			{ID: "a", Name: "a", Metrics: &models.Metrics{CPUUsage: 10, MemoryUsage: 70, NetworkIO: models.NetworkStats{BytesReceived: 5, BytesTransmitted: 5}}},
+			{ID: "b", Name: "b", Metrics: &models.Metrics{CPUUsage: 90, MemoryUsage: 20, DiskIO: models.DiskStats{BytesWritten: 100}}},
+			{ID: "c", Name: "c", Metrics: &models.Metrics{CPUUsage: 50, MemoryUsage: 20, NetworkIO: models.NetworkStats{BytesReceived: 1000}}},
+		}
+	}
+
+	tests := []struct {
+		key      string
+		reverse  bool
+		expected string
+	}{
+		{"cpu", false, "b,c,a"},
+		{"cpu", true, "a,c,b"},
+		{"mem", false, "a,b,c"},
+		{"net", false, "c,a,b"},
+		{"disk", false, "b,a,c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s_reverse_%v", tt.key, tt.reverse), func(t *testing.T) {
+			r := rows()
+			sortSimulationMetrics(r, tt.key, tt.reverse)
+			var ids []string
+			for _, row := range r {
+				ids = append(ids, row.ID)
+			}
+			if strings.Join(ids, ",") != tt.expected {
+				t.Errorf("sort by %s (reverse=%v) = %v, want %s", tt.key, tt.reverse, ids, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPullProgressPrinter(t *testing.T) {
+	var buf bytes.Buffer
+	p := newPullProgressPrinter(&buf)
+
+	p.print("", "Pulling from library/autobox-engine", "")
+	p.print("a1b2", "Pulling fs layer", "")
+	p.print("a1b2", "Downloading", "[=>   ] 1MB/10MB")
+	p.print("a1b2", "Downloading", "[===> ] 8MB/10MB")
+	p.print("c3d4", "Downloading", "[=>   ] 1MB/5MB")
+	p.print("a1b2", "Pull complete", "")
+
+	want := "  Pulling from library/autobox-engine\n" +
+		"  a1b2: Pulling fs layer\n" +
+		"  a1b2: Downloading\n" +
+		"  c3d4: Downloading\n" +
+		"  a1b2: Pull complete\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSortHistory(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := base.Add(2 * time.Hour)
+	finished := func(minutes int) *time.Time {
+		t := base.Add(time.Duration(minutes) * time.Minute)
+		return &t
+	}
+	code := func(c int) *int { return &c }
+
+	// In launch order, as ReadHistory returns them.
+	records := func() []state.HistoryRecord {
+		return []state.HistoryRecord{
+			{ContainerID: "a", StartedAt: base, FinishedAt: finished(30), ExitCode: code(0), Outcome: "completed"},
+			{ContainerID: "b", StartedAt: base.Add(10 * time.Minute), FinishedAt: finished(15), ExitCode: code(2), Outcome: "failed"},
+			{ContainerID: "c", StartedAt: base.Add(20 * time.Minute), Outcome: state.OutcomeRunning},
+			{ContainerID: "d", StartedAt: base.Add(30 * time.Minute), FinishedAt: finished(40), ExitCode: code(0), Outcome: "completed"},
+		}
+	}
+
+	tests := []struct {
+		key     string
+		reverse bool
+		want    string
+	}{
+		{"", false, "dcba"},
+		{"", true, "abcd"},
+		{"started", false, "dcba"},
+		{"duration", false, "cadb"},
+		{"duration", true, "bdac"},
+		{"exit-code", false, "bdac"},
+		{"exit-code", true, "cdab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s reverse=%v", tt.key, tt.reverse), func(t *testing.T) {
+			rs := records()
+			sortHistory(rs, tt.key, tt.reverse, now)
+			var got string
+			for _, r := range rs {
+				got += r.ContainerID
+			}
+			if got != tt.want {
+				t.Errorf("order = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterFailedRuns(t *testing.T) {
+	code := func(c int) *int { return &c }
+	records := []state.HistoryRecord{
+		{ContainerID: "a", ExitCode: code(0), Outcome: "completed"},
+		{ContainerID: "b", ExitCode: code(1), Outcome: "failed"},
+		{ContainerID: "c", Outcome: state.OutcomeRunning},
+		{ContainerID: "d", ExitCode: code(137), Outcome: state.OutcomeTerminated},
+	}
+
+	got := filterFailedRuns(records)
+	if len(got) != 2 || got[0].ContainerID != "b" || got[1].ContainerID != "d" {
+		t.Errorf("filterFailedRuns = %+v, want runs b and d", got)
+	}
+}
+
+func TestSummarizeResources(t *testing.T) {
+	const gb = 1 << 30
+	host := docker.HostResources{CPUs: 4, MemoryBytes: 8 * gb}
+
+	report := summarizeResources(host, []simulationResources{
+		{ID: "a", CPUs: 1.5, MemoryBytes: 2 * gb, MemoryLimitBytes: 6 * gb},
+		{ID: "b", CPUs: 0.5, MemoryBytes: 1 * gb, MemoryLimitBytes: 4 * gb},
+	})
+
+	if report.CPUsUsed != 2 || report.CPUHeadroom != 2 {
+		t.Errorf("CPUs used/headroom = %v/%v, want 2/2", report.CPUsUsed, report.CPUHeadroom)
+	}
+	if report.MemoryUsedBytes != 3*gb || report.MemoryHeadroomBytes != 5*gb {
+		t.Errorf("memory used/headroom = %d/%d, want %d/%d", report.MemoryUsedBytes, report.MemoryHeadroomBytes, 3*gb, 5*gb)
+	}
+	if report.MemoryLimitBytes != 10*gb || !report.MemoryOvercommitted {
+		t.Errorf("memory limits = %d (overcommitted %v), want %d overcommitted", report.MemoryLimitBytes, report.MemoryOvercommitted, 10*gb)
+	}
+
+	empty := summarizeResources(host, nil)
+	if empty.Simulations == nil || empty.MemoryOvercommitted || empty.CPUHeadroom != 4 {
+		t.Errorf("empty report = %+v, want full headroom and no overcommit", empty)
+	}
+}
+
+func TestJSONIndent(t *testing.T) {
+	tests := []struct {
+		width   int
+		char    string
+		want    string
+		wantErr bool
+	}{
+		{2, "space", "  ", false},
+		{4, "space", "    ", false},
+		{1, "tab", "\t", false},
+		{0, "tab", "", false},
+		{-1, "space", "", true},
+		{2, "dot", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := jsonIndent(tt.width, tt.char)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("jsonIndent(%d, %q) error = %v, wantErr %v", tt.width, tt.char, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("jsonIndent(%d, %q) = %q, want %q", tt.width, tt.char, got, tt.want)
+		}
+	}
+}
+
+// A bad indent must be rejected before the command runs, not after it has
+// done its work and comes to print the result.
+func TestRootRejectsInvalidJSONIndent(t *testing.T) {
+	defer func() { jsonIndentWidth, jsonIndentChar = 2, "space" }()
+
+	jsonIndentWidth, jsonIndentChar = -1, "space"
+	if err := rootCmd.PersistentPreRunE(versionCmd, nil); err == nil {
+		t.Error("PersistentPreRunE() with --json-indent -1 = nil, want an error")
+	}
+	jsonIndentWidth, jsonIndentChar = 2, "dots"
+	if err := rootCmd.PersistentPreRunE(versionCmd, nil); err == nil {
+		t.Error("PersistentPreRunE() with --json-indent-char dots = nil, want an error")
+	}
+}
+
+func TestTotalMetrics(t *testing.T) {
+	rows := []simulationMetrics{
+		{ID: "a", Metrics: &models.Metrics{CPUUsage: 10, MemoryUsage: 20,
+			NetworkIO: models.NetworkStats{BytesReceived: 100, BytesTransmitted: 50},
+			DiskIO:    models.DiskStats{BytesRead: 1000, BytesWritten: 10}}},
+		{ID: "b", Metrics: &models.Metrics{CPUUsage: 30, MemoryUsage: 40,
+			NetworkIO: models.NetworkStats{BytesReceived: 200, BytesTransmitted: 25},
+			DiskIO:    models.DiskStats{BytesRead: 500, BytesWritten: 5}}},
+	}
+
+	total := totalMetrics(rows)
+	if total.CPUUsage != 20 || total.MemoryUsage != 30 {
+		t.Errorf("CPU/memory = %v/%v, want averages 20/30", total.CPUUsage, total.MemoryUsage)
+	}
+	if total.NetworkIO.BytesReceived != 300 || total.NetworkIO.BytesTransmitted != 75 {
+		t.Errorf("network = %+v, want sums 300/75", total.NetworkIO)
+	}
+	if total.DiskIO.BytesRead != 1500 || total.DiskIO.BytesWritten != 15 {
+		t.Errorf("disk = %+v, want sums 1500/15", total.DiskIO)
+	}
+
+	if empty := totalMetrics(nil); empty.CPUUsage != 0 {
+		t.Errorf("totalMetrics(nil) CPU = %v, want 0", empty.CPUUsage)
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+	if got := editorCommand(); got != "nano" {
+		t.Errorf("editorCommand() = %q, want nano", got)
+	}
+
+	t.Setenv("VISUAL", "code --wait")
+	if got := editorCommand(); got != "code --wait" {
+		t.Errorf("editorCommand() = %q, want $VISUAL to take precedence", got)
+	}
+}
+
+func TestProfileSummary(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var s profileSummary
+	if s.AverageCPU() != 0 {
+		t.Errorf("AverageCPU() of empty summary = %v, want 0", s.AverageCPU())
+	}
+
+	s.add(&models.Metrics{CPUUsage: 50, MemoryUsage: 10, Timestamp: start,
+		NetworkIO: models.NetworkStats{BytesReceived: 100}})
+	s.add(&models.Metrics{CPUUsage: 150, MemoryUsage: 40, Timestamp: start.Add(2 * time.Second),
+		NetworkIO: models.NetworkStats{BytesReceived: 300}})
+	s.add(&models.Metrics{CPUUsage: 100, MemoryUsage: 30, Timestamp: start.Add(4 * time.Second),
+		NetworkIO: models.NetworkStats{BytesReceived: 900}, DiskIO: models.DiskStats{BytesWritten: 42}})
+
+	if s.Samples != 3 || s.PeakCPU != 150 || s.PeakMemory != 40 || s.AverageCPU() != 100 {
+		t.Errorf("summary = %+v (average %v), want 3 samples, peaks 150/40, average 100", s, s.AverageCPU())
+	}
+	if s.Last.Sub(s.First) != 4*time.Second {
+		t.Errorf("span = %s, want 4s", s.Last.Sub(s.First))
+	}
+	if s.NetworkIO.BytesReceived != 900 || s.DiskIO.BytesWritten != 42 {
+		t.Errorf("totals = %+v %+v, want the last sample's cumulative counters", s.NetworkIO, s.DiskIO)
+	}
+}
+
+func TestValidateCustomLabels(t *testing.T) {
+	if err := validateCustomLabels(map[string]string{"team": "research", "org.example.cost": "42"}); err != nil {
+		t.Errorf("validateCustomLabels(custom) = %v, want nil", err)
+	}
+
+	err := validateCustomLabels(map[string]string{
+		"team":                 "research",
+		"com.autobox.name":     "spoofed",
+		"com.autobox.future":   "x",
+		"com.autobox.metadata": "{}",
+	})
+	if err == nil {
+		t.Fatal("validateCustomLabels(reserved) = nil, want an error")
+	}
+	for _, want := range []string{"com.autobox.future, com.autobox.metadata, com.autobox.name", "com.autobox.metadata, com.autobox.name identify"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "team") {
+		t.Errorf("error %q lists a non-reserved key", err)
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	labels, err := parseLabels("--label", []string{"team=research", "empty=", "url=http://x?a=b"})
+	if err != nil {
+		t.Fatalf("parseLabels: %v", err)
+	}
+	want := map[string]string{"team": "research", "empty": "", "url": "http://x?a=b"}
+	if fmt.Sprint(labels) != fmt.Sprint(want) {
+		t.Errorf("parseLabels = %v, want %v", labels, want)
+	}
+
+	if _, err := parseLabels("--label", []string{"=value"}); err == nil {
+		t.Error("parseLabels(=value) = nil error, want an error")
+	}
+	if _, err := parseLabels("--label", []string{"com.autobox.simulation=false"}); err == nil {
+		t.Error("parseLabels(reserved) = nil error, want an error")
+	}
+}
+
+func TestRestartArgs(t *testing.T) {
+	if err := restartCmd.Args(restartCmd, nil); err == nil {
+		t.Error("restart with no arguments = nil error, want an error")
+	}
+	if err := restartCmd.Args(restartCmd, []string{"abc123", "def456"}); err == nil {
+		t.Error("restart with two arguments = nil error, want an error")
+	}
+	if err := restartCmd.Args(restartCmd, []string{"abc123"}); err != nil {
+		t.Errorf("restart abc123 = %v, want nil", err)
+	}
+
+	for _, tt := range []struct {
+		timeout time.Duration
+		wantErr bool
+	}{{30 * time.Second, false}, {0, false}, {-time.Second, true}} {
+		if err := validateRestartTimeout(tt.timeout); (err != nil) != tt.wantErr {
+			t.Errorf("validateRestartTimeout(%s) = %v, wantErr %v", tt.timeout, err, tt.wantErr)
+		}
+	}
+
+	// --timeout takes the same extended durations as every other duration flag.
+	defer restartCmd.Flags().Set("timeout", "30s")
+	if err := restartCmd.Flags().Set("timeout", "1d"); err != nil || restartTimeout != 24*time.Hour {
+		t.Errorf("--timeout 1d = %s, %v; want 24h", restartTimeout, err)
+	}
+}
+
+func TestPauseUnpauseArgs(t *testing.T) {
+	for _, cmd := range []*cobra.Command{pauseCmd, unpauseCmd} {
+		if err := cmd.Args(cmd, nil); err == nil {
+			t.Errorf("%s with no arguments = nil error, want an error", cmd.Name())
+		}
+		if err := cmd.Args(cmd, []string{"abc123", "def456"}); err == nil {
+			t.Errorf("%s with two arguments = nil error, want an error", cmd.Name())
+		}
+		if err := cmd.Args(cmd, []string{"abc123"}); err != nil {
+			t.Errorf("%s abc123 = %v, want nil", cmd.Name(), err)
+		}
+	}
+}
+
+func TestClipLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{"Short line", "abc", 10, "abc"},
+		{"Cut", "abcdef", 4, "abcd"},
+		{"No width", "abcdef", 0, "abcdef"},
+		{"Colors don't count", "\x1b[32mrunning\x1b[0m ok", 9, "\x1b[32mrunning\x1b[0m o\x1b[0m"},
+		{"Cut inside a color", "\x1b[36mabc123def456\x1b[0m", 6, "\x1b[36mabc123\x1b[0m"},
+		{"Multibyte", "▶ Found", 3, "▶ F"},
+		{"Each line", "abcdef\nxy\n", 3, "abc\nxy\n"},
+		{"Escape later in the line", "ab\x1b[1mcd\x1b[0mef", 3, "ab\x1b[1mc\x1b[0m"},
+		{"Not an escape", "\x1bxyz", 2, "\x1bx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clipLines(tt.in, tt.width); got != tt.want {
+				t.Errorf("clipLines(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchHeader(t *testing.T) {
+	now := time.Date(2025, 1, 15, 14, 30, 5, 0, time.UTC)
+
+	got := watchHeader("autobox list", 2*time.Second, now, 60)
+	if len(got) != 60 || !strings.HasPrefix(got, "Every 2s: autobox list") || !strings.HasSuffix(got, "2025-01-15 14:30:05") {
+		t.Errorf("watchHeader(width 60) = %q, want the time right-aligned in 60 columns", got)
+	}
+	if got := watchHeader("autobox list", 2*time.Second, now, 0); got != "Every 2s: autobox list  2025-01-15 14:30:05" {
+		t.Errorf("watchHeader(unknown width) = %q", got)
+	}
+}
+
+func TestSplitExecArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantID      string
+		wantCommand []string
+		wantIT      bool
+		wantErr     bool
+	}{
+		{"Command after --", []string{"abc123", "--", "ls", "-la"}, "abc123", []string{"ls", "-la"}, false, false},
+		{"Flags before -- are ours", []string{"-it", "abc123", "--", "/bin/sh"}, "abc123", []string{"/bin/sh"}, true, false},
+		{"Flags after the ID", []string{"abc123", "-it", "--", "sh", "-c", "echo -i"}, "abc123", []string{"sh", "-c", "echo -i"}, true, false},
+		{"Command flags after -- are not ours", []string{"abc123", "--", "grep", "-it", "x"}, "abc123", []string{"grep", "-it", "x"}, false, false},
+		{"No -- needed without command flags", []string{"abc123", "env"}, "abc123", []string{"env"}, false, false},
+		{"No command", []string{"abc123", "--"}, "", nil, false, true},
+		{"No ID", []string{"--", "ls"}, "", nil, false, true},
+		{"Two IDs", []string{"abc123", "def456", "--", "ls"}, "", nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var interactive, tty bool
+			cmd := &cobra.Command{Use: "exec"}
+			cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "")
+			cmd.Flags().BoolVarP(&tty, "tty", "t", false, "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags(%v) error = %v", tt.args, err)
+			}
+
+			id, command, err := splitExecArgs(cmd.Flags().Args(), cmd.ArgsLenAtDash())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitExecArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if id != tt.wantID || !reflect.DeepEqual(command, tt.wantCommand) {
+				t.Errorf("splitExecArgs() = %q, %q, want %q, %q", id, command, tt.wantID, tt.wantCommand)
+			}
+			if interactive != tt.wantIT || tty != tt.wantIT {
+				t.Errorf("-i = %v, -t = %v, want both %v", interactive, tty, tt.wantIT)
+			}
+		})
+	}
+}
+
+func TestWriteMetricsRecordNDJSON(t *testing.T) {
+	start := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	for i := 0; i < 3; i++ {
+		sample := &models.Metrics{CPUUsage: float64(10 * i), Timestamp: start.Add(time.Duration(i) * time.Second)}
+		if err := writeMetricsRecord(&buf, "json", sample); err != nil {
+			t.Fatalf("writeMetricsRecord() error = %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("wrote %d lines, want one per sample: %q", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d is not a JSON object: %v (%q)", i, err, line)
+		}
+		want := start.Add(time.Duration(i) * time.Second).Format(time.RFC3339)
+		if record["timestamp"] != want {
+			t.Errorf("line %d timestamp = %v, want %s", i, record["timestamp"], want)
+		}
+		if record["cpu_usage"] != float64(10*i) {
+			t.Errorf("line %d cpu_usage = %v, want %d", i, record["cpu_usage"], 10*i)
+		}
+	}
+}
+
+// fakeSimulationManager serves a fixed set of simulations in place of Docker.
+type fakeSimulationManager struct {
+	simulations []*models.Simulation
+	inspected   map[string]*models.Simulation
+	listErr     error
+	closed      bool
+	// stopped, killed and removed record the container IDs acted on.
+	stopped, killed, removed []string
+}
+
+func (f *fakeSimulationManager) LaunchSimulation(ctx context.Context, config models.SimulationConfig) (*models.Simulation, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeSimulationManager) ListSimulations(ctx context.Context) ([]*models.Simulation, error) {
+	return f.simulations, f.listErr
+}
+
+func (f *fakeSimulationManager) ResolveSimulation(ctx context.Context, ref string) (*models.Simulation, error) {
+	for _, sim := range f.simulations {
+		if sim.ID == ref || sim.ContainerID == ref || sim.Name == ref {
+			return sim, nil
+		}
+	}
+	return nil, fmt.Errorf("no such container: %s", ref)
+}
+
+func (f *fakeSimulationManager) ResolveContainerID(ctx context.Context, simulationID string) (string, error) {
+	sim, err := f.ResolveSimulation(ctx, simulationID)
+	if err != nil {
+		return "", err
+	}
+	return sim.ContainerID, nil
+}
+
+func (f *fakeSimulationManager) GetSimulationStatus(ctx context.Context, simulationID string) (*models.Simulation, error) {
+	return f.InspectSimulation(ctx, simulationID)
+}
+
+func (f *fakeSimulationManager) InspectSimulation(ctx context.Context, simulationID string) (*models.Simulation, error) {
+	if sim, ok := f.inspected[simulationID]; ok {
+		return sim, nil
+	}
+	return nil, fmt.Errorf("no such container: %s", simulationID)
+}
+
+func (f *fakeSimulationManager) IsSimulationRunning(ctx context.Context, simulationID string) (bool, error) {
+	sim, err := f.ResolveSimulation(ctx, simulationID)
+	if err != nil {
+		return false, err
+	}
+	return sim.Status == models.StatusRunning, nil
+}
+
+func (f *fakeSimulationManager) GetSimulationMetrics(ctx context.Context, simulationID string) (*models.Metrics, error) {
+	return &models.Metrics{}, nil
+}
+
+func (f *fakeSimulationManager) StreamSimulationMetrics(ctx context.Context, simulationID string) (<-chan *models.Metrics, <-chan error) {
+	metrics := make(chan *models.Metrics)
+	errs := make(chan error)
+	close(metrics)
+	return metrics, errs
+}
+
+func (f *fakeSimulationManager) StopSimulation(ctx context.Context, simulationID string) error {
+	f.stopped = append(f.stopped, simulationID)
+	return nil
+}
+
+func (f *fakeSimulationManager) KillSimulation(ctx context.Context, simulationID string) error {
+	f.killed = append(f.killed, simulationID)
+	return nil
+}
+
+func (f *fakeSimulationManager) RemoveSimulation(ctx context.Context, simulationID string, opts docker.RemoveOptions) error {
+	f.removed = append(f.removed, simulationID)
+	return nil
+}
+
+func (f *fakeSimulationManager) GetSimulationLogs(ctx context.Context, simulationID string, opts docker.LogOptions) (string, error) {
+	return "", nil
+}
+
+func (f *fakeSimulationManager) GetSimulationLogsStream(ctx context.Context, simulationID string, opts docker.LogOptions) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (f *fakeSimulationManager) Close() error {
+	f.closed = true
+	return nil
+}
+
+// useFakeManager makes newSimulationManager return fake for the rest of the
+// test.
+func useFakeManager(t *testing.T, fake *fakeSimulationManager) {
+	t.Helper()
+	saved := newSimulationManager
+	newSimulationManager = func() (docker.SimulationManager, error) { return fake, nil }
+	t.Cleanup(func() { newSimulationManager = saved })
+}
+
+// captureStdout returns what fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+	saved := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = saved }()
+
+	captured := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		captured <- string(data)
+	}()
+
+	fnErr := fn()
+	w.Close()
+	return <-captured, fnErr
+}
+
+func TestStopAndTerminateWithFakeManager(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	sim := &models.Simulation{ID: "aaa111", ContainerID: "aaa111full", Name: "alpha", Status: models.StatusRunning}
+	fake := &fakeSimulationManager{
+		simulations: []*models.Simulation{sim},
+		inspected:   map[string]*models.Simulation{"aaa111full": sim},
+	}
+	useFakeManager(t, fake)
+
+	if _, err := captureStdout(t, func() error { return runStop(stopCmd, []string{"alpha"}) }); err != nil {
+		t.Fatalf("runStop() error = %v", err)
+	}
+	if !reflect.DeepEqual(fake.stopped, []string{"aaa111full"}) || len(fake.killed) != 0 {
+		t.Errorf("runStop() stopped %v and killed %v, want aaa111full stopped", fake.stopped, fake.killed)
+	}
+
+	terminateForce = true
+	t.Cleanup(func() { terminateForce = false })
+	if _, err := captureStdout(t, func() error { return runTerminate(terminateCmd, []string{"aaa111"}) }); err != nil {
+		t.Fatalf("runTerminate() error = %v", err)
+	}
+	if !reflect.DeepEqual(fake.removed, []string{"aaa111full"}) {
+		t.Errorf("runTerminate() removed %v, want aaa111full", fake.removed)
+	}
+	if !fake.closed {
+		t.Error("the manager was not closed")
+	}
+}
+
+func TestRunListWithFakeManager(t *testing.T) {
+	saved := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = saved })
+
+	created := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	fake := &fakeSimulationManager{
+		simulations: []*models.Simulation{
+			{ID: "aaa111", ContainerID: "aaa111full", Name: "alpha", Status: models.StatusRunning, CreatedAt: created,
+				Config: models.SimulationConfig{Metadata: map[string]interface{}{"experiment": 42.0}}},
+			{ID: "bbb222", ContainerID: "bbb222full", Name: "beta", Status: models.StatusCompleted, CreatedAt: created},
+			{ID: "ccc333", ContainerID: "ccc333full", Name: "gamma", Status: models.StatusFailed, CreatedAt: created,
+				Config: models.SimulationConfig{Metadata: map[string]interface{}{"experiment": 42.0}}},
+			{ID: "ddd444", ContainerID: "ddd444full", Name: "delta", Status: models.StatusRunning, CreatedAt: created},
+		},
+	}
+	useFakeManager(t, fake)
+
+	setListFlags := func(all bool, metaFilter []string) {
+		listAll, listMetaFilter, listWide, listYAMLDocs, output = all, metaFilter, false, false, "table"
+	}
+	t.Cleanup(func() { setListFlags(false, nil) })
+
+	tests := []struct {
+		name        string
+		all         bool
+		metaFilter  []string
+		wantNames   []string
+		wantSummary string
+	}{
+		{"Running only by default", false, nil, []string{"alpha", "delta"}, "Summary: 2 running"},
+		{"All", true, nil, []string{"alpha", "beta", "gamma", "delta"}, "Summary: 2 running 1 completed 1 failed"},
+		{"Metadata filter", true, []string{"experiment=42"}, []string{"alpha", "gamma"}, "Summary: 1 running 1 failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setListFlags(tt.all, tt.metaFilter)
+			out, err := captureStdout(t, func() error { return runList(listCmd, nil) })
+			if err != nil {
+				t.Fatalf("runList() error = %v", err)
+			}
+
+			if !strings.Contains(out, fmt.Sprintf("Found %d simulation(s)", len(tt.wantNames))) {
+				t.Errorf("runList() output doesn't report %d simulations:\n%s", len(tt.wantNames), out)
+			}
+			for _, sim := range fake.simulations {
+				want := false
+				for _, name := range tt.wantNames {
+					want = want || name == sim.Name
+				}
+				if got := strings.Contains(out, sim.Name); got != want {
+					t.Errorf("output lists %s = %v, want %v", sim.Name, got, want)
+				}
+			}
+			if !strings.Contains(out, tt.wantSummary) {
+				t.Errorf("runList() output has no %q:\n%s", tt.wantSummary, out)
+			}
+		})
+	}
+
+	if !fake.closed {
+		t.Error("runList() didn't close the manager")
+	}
+
+	fake.listErr = errors.New("daemon went away")
+	if err := runList(listCmd, nil); err == nil || !strings.Contains(err.Error(), "daemon went away") {
+		t.Errorf("runList() with a failing list = %v, want the list error", err)
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	now := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	simulations := []*models.Simulation{
+		{ID: "1", Name: "gift_choice", Status: models.StatusRunning, CreatedAt: now.Add(-time.Hour)},
+		{ID: "2", Name: "Gift_Choice_v2", Status: models.StatusFailed, CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "3", Name: "summer_camp", Status: models.StatusFailed, CreatedAt: now.Add(-30 * time.Minute),
+			Config: models.SimulationConfig{Metadata: map[string]interface{}{"owner": "me"}}},
+		{ID: "4", Name: "summer_camp", Status: models.StatusCompleted, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "5", Name: "gift_choice", Status: models.StatusPaused, CreatedAt: now.Add(-10 * time.Minute)},
+	}
+
+	reset := func() {
+		listAll, listStatus, listName, listSince, listMetaFilter = false, nil, "", 0, nil
+	}
+	t.Cleanup(reset)
+
+	tests := []struct {
+		name    string
+		all     bool
+		status  []string
+		match   string
+		since   time.Duration
+		meta    []string
+		wantIDs []string
+		wantErr string
+	}{
+		{name: "Running and paused by default", wantIDs: []string{"1", "5"}},
+		{name: "All", all: true, wantIDs: []string{"1", "2", "3", "4", "5"}},
+		{name: "Several statuses", status: []string{"running", "FAILED"}, wantIDs: []string{"1", "2", "3"}},
+		{name: "Name ignores case", all: true, match: "gift", wantIDs: []string{"1", "2", "5"}},
+		{name: "Name without --all keeps the running default", match: "summer"},
+		{name: "Since", all: true, since: 90 * time.Minute, wantIDs: []string{"1", "3", "5"}},
+		{name: "Status, name and since", status: []string{"failed", "paused"}, match: "gift", since: 24 * time.Hour, wantIDs: []string{"5"}},
+		{name: "Status and metadata", status: []string{"failed"}, meta: []string{"owner=me"}, wantIDs: []string{"3"}},
+		{name: "Unknown status", status: []string{"done"}, wantErr: `invalid --status "done"`},
+		{name: "Negative since", all: true, since: -time.Hour, wantErr: "--since must not be negative"},
+		{name: "Bad metadata filter", all: true, meta: []string{"owner"}, wantErr: "invalid --meta-filter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listAll, listStatus, listName, listSince, listMetaFilter = tt.all, tt.status, tt.match, tt.since, tt.meta
+			filters, err := listFilters(now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("listFilters() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("listFilters() error = %v", err)
+			}
+
+			var gotIDs []string
+			for _, sim := range applyFilters(simulations, filters...) {
+				gotIDs = append(gotIDs, sim.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("filtered IDs = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestApplyFiltersWithoutFilters(t *testing.T) {
+	simulations := []*models.Simulation{{ID: "1"}, {ID: "2"}}
+	if got := applyFilters(simulations); len(got) != 2 {
+		t.Errorf("applyFilters() with no filters kept %d simulations, want 2", len(got))
+	}
+}
+
+func TestSortSimulations(t *testing.T) {
+	base := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	newSimulations := func() []*models.Simulation {
+		return []*models.Simulation{
+			{ID: "ccc", Name: "beta", Status: models.StatusRunning, CreatedAt: base.Add(time.Hour)},
+			{ID: "aaa", Name: "gamma", Status: models.StatusFailed, CreatedAt: base},
+			{ID: "ddd", Name: "alpha", Status: models.StatusRunning, CreatedAt: base.Add(2 * time.Hour)},
+			{ID: "bbb", Name: "beta", Status: models.StatusCompleted, CreatedAt: base.Add(time.Hour)},
+		}
+	}
+
+	tests := []struct {
+		key     string
+		reverse bool
+		want    []string
+	}{
+		{"created", false, []string{"ddd", "ccc", "bbb", "aaa"}},
+		{"created", true, []string{"aaa", "ccc", "bbb", "ddd"}},
+		{"name", false, []string{"ddd", "ccc", "bbb", "aaa"}},
+		{"name", true, []string{"aaa", "ccc", "bbb", "ddd"}},
+		{"status", false, []string{"bbb", "aaa", "ccc", "ddd"}},
+		{"status", true, []string{"ccc", "ddd", "aaa", "bbb"}},
+		{"id", false, []string{"aaa", "bbb", "ccc", "ddd"}},
+		{"id", true, []string{"ddd", "ccc", "bbb", "aaa"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s reverse=%v", tt.key, tt.reverse), func(t *testing.T) {
+			simulations := newSimulations()
+			sortSimulations(simulations, tt.key, tt.reverse)
+
+			var got []string
+			for _, sim := range simulations {
+				got = append(got, sim.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortSimulations(%s, %v) = %v, want %v", tt.key, tt.reverse, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewListOutput(t *testing.T) {
+	simulations := []*models.Simulation{
+		{ID: "aaa111", Status: models.StatusRunning},
+		{ID: "bbb222", Status: models.StatusCompleted},
+		{ID: "ccc333", Status: models.StatusFailed},
+		{ID: "ddd444", Status: models.StatusRunning},
+		{ID: "eee555", Status: models.StatusPaused},
+		{ID: "fff666", Status: models.StatusStopped},
+		{ID: "ggg777", Status: models.StatusPending},
+	}
+
+	got := newListOutput(simulations).Summary
+	want := listSummary{Pending: 1, Running: 2, Paused: 1, Completed: 1, Failed: 1, Stopped: 1, Total: 7}
+	if got != want {
+		t.Errorf("newListOutput().Summary = %+v, want %+v", got, want)
+	}
+	if sum := got.Pending + got.Running + got.Paused + got.Completed + got.Failed + got.Stopped; sum != got.Total {
+		t.Errorf("summary counts add up to %d, want the total %d", sum, got.Total)
+	}
+
+	data, err := json.Marshal(newListOutput(nil))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"simulations":[]`) {
+		t.Errorf("empty list output = %s, want an empty simulations array", data)
+	}
+}
+
+func TestRunListCSV(t *testing.T) {
+	created := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	exitCode := 1
+	useFakeManager(t, &fakeSimulationManager{
+		simulations: []*models.Simulation{
+			{ID: "aaa111", ContainerID: "aaa111full", ContainerName: "autobox-alpha-1a2b3c", Name: "alpha",
+				Status: models.StatusRunning, CreatedAt: created, Config: models.SimulationConfig{Image: "autobox-engine:latest"}},
+			{ID: "bbb222", ContainerID: "bbb222full", Name: "beta, the second", Status: models.StatusFailed,
+				CreatedAt: created, FinishedAt: &created, ExitCode: &exitCode, RestartCount: 2},
+		},
+	})
+	listAll, output = true, "csv"
+	t.Cleanup(func() { listAll, output = false, "table" })
+
+	out, err := captureStdout(t, func() error { return runList(listCmd, nil) })
+	if err != nil {
+		t.Fatalf("runList() error = %v", err)
+	}
+
+	want := `id,name,status,container_id,container_name,image,created_at,started_at,finished_at,exit_code,restart_count
+aaa111,alpha,running,aaa111full,autobox-alpha-1a2b3c,autobox-engine:latest,2024-01-15T14:30:00Z,,,,0
+bbb222,"beta, the second",failed,bbb222full,,,2024-01-15T14:30:00Z,,2024-01-15T14:30:00Z,1,2
+`
+	if out != want {
+		t.Errorf("runList() csv =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestWriteMetricsCSV(t *testing.T) {
+	metrics := &models.Metrics{
+		CPUUsage:    12.345,
+		MemoryUsage: 50,
+		NetworkIO:   models.NetworkStats{BytesReceived: 1024, BytesTransmitted: 2048},
+		DiskIO:      models.DiskStats{BytesRead: 10, BytesWritten: 20},
+		Timestamp:   time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC),
+	}
+
+	var single bytes.Buffer
+	if err := writeCSV(&single, metrics); err != nil {
+		t.Fatalf("writeCSV() error = %v", err)
+	}
+	want := `cpu_usage,memory_usage,net_rx_bytes,net_tx_bytes,disk_read_bytes,disk_write_bytes,timestamp
+12.35,50.00,1024,2048,10,20,2024-01-15T14:30:00Z
+`
+	if single.String() != want {
+		t.Errorf("writeCSV(metrics) =\n%s\nwant\n%s", single.String(), want)
+	}
+
+	var all bytes.Buffer
+	if err := writeCSV(&all, []simulationMetrics{{ID: "aaa111", Name: "alpha", Metrics: metrics}}); err != nil {
+		t.Fatalf("writeCSV() error = %v", err)
+	}
+	want = `id,name,cpu_usage,memory_usage,net_rx_bytes,net_tx_bytes,disk_read_bytes,disk_write_bytes,timestamp
+aaa111,alpha,12.35,50.00,1024,2048,10,20,2024-01-15T14:30:00Z
+`
+	if all.String() != want {
+		t.Errorf("writeCSV(rows) =\n%s\nwant\n%s", all.String(), want)
+	}
+
+	if err := writeCSV(&bytes.Buffer{}, map[string]string{}); err == nil {
+		t.Error("writeCSV() of an unsupported type succeeded, want an error")
+	}
+}
+
+func TestSupportsCSV(t *testing.T) {
+	for _, cmd := range []*cobra.Command{listCmd, statusCmd, metricsCmd} {
+		if !supportsCSV(cmd) {
+			t.Errorf("supportsCSV(%s) = false, want true", cmd.Name())
+		}
+	}
+	for _, cmd := range []*cobra.Command{historyCmd, inspectCmd, statsCmd} {
+		if supportsCSV(cmd) {
+			t.Errorf("supportsCSV(%s) = true, want false", cmd.Name())
+		}
+	}
+}
+
+func TestJSONToYAMLNode(t *testing.T) {
+	data := struct {
+		ID    string                 `json:"Id"`
+		State map[string]interface{} `json:"State"`
+		Env   []string               `json:"Env"`
+		Tags  map[string]string      `json:"Labels"`
+	}{
+		ID:    "abc123",
+		State: map[string]interface{}{"ExitCode": 137, "OOMKilled": true},
+		Env:   []string{"A=1"},
+		Tags:  map[string]string{"com.autobox.simulation": "true"},
+	}
+
+	node, err := jsonToYAMLNode(data)
+	if err != nil {
+		t.Fatalf("jsonToYAMLNode() error = %v", err)
+	}
+	encoded, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatalf("yaml.Marshal() error = %v", err)
+	}
+
+	want := `Id: abc123
+State:
+    ExitCode: 137
+    OOMKilled: true
+Env:
+    - A=1
+Labels:
+    com.autobox.simulation: "true"
+`
+	if string(encoded) != want {
+		t.Errorf("YAML =\n%s\nwant\n%s", encoded, want)
+	}
+}
+
+func TestWaitExitCode(t *testing.T) {
+	code := func(n int) *int { return &n }
+	tests := []struct {
+		name     string
+		status   models.SimulationStatus
+		exitCode *int
+		expected int
+	}{
+		{"Completed", models.StatusCompleted, code(0), 0},
+		{"Completed per engine, still up", models.StatusCompleted, nil, 0},
+		{"Failed", models.StatusFailed, code(2), 2},
+		{"OOM killed", models.StatusFailed, code(137), 137},
+		{"Failed per engine, still up", models.StatusFailed, nil, 1},
+		{"Failed with exit code 0", models.StatusFailed, code(0), 1},
+		{"Stopped", models.StatusStopped, code(143), 143},
+		{"Out of range", models.StatusFailed, code(-1), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := &models.Simulation{Status: tt.status, ExitCode: tt.exitCode}
+			if got := waitExitCode(sim); got != tt.expected {
+				t.Errorf("waitExitCode() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+
+	for _, status := range []models.SimulationStatus{models.StatusRunning, models.StatusPaused, models.StatusPending} {
+		if isTerminalStatus(status) {
+			t.Errorf("isTerminalStatus(%s) = true, want the wait to go on", status)
+		}
+	}
+}
+
+func TestIsPrunable(t *testing.T) {
+	now := time.Now()
+	at := func(ago time.Duration) *time.Time {
+		finished := now.Add(-ago)
+		return &finished
+	}
+	tests := []struct {
+		name       string
+		sim        models.Simulation
+		status     models.SimulationStatus
+		cutoff     time.Time
+		wantPruned bool
+	}{
+		{"Completed", models.Simulation{Status: models.StatusCompleted, FinishedAt: at(time.Hour)}, "", time.Time{}, true},
+		{"Failed", models.Simulation{Status: models.StatusFailed}, "", time.Time{}, true},
+		{"Stopped", models.Simulation{Status: models.StatusStopped}, "", time.Time{}, true},
+		{"Running", models.Simulation{Status: models.StatusRunning}, "", time.Time{}, false},
+		{"Paused", models.Simulation{Status: models.StatusPaused}, "", time.Time{}, false},
+		{"Pending", models.Simulation{Status: models.StatusPending}, "", time.Time{}, false},
+		{"Status filter matches", models.Simulation{Status: models.StatusFailed}, models.StatusFailed, time.Time{}, true},
+		{"Status filter excludes", models.Simulation{Status: models.StatusCompleted}, models.StatusFailed, time.Time{}, false},
+		{"Finished before cutoff", models.Simulation{Status: models.StatusCompleted, FinishedAt: at(48 * time.Hour)}, "", now.Add(-24 * time.Hour), true},
+		{"Finished after cutoff", models.Simulation{Status: models.StatusCompleted, FinishedAt: at(time.Hour)}, "", now.Add(-24 * time.Hour), false},
+		{"Unknown finish time with cutoff", models.Simulation{Status: models.StatusFailed}, "", now.Add(-24 * time.Hour), false},
+		{"Zero finish time with cutoff", models.Simulation{Status: models.StatusFailed, FinishedAt: &time.Time{}}, "", now.Add(-24 * time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPrunable(&tt.sim, tt.status, tt.cutoff); got != tt.wantPruned {
+				t.Errorf("isPrunable() = %v, want %v", got, tt.wantPruned)
+			}
+		})
+	}
+}
+
+func TestParseCopyPath(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected copyPath
+	}{
+		{"abc123def456:/app/logs", copyPath{Ref: "abc123def456", Path: "/app/logs"}},
+		{"gift_choice:/app/logs/results.json", copyPath{Ref: "gift_choice", Path: "/app/logs/results.json"}},
+		{"gift_choice:", copyPath{Ref: "gift_choice"}},
+		{"results.json", copyPath{Path: "results.json"}},
+		{".", copyPath{Path: "."}},
+		{"./a:b.json", copyPath{Path: "./a:b.json"}},
+		{"/tmp/a:b.json", copyPath{Path: "/tmp/a:b.json"}},
+		{"logs/a:b.json", copyPath{Path: "logs/a:b.json"}},
+		{":/app/logs", copyPath{Path: ":/app/logs"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseCopyPath(tt.arg); got != tt.expected {
+			t.Errorf("parseCopyPath(%q) = %+v, want %+v", tt.arg, got, tt.expected)
+		}
+	}
+}
+
+func TestParseCopyArgs(t *testing.T) {
+	tests := []struct {
+		src, dst string
+		wantErr  string
+	}{
+		{"abc123:/app/logs", "./logs", ""},
+		{"./agents.json", "abc123:/app/config/", ""},
+		{"abc123:/app/logs", "def456:/app/logs", "between two simulations"},
+		{"a.json", "b.json", "must be SIMULATION:PATH"},
+		{"abc123:", "./logs", "missing path"},
+	}
+
+	for _, tt := range tests {
+		_, _, err := parseCopyArgs(tt.src, tt.dst)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("parseCopyArgs(%q, %q) error = %v", tt.src, tt.dst, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseCopyArgs(%q, %q) error = %v, want %q", tt.src, tt.dst, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPreviousImageDigest(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2025, 1, n, 0, 0, 0, 0, time.UTC) }
+	records := []state.HistoryRecord{
+		{ContainerID: "a", Name: "gift_choice", Image: "autobox-engine:latest", ImageDigest: "autobox-engine@sha256:old", StartedAt: day(1)},
+		{ContainerID: "b", Name: "gift_choice", Image: "autobox-engine:latest", ImageDigest: "autobox-engine@sha256:new", StartedAt: day(3)},
+		{ContainerID: "c", Name: "gift_choice", Image: "autobox-engine:latest", StartedAt: day(4)},
+		{ContainerID: "d", Name: "gift_choice", Image: "autobox-engine:v2", ImageDigest: "autobox-engine@sha256:v2", StartedAt: day(5)},
+		{ContainerID: "e", Name: "summer_sale", Image: "autobox-engine:latest", ImageDigest: "autobox-engine@sha256:other", StartedAt: day(6)},
+	}
+
+	if got := previousImageDigest(records, "gift_choice", "autobox-engine:latest"); got != "autobox-engine@sha256:new" {
+		t.Errorf("previousImageDigest() = %q, want the latest run's digest", got)
+	}
+	if got := previousImageDigest(records, "gift_choice", "autobox-engine:v3"); got != "" {
+		t.Errorf("previousImageDigest() for a new tag = %q, want none", got)
+	}
+	if got := previousImageDigest(records, "new_sim", "autobox-engine:latest"); got != "" {
+		t.Errorf("previousImageDigest() for a new simulation = %q, want none", got)
+	}
+}
+
+func TestExpandFlagValue(t *testing.T) {
+	t.Setenv("AUTOBOX_DATA", "/data")
+
+	if got := expandFlagValue("--volume", "${AUTOBOX_DATA}/logs:/app/logs"); got != "/data/logs:/app/logs" {
+		t.Errorf("expandFlagValue() = %q, want /data/logs:/app/logs", got)
+	}
+	if got := expandFlagValue("--env", "$AUTOBOX_UNSET_FOR_TEST"); got != "$AUTOBOX_UNSET_FOR_TEST" {
+		t.Errorf("expandFlagValue() = %q, want the unset variable left as written", got)
+	}
+}
+
+func TestCommandsShareConfigPaths(t *testing.T) {
+	paths, err := config.Paths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := runCmd.Flags().Lookup("volume").DefValue; got != "["+paths.Config+":/app/config]" {
+		t.Errorf("run --volume default = %s, want %s mounted", got, paths.Config)
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	paths, _ = config.Paths()
+
+	checks := checkConfigDirectories()
+	if len(checks) != 2 || checks[0].detail != paths.Simulations+" missing; created on first run" ||
+		checks[1].detail != paths.Metrics+" missing; created on first run" {
+		t.Errorf("doctor config checks = %+v", checks)
+	}
+
+	dir, err := state.Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != paths.State {
+		t.Errorf("state.Dir() = %s, want %s", dir, paths.State)
+	}
+}
+
+func TestNamedSimulationPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	base := filepath.Join(home, ".autobox", "config")
+	configSet := &config.SimulationConfigSet{
+		SimulationPath: filepath.Join(base, "simulations", "market.json"),
+		MetricsPath:    filepath.Join(base, "metrics", "market.json"),
+		ServerPath:     filepath.Join(base, "server.json"),
+	}
+	configPath, metricsPath, serverPath := namedSimulationPaths(configSet)
+	if configPath != "/app/config/simulations/market.json" || metricsPath != "/app/config/metrics/market.json" || serverPath != "/app/config/server.json" {
+		t.Errorf("namedSimulationPaths() = %s, %s, %s", configPath, metricsPath, serverPath)
+	}
+
+	configSet.ServerPath = filepath.Join(base, "server", "market.json")
+	if _, _, serverPath := namedSimulationPaths(configSet); serverPath != "/app/config/server/market.json" {
+		t.Errorf("namedSimulationPaths() server = %s, want the per-name file", serverPath)
+	}
+
+	configSet.SimulationPath = filepath.Join(base, "runs", "market-123", "simulations", "market.json")
+	if configPath, _, _ := namedSimulationPaths(configSet); configPath != "/app/config/runs/market-123/simulations/market.json" {
+		t.Errorf("namedSimulationPaths() expanded config = %s, want it under /app/config/runs", configPath)
+	}
+}
+
+func TestRunDirectory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	runs := filepath.Join(home, ".autobox", "config", "runs")
+
+	tests := []struct {
+		configPath string
+		want       string
+	}{
+		{"/app/config/runs/market-123/simulations/market.json", filepath.Join(runs, "market-123")},
+		{"/app/config/simulations/market.json", ""},
+		{"/app/config/runs/../simulations/market.json", ""},
+		{"/app/config/runs/", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		sim := &models.Simulation{Config: models.SimulationConfig{ConfigPath: tt.configPath}}
+		if got := runDirectory(sim); got != tt.want {
+			t.Errorf("runDirectory(%q) = %q, want %q", tt.configPath, got, tt.want)
+		}
+	}
+}
+
+// The container must be given the expanded configs, since it reads the
+// files rather than what the CLI loaded.
+func TestLoadNamedSimulationWritesExpandedConfigs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AUTOBOX_DATA", "/data")
+	base := filepath.Join(home, ".autobox", "config")
+	files := map[string]string{
+		"simulations/expanded.json": `{"name": "expanded", "agents": [], "output": "${AUTOBOX_DATA}/out"}`,
+		"metrics/expanded.json":     `{"export": "$AUTOBOX_DATA/metrics"}`,
+		"simulations/literal.json":  `{"name": "literal", "agents": []}`,
+		"metrics/literal.json":      `{}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(base, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configSet, err := loadNamedSimulation("expanded")
+	if err != nil {
+		t.Fatalf("loadNamedSimulation() error = %v", err)
+	}
+	configPath, metricsPath, serverPath := namedSimulationPaths(configSet)
+	if !strings.HasPrefix(configPath, "/app/config/runs/expanded-") || !strings.HasPrefix(metricsPath, "/app/config/runs/expanded-") {
+		t.Errorf("namedSimulationPaths() = %s, %s, want the expanded copies", configPath, metricsPath)
+	}
+	if serverPath != "/app/config/server.json" {
+		t.Errorf("server path = %s, want the missing server.json left alone", serverPath)
+	}
+	for _, tt := range []struct{ containerPath, want string }{
+		{configPath, "/data/out"},
+		{metricsPath, "/data/metrics"},
+	} {
+		data, err := os.ReadFile(filepath.Join(base, strings.TrimPrefix(tt.containerPath, "/app/config/")))
+		if err != nil {
+			t.Fatalf("reading what the container is given: %v", err)
+		}
+		if !strings.Contains(string(data), tt.want) {
+			t.Errorf("%s = %s, want the expanded value %s", tt.containerPath, data, tt.want)
+		}
+	}
+
+	literal, err := loadNamedSimulation("literal")
+	if err != nil {
+		t.Fatalf("loadNamedSimulation() error = %v", err)
+	}
+	if configPath, _, _ := namedSimulationPaths(literal); configPath != "/app/config/simulations/literal.json" {
+		t.Errorf("namedSimulationPaths() = %s, want the file as written when nothing was expanded", configPath)
+	}
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	waitTimeout  time.Duration
+	waitInterval time.Duration
+	waitNotify   string
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait SIMULATION_ID|NAME",
+	Short: "Wait for a simulation to finish and exit with its result",
+	Long: `Block until a simulation completes, fails or is stopped, then exit 0 if it
+completed and non-zero otherwise: the engine's exit code when it has one, or 1.
+
+The wait ends as soon as the container exits, or when the engine's status
+endpoint reports a finished run while the container is still up. With
+--timeout, autobox gives up after that long and exits 1.
+
+With --notify, the result (ID, name, final status, exit code and duration) is
+POSTed as JSON to the URL once the simulation finishes, retrying failed
+requests with backoff. A wait with --notify keeps going if its terminal is
+closed, so it can be left running in the background.
+
+Examples:
+  autobox run gift_choice --detach
+  autobox wait gift_choice
+  autobox wait abc123def456 --timeout 2h
+  autobox wait gift_choice --notify https://hooks.example.com/autobox &`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if waitTimeout < 0 {
+			return fmt.Errorf("--timeout must not be negative")
+		}
+		if waitInterval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		if waitNotify != "" {
+			return validateWebhookURL(waitNotify)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		code, err := runWait(args[0])
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			os.Exit(code)
+		}
+		return nil
+	},
+}
+
+func init() {
+	waitCmd.Flags().Var(newExtendedDuration(0, &waitTimeout), "timeout", "Give up after this long (e.g. 30m, 2h; default no limit)")
+	waitCmd.Flags().Var(newExtendedDuration(5*time.Second, &waitInterval), "interval", "How often to poll the engine's status endpoint")
+	waitCmd.Flags().StringVar(&waitNotify, "notify", "", "URL to POST the result to as JSON once the simulation finishes")
+}
+
+func runWait(ref string) (int, error) {
+	if waitNotify != "" {
+		signal.Ignore(syscall.SIGHUP)
+	}
+
+	ctx := context.Background()
+	if waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitTimeout)
+		defer cancel()
+	}
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	simulationID, err := resolveSimulationID(ctx, client, ref)
+	if err != nil {
+		return 0, err
+	}
+
+	fmt.Printf("%s Waiting for simulation %s to finish...\n", color.YellowString("→"), ref)
+
+	// The container exiting is seen at once; an engine that reports a
+	// finished run but keeps its container up is caught by polling.
+	exited := make(chan error, 1)
+	go func() {
+		_, err := client.WaitForExit(ctx, simulationID)
+		exited <- err
+	}()
+
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+
+	for {
+		sim, err := client.GetSimulationStatus(ctx, simulationID)
+		if err != nil && ctx.Err() == nil {
+			if docker.IsNotFound(err) {
+				return 0, fmt.Errorf("simulation %s was removed while waiting", ref)
+			}
+			return 0, err
+		}
+		if err == nil && isTerminalStatus(sim.Status) {
+			printWaitResult(sim)
+			if waitNotify != "" {
+				// Not bound by --timeout: the wait itself is over.
+				if err := notifyCompletion(context.Background(), waitNotify, sim); err != nil {
+					fmt.Fprintf(os.Stderr, "%s Completion notification failed: %v\n", color.YellowString("⚠"), err)
+				}
+			}
+			return waitExitCode(sim), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return 0, fmt.Errorf("timed out after %s waiting for simulation %s", waitTimeout, ref)
+			}
+			return 0, ctx.Err()
+		case err := <-exited:
+			if err != nil && ctx.Err() == nil {
+				return 0, err
+			}
+			// Look at the final state straight away, and stop listening.
+			exited = nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitExitCode maps a finished simulation to autobox wait's exit code: 0 if
+// it completed, otherwise the engine's exit code, or 1 when that is zero or
+// unknown (e.g. the engine reported a failure but hasn't exited).
+func waitExitCode(sim *models.Simulation) int {
+	if sim.Status == models.StatusCompleted {
+		return 0
+	}
+	if sim.ExitCode != nil && *sim.ExitCode > 0 && *sim.ExitCode < 256 {
+		return *sim.ExitCode
+	}
+	return 1
+}
+
+func printWaitResult(sim *models.Simulation) {
+	result := fmt.Sprintf("Simulation %s %s", sim.ID, sim.Status)
+	if sim.ExitCode != nil {
+		result += fmt.Sprintf(" (exit code %d: %s)", *sim.ExitCode, describeExitCode(*sim.ExitCode, sim.OOMKilled))
+	}
+
+	if sim.Status == models.StatusCompleted {
+		fmt.Printf("%s %s\n", color.GreenString("✓"), result)
+	} else {
+		fmt.Printf("%s %s\n", color.RedString("✗"), result)
+	}
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Autobox-AI/autobox-cli/internal/docker"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+	"github.com/moby/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchInterval time.Duration
+	watchUntil    string
+	watchNotify   string
+)
+
+const (
+	untilAllCompleted = "all-completed"
+	untilNoneRunning  = "none-running"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Repeatedly list simulations",
+	Long: `Refresh the simulation list every interval until Ctrl+C.
+
+In a terminal, each refresh redraws the screen under a header with the time of
+the last update, and lines are cut to the terminal width so a narrow or
+resized window doesn't wrap the table. When the output is piped, frames are
+printed one after another instead.
+
+With --until, watch instead stops once a condition is met:
+
+  all-completed  every simulation is completed, failed or stopped
+  none-running   no simulation is running or paused (pending ones are ignored)
+
+When the condition is met, watch exits 0 if no simulation failed and 1 if
+any did, so it can gate a CI job. Interrupting with Ctrl+C exits 0.
+
+With --notify, watch runs in the background instead of drawing the table:
+every interval it polls each unfinished simulation's status, and when one
+completes, fails or is stopped, its ID, name, final status, exit code and
+duration are POSTed as JSON to the URL, retrying failed requests with backoff.
+Simulations that had already finished when watch started aren't reported.
+
+Examples:
+  autobox watch
+  autobox watch --interval 5s
+  autobox watch --until all-completed
+  nohup autobox watch --notify https://hooks.example.com/autobox &`,
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.Flags().Var(newExtendedDuration(2*time.Second, &watchInterval), "interval", "Refresh interval")
+	watchCmd.Flags().StringVar(&watchUntil, "until", "", "Exit once a condition is met (all-completed, none-running)")
+	watchCmd.Flags().StringVar(&watchNotify, "notify", "", "Instead of drawing the table, POST each simulation's result as JSON to this URL when it finishes")
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if watchInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	switch watchUntil {
+	case "", untilAllCompleted, untilNoneRunning:
+	default:
+		return fmt.Errorf("invalid --until %q (expected %s or %s)", watchUntil, untilAllCompleted, untilNoneRunning)
+	}
+	if watchNotify != "" {
+		if err := validateWebhookURL(watchNotify); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := docker.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer client.Close()
+
+	var tracker *completionTracker
+	if watchNotify != "" {
+		tracker = newCompletionTracker()
+		fmt.Printf("%s Notifying %s when simulations finish (Ctrl+C to stop)\n", color.YellowString("→"), watchNotify)
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		simulations, err := client.ListSimulations(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list simulations: %w", err)
+		}
+
+		if tracker != nil {
+			notifyFinished(ctx, client, tracker, simulations)
+		} else if err := drawWatchFrame(os.Stdout, simulations, time.Now()); err != nil {
+			return err
+		}
+
+		if watchUntil != "" && untilConditionMet(watchUntil, simulations) {
+			if failed := countByStatus(simulations, models.StatusFailed); failed > 0 {
+				return fmt.Errorf("%d simulation(s) failed", failed)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyFinished polls the status of every simulation that is, or was last
+// seen, unfinished, and sends --notify for each one that has just finished.
+// The engine can report a result before its container exits, so the list's
+// status alone isn't enough.
+func notifyFinished(ctx context.Context, client *docker.Client, tracker *completionTracker, simulations []*models.Simulation) {
+	tracker.forget(simulations)
+	for _, sim := range simulations {
+		if isTerminalStatus(sim.Status) && !tracker.tracking(sim) {
+			continue
+		}
+		current, err := client.GetSimulationStatus(ctx, sim.ContainerID)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "%s Failed to get the status of %s: %v\n", color.YellowString("⚠"), sim.ID, err)
+			}
+			continue
+		}
+		if !tracker.observe(current) {
+			continue
+		}
+
+		if err := notifyCompletion(ctx, watchNotify, current); err != nil {
+			fmt.Fprintf(os.Stderr, "%s Notification for %s (%s) failed: %v\n", color.RedString("✗"), current.ID, current.Status, err)
+			continue
+		}
+		fmt.Printf("%s Notified %s %s (%s)\n", color.GreenString("✓"), current.ID, current.Name, current.Status)
+	}
+}
+
+const clearScreen = "\x1b[H\x1b[2J"
+
+// drawWatchFrame renders one refresh. The frame is built in full before it is
+// written so the screen is cleared and redrawn in one go, without flicker.
+// The terminal width is read on every frame to follow window resizes.
+func drawWatchFrame(out *os.File, simulations []*models.Simulation, now time.Time) error {
+	width, isTerminal := terminalWidth(out)
+
+	var frame bytes.Buffer
+	fmt.Fprintln(&frame, watchHeader("autobox list", watchInterval, now, width))
+	if err := writeListTable(&frame, simulations); err != nil {
+		return err
+	}
+
+	if !isTerminal {
+		_, err := fmt.Fprintln(out, frame.String())
+		return err
+	}
+	_, err := io.WriteString(out, clearScreen+clipLines(frame.String(), width))
+	return err
+}
+
+// watchHeader is the first line of a frame: the refresh interval and what is
+// shown on the left, and the time of the refresh on the right, as watch(1)
+// does.
+func watchHeader(title string, interval time.Duration, now time.Time, width int) string {
+	left := fmt.Sprintf("Every %s: %s", interval, title)
+	right := now.Format("2006-01-02 15:04:05")
+	if pad := width - len(left) - len(right); pad > 0 {
+		return left + strings.Repeat(" ", pad) + right
+	}
+	return left + "  " + right
+}
+
+// terminalWidth reports the width of out in columns and whether it is a
+// terminal at all. Zero means the width is unknown and lines aren't cut.
+func terminalWidth(out *os.File) (int, bool) {
+	fd, isTerminal := term.GetFdInfo(out)
+	if !isTerminal {
+		return 0, false
+	}
+	size, err := term.GetWinsize(fd)
+	if err != nil {
+		return 0, true
+	}
+	return int(size.Width), true
+}
+
+// clipLines cuts every line of s to width visible characters. Color codes
+// don't count towards the width and are kept, and a reset is added to a cut
+// line so a color doesn't bleed into the next one.
+func clipLines(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = clipLine(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// leadingANSIEscape matches an escape sequence only at the start of the
+// string, so clipLine never scans past the rune it is looking at.
+var leadingANSIEscape = regexp.MustCompile("^(?:" + ansiEscapePattern.String() + ")")
+
+func clipLine(line string, width int) string {
+	var b strings.Builder
+	visible := 0
+	styled := false
+	for len(line) > 0 {
+		if line[0] == '\x1b' {
+			if n := len(leadingANSIEscape.FindString(line)); n > 0 {
+				b.WriteString(line[:n])
+				line = line[n:]
+				styled = true
+				continue
+			}
+		}
+		if visible == width {
+			if styled {
+				b.WriteString("\x1b[0m")
+			}
+			return b.String()
+		}
+		r, size := utf8.DecodeRuneInString(line)
+		b.WriteRune(r)
+		line = line[size:]
+		visible++
+	}
+	return b.String()
+}
+
+func untilConditionMet(condition string, simulations []*models.Simulation) bool {
+	for _, sim := range simulations {
+		switch condition {
+		case untilAllCompleted:
+			if !isTerminalStatus(sim.Status) {
+				return false
+			}
+		case untilNoneRunning:
+			// A paused simulation still holds its resources and will carry on
+			// once unpaused, so it counts as running here.
+			if sim.Status == models.StatusRunning || sim.Status == models.StatusPaused {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isTerminalStatus(status models.SimulationStatus) bool {
+	switch status {
+	case models.StatusCompleted, models.StatusFailed, models.StatusStopped:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/Autobox-AI/autobox-cli/internal/webhook"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/fatih/color"
+)
+
+// resolveWebhookURL returns the --webhook flag, falling back to webhook.url
+// from autobox.yaml.
+func resolveWebhookURL(flag string) (string, error) {
+	target := flag
+	if target == "" {
+		target = config.GetString("webhook.url")
+	}
+	if target == "" {
+		return "", nil
+	}
+	if err := validateWebhookURL(target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+func validateWebhookURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q (expected http:// or https://)", target)
+	}
+	return nil
+}
+
+// notifyWebhook POSTs the simulation to target, with secret environment
+// values masked as in status and list. Failures are logged rather than
+// returned, so an unreachable control plane never blocks a launch or a
+// terminate.
+func notifyWebhook(ctx context.Context, target, event string, simulation *models.Simulation) {
+	if target == "" {
+		return
+	}
+
+	settings := config.Get()
+	payload := *simulation
+	redactSimulations([]*models.Simulation{&payload}, secretPattern(settings.Output.SecretPattern))
+
+	notifier := webhook.New(target, settings.Webhook.Timeout, settings.Webhook.Retries)
+	if err := notifier.Notify(ctx, event, &payload); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Webhook %s notification failed: %v\n", color.YellowString("⚠"), event, err)
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+)
+
+func TestNotifyWebhookMasksSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	const secret = "sk-proj-abcdefgh1234"
+	sim := &models.Simulation{
+		ID:     "abc123",
+		Status: models.StatusRunning,
+		Config: models.SimulationConfig{
+			Environment: map[string]string{"OPENAI_API_KEY": secret, "LOG_LEVEL": "debug"},
+		},
+	}
+	notifyWebhook(context.Background(), server.URL, "launch", sim)
+
+	if body == "" {
+		t.Fatal("notifyWebhook() sent nothing")
+	}
+	if strings.Contains(body, secret) {
+		t.Errorf("webhook body contains the secret: %s", body)
+	}
+	if !strings.Contains(body, maskSecret(secret)) || !strings.Contains(body, `"LOG_LEVEL":"debug"`) {
+		t.Errorf("webhook body = %s, want the masked key and the other values as they are", body)
+	}
+	if sim.Config.Environment["OPENAI_API_KEY"] != secret {
+		t.Errorf("notifyWebhook() changed the caller's simulation: %v", sim.Config.Environment)
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxBundleFileSize bounds each file read from a bundle, so a corrupt or
+// hostile archive can't exhaust memory.
+const maxBundleFileSize = 10 << 20
+
+const bundleServerFile = "server.json"
+
+// ExportBundle writes a gzipped tarball with the simulation and metrics
+// configs for simulationName, laid out as in ~/.autobox/config
+// (simulations/<name>.json, metrics/<name>.json). When includeServer is set
+// it adds the server config the simulation runs with: server/<name>.json if
+// it exists, or else server.json if that exists.
+func ExportBundle(simulationName string, w io.Writer, includeServer bool) error {
+	if err := ValidateSimulationConfig(simulationName); err != nil {
+		return err
+	}
+
+	paths, err := Paths()
+	if err != nil {
+		return err
+	}
+	fileName := SimulationFileName(simulationName)
+	base, _ := paths.configTree(fileName)
+
+	files := []string{
+		path.Join("simulations", fileName),
+		path.Join("metrics", fileName),
+	}
+	if includeServer {
+		for _, name := range []string{path.Join(filepath.Base(paths.Servers), fileName), bundleServerFile} {
+			if _, err := os.Stat(filepath.Join(base, filepath.FromSlash(name))); err == nil {
+				files = append(files, name)
+				break
+			} else if !os.IsNotExist(err) {
+				return fmt.Errorf("failed to read server config: %w", err)
+			}
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(base, filepath.FromSlash(name)))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// ImportBundle unpacks a bundle written by ExportBundle into ~/.autobox/config
+// and returns the files it wrote. The whole bundle is validated before
+// anything is written: it must hold exactly one simulation config and its
+// matching metrics config (and optionally server.json or the matching
+// server/<name>.json), all valid JSON, with no other entries. Existing files are only replaced when force is set.
+func ImportBundle(r io.Reader, force bool) ([]string, error) {
+	files, err := readBundle(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBundle(files); err != nil {
+		return nil, err
+	}
+
+	paths, err := Paths()
+	if err != nil {
+		return nil, err
+	}
+	base := paths.Config
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if !force {
+		var existing []string
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(base, filepath.FromSlash(name))); err == nil {
+				existing = append(existing, name)
+			}
+		}
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("refusing to overwrite %s in %s (use --force)", strings.Join(existing, ", "), base)
+		}
+	}
+
+	var written []string
+	for _, name := range names {
+		dest := filepath.Join(base, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return written, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(dest, files[name], 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", dest, err)
+		}
+		written = append(written, dest)
+	}
+	return written, nil
+}
+
+func readBundle(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gzipped bundle: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("bundle entry %s is not a regular file", header.Name)
+		}
+
+		name, ok := bundleEntryName(header.Name)
+		if !ok {
+			return nil, fmt.Errorf("unexpected bundle entry %s (expected simulations/*.json, metrics/*.json, server/*.json or server.json)", header.Name)
+		}
+		if _, dup := files[name]; dup {
+			return nil, fmt.Errorf("bundle entry %s appears more than once", name)
+		}
+		if header.Size > maxBundleFileSize {
+			return nil, fmt.Errorf("bundle entry %s is too large", name)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from bundle: %w", name, err)
+		}
+		if len(data) > maxBundleFileSize {
+			return nil, fmt.Errorf("bundle entry %s is too large", name)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// bundleEntryName cleans an archive path and reports whether it is one of
+// the files a bundle may contain. Anything else, including absolute paths and
+// paths escaping the config directory, is rejected.
+func bundleEntryName(name string) (string, bool) {
+	name = path.Clean(strings.TrimPrefix(name, "./"))
+	if name == bundleServerFile {
+		return name, true
+	}
+
+	dir, file := path.Split(name)
+	if dir != "simulations/" && dir != "metrics/" && dir != "server/" {
+		return "", false
+	}
+	if !strings.HasSuffix(file, ".json") || file == ".json" || strings.HasPrefix(file, ".") {
+		return "", false
+	}
+	return name, true
+}
+
+func validateBundle(files map[string][]byte) error {
+	var simulation, metrics, server string
+	for name, data := range files {
+		switch {
+		case strings.HasPrefix(name, "simulations/"):
+			if simulation != "" {
+				return fmt.Errorf("bundle holds more than one simulation config")
+			}
+			simulation = name
+			if err := ValidateSimulationDocument(data); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		case strings.HasPrefix(name, "metrics/"):
+			if metrics != "" {
+				return fmt.Errorf("bundle holds more than one metrics config")
+			}
+			metrics = name
+			if !json.Valid(bytes.TrimSpace(data)) {
+				return fmt.Errorf("%s is not valid JSON", name)
+			}
+		default:
+			if server != "" {
+				return fmt.Errorf("bundle holds more than one server config")
+			}
+			server = name
+			if !json.Valid(bytes.TrimSpace(data)) {
+				return fmt.Errorf("%s is not valid JSON", name)
+			}
+		}
+	}
+
+	switch {
+	case simulation == "":
+		return fmt.Errorf("bundle has no simulation config")
+	case metrics == "":
+		return fmt.Errorf("bundle has no metrics config for %s", path.Base(simulation))
+	case path.Base(simulation) != path.Base(metrics):
+		return fmt.Errorf("bundle configs don't match: %s and %s (simulation and metrics configs must have matching names)", simulation, metrics)
+	case server != "" && server != bundleServerFile && path.Base(server) != path.Base(simulation):
+		return fmt.Errorf("bundle configs don't match: %s and %s (a per-simulation server config must match the simulation name)", simulation, server)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupBundleHome(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	t.Cleanup(func() { os.Setenv("HOME", oldHome) })
+	return filepath.Join(tmpDir, ".autobox", "config")
+}
+
+func writeBundleFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func buildBundle(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatalf("Failed to write header: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write entry: %v", err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	base := setupBundleHome(t)
+	writeBundleFile(t, filepath.Join(base, "simulations", "gift_choice.json"), `{"name": "Gift choice", "agents": []}`)
+	writeBundleFile(t, filepath.Join(base, "metrics", "gift_choice.json"), `[]`)
+	writeBundleFile(t, filepath.Join(base, "server.json"), `{"port": 9000}`)
+
+	var bundle bytes.Buffer
+	if err := ExportBundle("gift-choice", &bundle, true); err != nil {
+		t.Fatalf("ExportBundle() error = %v", err)
+	}
+
+	if _, err := ImportBundle(bytes.NewReader(bundle.Bytes()), false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("ImportBundle() over existing files error = %v, want a --force hint", err)
+	}
+
+	// Import into a fresh home.
+	base = setupBundleHome(t)
+	written, err := ImportBundle(bytes.NewReader(bundle.Bytes()), false)
+	if err != nil {
+		t.Fatalf("ImportBundle() error = %v", err)
+	}
+	if len(written) != 3 {
+		t.Errorf("ImportBundle() wrote %v, want 3 files", written)
+	}
+	data, err := os.ReadFile(filepath.Join(base, "simulations", "gift_choice.json"))
+	if err != nil || string(data) != `{"name": "Gift choice", "agents": []}` {
+		t.Errorf("Imported simulation config = %q, %v", data, err)
+	}
+
+	writeBundleFile(t, filepath.Join(base, "metrics", "gift_choice.json"), `["stale"]`)
+	if _, err := ImportBundle(bytes.NewReader(bundle.Bytes()), true); err != nil {
+		t.Fatalf("ImportBundle(force) error = %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(base, "metrics", "gift_choice.json"))
+	if string(data) != `[]` {
+		t.Errorf("Forced import left metrics config = %q, want []", data)
+	}
+}
+
+func TestBundleRoundTripPerSimulationServer(t *testing.T) {
+	base := setupBundleHome(t)
+	writeBundleFile(t, filepath.Join(base, "simulations", "sim.json"), `{"name": "sim", "agents": []}`)
+	writeBundleFile(t, filepath.Join(base, "metrics", "sim.json"), `[]`)
+	writeBundleFile(t, filepath.Join(base, "server.json"), `{"port": 9000}`)
+	writeBundleFile(t, filepath.Join(base, "server", "sim.json"), `{"port": 9100}`)
+
+	var bundle bytes.Buffer
+	if err := ExportBundle("sim", &bundle, true); err != nil {
+		t.Fatalf("ExportBundle() error = %v", err)
+	}
+	files, err := readBundle(bytes.NewReader(bundle.Bytes()))
+	if err != nil {
+		t.Fatalf("readBundle() error = %v", err)
+	}
+	if _, ok := files["server.json"]; ok || string(files["server/sim.json"]) != `{"port": 9100}` {
+		t.Errorf("Bundle entries = %v, want server/sim.json in place of server.json", files)
+	}
+
+	base = setupBundleHome(t)
+	if _, err := ImportBundle(bytes.NewReader(bundle.Bytes()), false); err != nil {
+		t.Fatalf("ImportBundle() error = %v", err)
+	}
+	configSet, err := LoadSimulationConfig("sim")
+	if err != nil {
+		t.Fatalf("LoadSimulationConfig() error = %v", err)
+	}
+	if configSet.ServerPath != filepath.Join(base, "server", "sim.json") || configSet.Server["port"] != float64(9100) {
+		t.Errorf("Imported server config = %v from %s, want port 9100 from server/sim.json", configSet.Server, configSet.ServerPath)
+	}
+}
+
+func TestExportBundleWithoutServer(t *testing.T) {
+	base := setupBundleHome(t)
+	writeBundleFile(t, filepath.Join(base, "simulations", "sim.json"), `{"name": "sim", "agents": []}`)
+	writeBundleFile(t, filepath.Join(base, "metrics", "sim.json"), `[]`)
+	writeBundleFile(t, filepath.Join(base, "server.json"), `{}`)
+
+	var bundle bytes.Buffer
+	if err := ExportBundle("sim", &bundle, false); err != nil {
+		t.Fatalf("ExportBundle() error = %v", err)
+	}
+	files, err := readBundle(&bundle)
+	if err != nil {
+		t.Fatalf("readBundle() error = %v", err)
+	}
+	if _, ok := files["server.json"]; ok || len(files) != 2 {
+		t.Errorf("Bundle entries = %v, want only the simulation and metrics configs", files)
+	}
+}
+
+func TestImportBundleRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "path traversal",
+			files: map[string]string{"simulations/../../evil.json": `{}`},
+			want:  "unexpected bundle entry",
+		},
+		{
+			name:  "absolute path",
+			files: map[string]string{"/etc/passwd": "x"},
+			want:  "unexpected bundle entry",
+		},
+		{
+			name:  "missing metrics",
+			files: map[string]string{"simulations/sim.json": `{"name": "sim"}`},
+			want:  "no metrics config",
+		},
+		{
+			name:  "mismatched names",
+			files: map[string]string{"simulations/sim.json": `{}`, "metrics/other.json": `[]`},
+			want:  "don't match",
+		},
+		{
+			name:  "mismatched server config",
+			files: map[string]string{"simulations/sim.json": `{}`, "metrics/sim.json": `[]`, "server/other.json": `{}`},
+			want:  "per-simulation server config",
+		},
+		{
+			name:  "two server configs",
+			files: map[string]string{"simulations/sim.json": `{}`, "metrics/sim.json": `[]`, "server/sim.json": `{}`, "server.json": `{}`},
+			want:  "more than one server config",
+		},
+		{
+			name:  "invalid simulation",
+			files: map[string]string{"simulations/sim.json": `{"agents": "none"}`, "metrics/sim.json": `[]`},
+			want:  "simulations/sim.json",
+		},
+		{
+			name:  "invalid metrics",
+			files: map[string]string{"simulations/sim.json": `{}`, "metrics/sim.json": `{`},
+			want:  "not valid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := setupBundleHome(t)
+			bundle := buildBundle(t, tt.files)
+			_, err := ImportBundle(bytes.NewReader(bundle), false)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ImportBundle() error = %v, want it to contain %q", err, tt.want)
+			}
+			if _, err := os.Stat(base); !os.IsNotExist(err) {
+				t.Errorf("Rejected bundle wrote to %s", base)
+			}
+		})
+	}
+}
@@ -3,40 +3,66 @@ package config
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
+	// Version is the schema version of the ~/.autobox layout, see
+	// SchemaVersion.
+	Version    int              `mapstructure:"version"`
 	Docker     DockerConfig     `mapstructure:"docker"`
 	Simulation SimulationConfig `mapstructure:"simulation"`
 	Output     OutputConfig     `mapstructure:"output"`
+	Webhook    WebhookConfig    `mapstructure:"webhook"`
 }
 
 type DockerConfig struct {
-	Host       string `mapstructure:"host"`
+	Host string `mapstructure:"host"`
+	// APIVersion is the oldest Docker API the CLI is known to work with. The
+	// version is still negotiated with the daemon; an older daemon only
+	// produces a warning.
 	APIVersion string `mapstructure:"api_version"`
 	TLSVerify  bool   `mapstructure:"tls_verify"`
 	CertPath   string `mapstructure:"cert_path"`
 	Image      string `mapstructure:"image"`
+	// LabelPrefix namespaces the labels simulations are created and listed
+	// with, to keep separate fleets on one host apart.
+	LabelPrefix string `mapstructure:"label_prefix"`
 }
 
 type SimulationConfig struct {
-	DefaultImage       string            `mapstructure:"default_image"`
 	DefaultConfigPath  string            `mapstructure:"default_config_path"`
 	DefaultMetricsPath string            `mapstructure:"default_metrics_path"`
 	DefaultVolumes     []string          `mapstructure:"default_volumes"`
 	DefaultEnvironment map[string]string `mapstructure:"default_environment"`
 	LogsDirectory      string            `mapstructure:"logs_directory"`
 	ConfigDirectory    string            `mapstructure:"config_directory"`
+	// StrictNames turns off simulation name normalization, see
+	// SimulationFileName.
+	StrictNames bool `mapstructure:"strict_names"`
 }
 
 type OutputConfig struct {
 	Format  string `mapstructure:"format"`
 	Verbose bool   `mapstructure:"verbose"`
 	Color   bool   `mapstructure:"color"`
+	// SecretPattern is a regular expression matched against environment
+	// variable names; the values of those that match are masked in output.
+	SecretPattern string `mapstructure:"secret_pattern"`
+}
+
+// DefaultSecretPattern masks variables whose names look like credentials.
+const DefaultSecretPattern = `(?i)(KEY|TOKEN|SECRET|PASSWORD)`
+
+// WebhookConfig sets a default for run --webhook. Notifications are sent on
+// launch and terminate; failures are only logged.
+type WebhookConfig struct {
+	URL     string        `mapstructure:"url"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	Retries int           `mapstructure:"retries"`
 }
 
 var (
@@ -47,12 +73,12 @@ func Init() error {
 	viper.SetConfigName("autobox")
 	viper.SetConfigType("yaml")
 
-	home, err := os.UserHomeDir()
+	paths, err := Paths()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	viper.AddConfigPath(filepath.Join(home, ".autobox"))
+	viper.AddConfigPath(paths.Root)
 	viper.AddConfigPath(".")
 	viper.AddConfigPath("/etc/autobox")
 
@@ -81,23 +107,28 @@ func setDefaults() {
 	viper.SetDefault("docker.api_version", "1.41")
 	viper.SetDefault("docker.tls_verify", false)
 	viper.SetDefault("docker.image", "autobox-engine:latest")
+	viper.SetDefault("docker.label_prefix", "com.autobox")
 
-	home, _ := os.UserHomeDir()
-	defaultConfigDir := filepath.Join(home, ".autobox", "config")
+	paths, _ := Paths()
 
-	viper.SetDefault("simulation.default_image", "autobox-engine:latest")
 	viper.SetDefault("simulation.default_config_path", "/app/config/simulation.json")
 	viper.SetDefault("simulation.default_metrics_path", "/app/config/metrics.json")
 	viper.SetDefault("simulation.default_volumes", []string{
-		fmt.Sprintf("%s:/app/config", defaultConfigDir),
+		fmt.Sprintf("%s:/app/config", paths.Config),
 	})
 	viper.SetDefault("simulation.default_environment", map[string]string{})
-	viper.SetDefault("simulation.logs_directory", filepath.Join(home, ".autobox", "logs"))
-	viper.SetDefault("simulation.config_directory", defaultConfigDir)
+	viper.SetDefault("simulation.logs_directory", paths.Logs)
+	viper.SetDefault("simulation.config_directory", paths.Config)
+	viper.SetDefault("simulation.strict_names", false)
 
 	viper.SetDefault("output.format", "table")
 	viper.SetDefault("output.verbose", false)
 	viper.SetDefault("output.color", true)
+	viper.SetDefault("output.secret_pattern", DefaultSecretPattern)
+
+	viper.SetDefault("webhook.url", "")
+	viper.SetDefault("webhook.timeout", 5*time.Second)
+	viper.SetDefault("webhook.retries", 3)
 }
 
 func Get() *Config {
@@ -109,6 +140,23 @@ func Get() *Config {
 	return cfg
 }
 
+// Set overrides a config value for the rest of the process, for flags that
+// mirror a config key.
+func Set(key string, value interface{}) {
+	viper.Set(key, value)
+}
+
+// IsConfigured reports whether key was given a value in the config file or
+// through its AUTOBOX_ environment variable, as opposed to only having a
+// default.
+func IsConfigured(key string) bool {
+	if viper.InConfig(key) {
+		return true
+	}
+	_, ok := os.LookupEnv("AUTOBOX_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+	return ok
+}
+
 func GetString(key string) string {
 	return viper.GetString(key)
 }
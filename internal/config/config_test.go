@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -26,6 +27,10 @@ func TestSetDefaults(t *testing.T) {
 	if viper.GetBool("output.color") != true {
 		t.Errorf("output.color: got %v, want true", viper.GetBool("output.color"))
 	}
+
+	if viper.GetDuration("webhook.timeout") != 5*time.Second {
+		t.Errorf("webhook.timeout: got %s, want 5s", viper.GetDuration("webhook.timeout"))
+	}
 }
 
 func TestInit(t *testing.T) {
@@ -43,6 +48,20 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestIsConfigured(t *testing.T) {
+	viper.Reset()
+	setDefaults()
+
+	if IsConfigured("docker.host") {
+		t.Error("IsConfigured(docker.host) = true with only the default")
+	}
+
+	t.Setenv("AUTOBOX_DOCKER_HOST", "tcp://build-box:2376")
+	if !IsConfigured("docker.host") {
+		t.Error("IsConfigured(docker.host) = false with AUTOBOX_DOCKER_HOST set")
+	}
+}
+
 func TestGetString(t *testing.T) {
 	viper.Reset()
 	viper.Set("test.key", "test-value")
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ParseEnvFile reads KEY=VALUE lines from path. Blank lines and lines
+// starting with # are skipped, and an "export " prefix is allowed so a file
+// can also be sourced by a shell. A value in double quotes may contain the
+// escapes \n, \t, \" and \\; one in single quotes is taken literally.
+// Unquoted values are trimmed, and a # inside them is part of the value.
+func ParseEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	defer f.Close()
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := parseEnvLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		env[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	return env, nil
+}
+
+func parseEnvLine(line string) (string, string, error) {
+	if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		line = strings.TrimSpace(rest)
+	}
+
+	key, raw, ok := strings.Cut(line, "=")
+	key = strings.TrimSpace(key)
+	if !ok {
+		return "", "", fmt.Errorf("expected KEY=VALUE, got %q", line)
+	}
+	if key == "" || strings.ContainsAny(key, " \t\"'") {
+		return "", "", fmt.Errorf("invalid variable name %q", key)
+	}
+
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return key, "", nil
+	}
+	switch quote := raw[0]; quote {
+	case '"', '\'':
+		end := closingQuote(raw, quote)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated %c quote in the value of %s", quote, key)
+		}
+		if trailing := strings.TrimSpace(raw[end+1:]); trailing != "" && !strings.HasPrefix(trailing, "#") {
+			return "", "", fmt.Errorf("unexpected %q after the quoted value of %s", trailing, key)
+		}
+		if quote == '\'' {
+			return key, raw[1:end], nil
+		}
+		return key, unescapeEnvValue(raw[1:end]), nil
+	}
+	return key, raw, nil
+}
+
+// closingQuote returns the index of the quote that ends the value opened by
+// s[0], skipping backslash escapes inside double quotes, or -1.
+func closingQuote(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+func unescapeEnvValue(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	content := "\ufeff# API credentials\n" +
+		"OPENAI_API_KEY=sk-test-123\n" +
+		"\n" +
+		"export MODEL=gpt-4o\n" +
+		"exported=yes\n" +
+		"  PADDED  =  spaced out  \n" +
+		`GREETING="hello\nworld \"quoted\""` + "\n" +
+		`LITERAL='no $expansion \n here'` + "\n" +
+		`QUOTED_COMMENT="value" # trailing comment` + "\n" +
+		"HASH=abc#123\n" +
+		"EQUALS=a=b=c\n" +
+		"EMPTY=\n" +
+		`EMPTY_QUOTED=""` + "\n" +
+		"WINDOWS=crlf\r\n"
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseEnvFile(path)
+	if err != nil {
+		t.Fatalf("ParseEnvFile() error = %v", err)
+	}
+	want := map[string]string{
+		"OPENAI_API_KEY": "sk-test-123",
+		"MODEL":          "gpt-4o",
+		"exported":       "yes",
+		"PADDED":         "spaced out",
+		"GREETING":       "hello\nworld \"quoted\"",
+		"LITERAL":        `no $expansion \n here`,
+		"QUOTED_COMMENT": "value",
+		"HASH":           "abc#123",
+		"EQUALS":         "a=b=c",
+		"EMPTY":          "",
+		"EMPTY_QUOTED":   "",
+		"WINDOWS":        "crlf",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEnvFile() = %v, want %v", got, want)
+	}
+}
+
+func TestParseEnvFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"No equals sign", "# header\nOPENAI_API_KEY\n", ":2: expected KEY=VALUE"},
+		{"Empty name", "=value\n", "invalid variable name"},
+		{"Space in name", "MY KEY=value\n", `invalid variable name "MY KEY"`},
+		{"Unterminated double quote", "KEY=\"open\n", "unterminated \" quote"},
+		{"Unterminated single quote", "KEY='open\n", "unterminated ' quote"},
+		{"Escaped closing quote", `KEY="open\"` + "\n", "unterminated"},
+		{"Text after quotes", `KEY="a" b` + "\n", `unexpected "b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ParseEnvFile(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseEnvFile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseEnvFile(filepath.Join(t.TempDir(), "missing.env")); err == nil {
+		t.Error("ParseEnvFile() of a missing file succeeded, want an error")
+	}
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExpandVariables substitutes $VAR and ${VAR} in s with values from lookup,
+// and turns $$ into a literal $. A name runs for as long as it can, so
+// $HOMEDIR is the variable HOMEDIR, never $HOME followed by "DIR". Variables
+// lookup doesn't know are left as written and their names returned, as is
+// anything after a $ that isn't a name.
+func ExpandVariables(s string, lookup func(string) (string, bool)) (string, []string) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	var unknown []string
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		var name, written string
+		switch next := s[i+1]; {
+		case next == '$':
+			b.WriteByte('$')
+			i++
+			continue
+		case next == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 || !isVariableName(s[i+2:i+2+end]) {
+				b.WriteByte('$')
+				continue
+			}
+			name = s[i+2 : i+2+end]
+			written = s[i : i+3+end]
+		case isVariableStart(next):
+			end := i + 2
+			for end < len(s) && isVariableChar(s[end]) {
+				end++
+			}
+			name = s[i+1 : end]
+			written = s[i:end]
+		default:
+			b.WriteByte('$')
+			continue
+		}
+
+		if value, ok := lookup(name); ok {
+			b.WriteString(value)
+		} else {
+			b.WriteString(written)
+			unknown = append(unknown, name)
+		}
+		i += len(written) - 1
+	}
+	return b.String(), unknown
+}
+
+// ExpandEnv is ExpandVariables over the process environment.
+func ExpandEnv(s string) (string, []string) {
+	return ExpandVariables(s, os.LookupEnv)
+}
+
+// expansion collects what expandValues did across the documents of a
+// simulation: the names it couldn't resolve, and whether any value changed.
+type expansion struct {
+	unknown map[string]bool
+	changed bool
+}
+
+// expandValues expands the variables in every string inside v, a value
+// decoded from JSON, recording what it did in e.
+func expandValues(v interface{}, e *expansion) interface{} {
+	switch value := v.(type) {
+	case string:
+		expanded, missing := ExpandEnv(value)
+		for _, name := range missing {
+			e.unknown[name] = true
+		}
+		if expanded != value {
+			e.changed = true
+		}
+		return expanded
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = expandValues(item, e)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = expandValues(item, e)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// WriteExpanded writes the expanded documents to a new directory under
+// LayoutPaths.Runs and points the set's paths at the copies. The files on
+// disk hold the references as written, so a simulation given those would
+// never see the expanded values; Runs is inside the mounted config
+// directory, so the copies are visible in the container. The set's server
+// path is left alone when there is no server config.
+//
+// The copies hold the resolved values of the variables, which may be
+// secrets, so only the owner can read them. Remove them with RemoveRun once
+// the simulation is gone.
+func (s *SimulationConfigSet) WriteExpanded() error {
+	paths, err := Paths()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(paths.Runs, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", paths.Runs, err)
+	}
+	dir, err := os.MkdirTemp(paths.Runs, strings.TrimSuffix(filepath.Base(s.SimulationPath), ".json")+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+	s.RunDirectory = dir
+
+	docs := []struct {
+		path *string
+		doc  interface{}
+		dir  string
+	}{
+		{&s.SimulationPath, s.Simulation, "simulations"},
+		{&s.MetricsPath, s.Metrics, "metrics"},
+		{&s.ServerPath, s.Server, "server"},
+	}
+	for _, d := range docs {
+		if d.dir == "server" && s.Server == nil {
+			continue
+		}
+		data, err := json.MarshalIndent(d.doc, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", *d.path, err)
+		}
+		target := filepath.Join(dir, d.dir, filepath.Base(*d.path))
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, append(data, '\n'), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		*d.path = target
+	}
+	return nil
+}
+
+// RemoveRun deletes a directory WriteExpanded created. Anything that isn't
+// directly inside LayoutPaths.Runs is refused, so a bad path can't remove
+// the rest of the config tree.
+func RemoveRun(dir string) error {
+	paths, err := Paths()
+	if err != nil {
+		return err
+	}
+	dir = filepath.Clean(dir)
+	if filepath.Dir(dir) != paths.Runs {
+		return fmt.Errorf("%s is not a run directory in %s", dir, paths.Runs)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", dir, err)
+	}
+	return nil
+}
+
+func sortedNames(names map[string]bool) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func isVariableName(s string) bool {
+	if s == "" || !isVariableStart(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isVariableChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isVariableStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isVariableChar(c byte) bool {
+	return isVariableStart(c) || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandVariables(t *testing.T) {
+	vars := map[string]string{
+		"HOME":         "/home/ada",
+		"AUTOBOX_DATA": "/data",
+		"EMPTY":        "",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		in      string
+		want    string
+		unknown []string
+	}{
+		{"plain", "plain", nil},
+		{"${HOME}/logs", "/home/ada/logs", nil},
+		{"$HOME/logs", "/home/ada/logs", nil},
+		{"$AUTOBOX_DATA:/app/data", "/data:/app/data", nil},
+		{"${HOME}${AUTOBOX_DATA}", "/home/ada/data", nil},
+		{"x${EMPTY}y", "xy", nil},
+		{"cost: $$5", "cost: $5", nil},
+		{"$$HOME", "$HOME", nil},
+		{"$$$HOME", "$/home/ada", nil},
+		{"$HOMEDIR/x", "$HOMEDIR/x", []string{"HOMEDIR"}},
+		{"${HOME}DIR", "/home/adaDIR", nil},
+		{"${MISSING}:/app", "${MISSING}:/app", []string{"MISSING"}},
+		{"$MISSING and $ALSO", "$MISSING and $ALSO", []string{"MISSING", "ALSO"}},
+		{"${HOME", "${HOME", nil},
+		{"${}", "${}", nil},
+		{"${not valid}", "${not valid}", nil},
+		{"$1 and $-", "$1 and $-", nil},
+		{"trailing $", "trailing $", nil},
+	}
+	for _, tt := range tests {
+		got, unknown := ExpandVariables(tt.in, lookup)
+		if got != tt.want {
+			t.Errorf("ExpandVariables(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if !reflect.DeepEqual(unknown, tt.unknown) {
+			t.Errorf("ExpandVariables(%q) unknown = %q, want %q", tt.in, unknown, tt.unknown)
+		}
+	}
+}
+
+func TestLoadSimulationConfigExpandsVariables(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("AUTOBOX_DATA", "/data")
+
+	configBase := filepath.Join(tmpDir, ".autobox", "config")
+	files := map[string]string{
+		"simulations/paths.json": `{"name": "paths", "output": "${AUTOBOX_DATA}/out", "agents": [{"prompt": "$UNSET_PROMPT_VAR"}]}`,
+		"metrics/paths.json":     `{"export": "$AUTOBOX_DATA/metrics", "price": "$$5"}`,
+		"server.json":            `{"logs": "${HOME}/logs"}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(configBase, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configSet, err := LoadSimulationConfig("paths")
+	if err != nil {
+		t.Fatalf("LoadSimulationConfig() error = %v", err)
+	}
+
+	if got := configSet.Simulation["output"]; got != "/data/out" {
+		t.Errorf("simulation output = %v, want /data/out", got)
+	}
+	agent := configSet.Simulation["agents"].([]interface{})[0].(map[string]interface{})
+	if got := agent["prompt"]; got != "$UNSET_PROMPT_VAR" {
+		t.Errorf("agent prompt = %v, want it left as written", got)
+	}
+	metrics := configSet.Metrics.(map[string]interface{})
+	if metrics["export"] != "/data/metrics" || metrics["price"] != "$5" {
+		t.Errorf("metrics = %v", metrics)
+	}
+	if got := configSet.Server["logs"]; got != filepath.Join(tmpDir, "logs") {
+		t.Errorf("server logs = %v", got)
+	}
+	if want := []string{"UNSET_PROMPT_VAR"}; !reflect.DeepEqual(configSet.UnresolvedVariables, want) {
+		t.Errorf("UnresolvedVariables = %q, want %q", configSet.UnresolvedVariables, want)
+	}
+	if !configSet.Expanded {
+		t.Error("Expanded = false, want true")
+	}
+
+	if err := configSet.WriteExpanded(); err != nil {
+		t.Fatalf("WriteExpanded() error = %v", err)
+	}
+	runs := filepath.Join(configBase, "runs") + string(filepath.Separator)
+	for _, path := range []string{configSet.SimulationPath, configSet.MetricsPath, configSet.ServerPath} {
+		if !strings.HasPrefix(path, runs) {
+			t.Errorf("WriteExpanded() path = %s, want it under %s", path, runs)
+		}
+	}
+	data, err := os.ReadFile(configSet.ServerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var server map[string]interface{}
+	if err := json.Unmarshal(data, &server); err != nil || server["logs"] != filepath.Join(tmpDir, "logs") {
+		t.Errorf("written server config = %s, %v; want the expanded logs path", data, err)
+	}
+
+	// The copies may hold secrets.
+	for path, want := range map[string]os.FileMode{configSet.RunDirectory: 0700, configSet.ServerPath: 0600} {
+		if info, err := os.Stat(path); err != nil {
+			t.Error(err)
+		} else if info.Mode().Perm() != want {
+			t.Errorf("%s mode = %v, want %v", path, info.Mode().Perm(), want)
+		}
+	}
+
+	if err := RemoveRun(configBase); err == nil {
+		t.Error("RemoveRun(config directory) error = nil, want a refusal")
+	}
+	if err := RemoveRun(configSet.RunDirectory); err != nil {
+		t.Fatalf("RemoveRun() error = %v", err)
+	}
+	if _, err := os.Stat(configSet.RunDirectory); !os.IsNotExist(err) {
+		t.Errorf("RemoveRun() left %s behind", configSet.RunDirectory)
+	}
+}
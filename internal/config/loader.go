@@ -8,6 +8,9 @@ import (
 	"strings"
 )
 
+// SimulationConfigSet is a named simulation's config files and their
+// contents. ServerPath is the server config used: server/<name>.json if
+// there is one, else the shared server.json, which may not exist.
 type SimulationConfigSet struct {
 	Name           string                 `json:"name"`
 	SimulationPath string                 `json:"simulation_path"`
@@ -16,23 +19,46 @@ type SimulationConfigSet struct {
 	Simulation     map[string]interface{} `json:"simulation"`
 	Metrics        interface{}            `json:"metrics"`
 	Server         map[string]interface{} `json:"server"`
+	// UnresolvedVariables are the $VAR references in the loaded files that
+	// aren't set in the environment and were left as written.
+	UnresolvedVariables []string `json:"unresolved_variables,omitempty"`
+	// Expanded is set when expanding variables changed any value, so the
+	// files on disk no longer match the contents; see WriteExpanded.
+	Expanded bool `json:"expanded,omitempty"`
+	// RunDirectory is the directory WriteExpanded wrote the copies to.
+	RunDirectory string `json:"run_directory,omitempty"`
+	// Legacy is set when the files were read from the legacy configs/
+	// directory, which isn't mounted into simulations.
+	Legacy bool `json:"legacy,omitempty"`
 }
 
-func LoadSimulationConfig(simulationName string) (*SimulationConfigSet, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+// SimulationFileName maps a simulation name to its config file name. Names
+// are normalized by default: lowercased, with dashes turned into underscores,
+// so "My-Sim" loads my_sim.json. With simulation.strict_names set the name is
+// used exactly as given and only ".json" is appended.
+func SimulationFileName(simulationName string) string {
+	fileName := simulationName
+	if !GetBool("simulation.strict_names") {
+		fileName = strings.ToLower(strings.ReplaceAll(fileName, "-", "_"))
 	}
-
-	configBase := filepath.Join(home, ".autobox", "config")
-
-	fileName := strings.ToLower(strings.ReplaceAll(simulationName, "-", "_"))
 	if !strings.HasSuffix(fileName, ".json") {
 		fileName = fileName + ".json"
 	}
+	return fileName
+}
+
+func LoadSimulationConfig(simulationName string) (*SimulationConfigSet, error) {
+	paths, err := Paths()
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := SimulationFileName(simulationName)
+	configBase, legacy := paths.configTree(fileName)
 
 	configSet := &SimulationConfigSet{
-		Name: simulationName,
+		Name:   simulationName,
+		Legacy: legacy,
 	}
 
 	simPath := filepath.Join(configBase, "simulations", fileName)
@@ -63,9 +89,9 @@ func LoadSimulationConfig(simulationName string) (*SimulationConfigSet, error) {
 		configSet.Metrics = metricsInterface
 	}
 
-	serverPath := filepath.Join(configBase, "server.json")
+	serverPath := filepath.Join(configBase, filepath.Base(paths.Servers), fileName)
 	if _, err := os.Stat(serverPath); os.IsNotExist(err) {
-		serverPath = filepath.Join(configBase, "server.json")
+		serverPath = filepath.Join(configBase, filepath.Base(paths.Server))
 	}
 	configSet.ServerPath = serverPath
 	if serverData, err := os.ReadFile(serverPath); err != nil {
@@ -78,68 +104,135 @@ func LoadSimulationConfig(simulationName string) (*SimulationConfigSet, error) {
 		}
 	}
 
+	e := &expansion{unknown: map[string]bool{}}
+	expandValues(configSet.Simulation, e)
+	configSet.Metrics = expandValues(configSet.Metrics, e)
+	expandValues(configSet.Server, e)
+	configSet.UnresolvedVariables = sortedNames(e.unknown)
+	configSet.Expanded = e.changed
+
 	return configSet, nil
 }
 
 func ListAvailableSimulations() ([]string, error) {
-	home, err := os.UserHomeDir()
+	entries, err := ListSimulationsWithStatus()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
 
-	simDir := filepath.Join(home, ".autobox", "config", "simulations")
-	metricsDir := filepath.Join(home, ".autobox", "config", "metrics")
+	simulations := []string{}
+	for _, entry := range entries {
+		if entry.Complete {
+			simulations = append(simulations, entry.Name)
+		}
+	}
+	return simulations, nil
+}
+
+// SimulationEntry describes a simulation config found on disk. Incomplete
+// entries have a simulation file but no matching metrics file and cannot be run.
+type SimulationEntry struct {
+	Name     string `json:"name"`
+	Complete bool   `json:"complete"`
+	Missing  string `json:"missing,omitempty"`
+}
 
-	simFiles, err := os.ReadDir(simDir)
+// ListSimulationsWithStatus returns every simulation config, including those
+// ListAvailableSimulations drops because their metrics counterpart is missing.
+// Simulations only found in the legacy configs/ directory are listed after
+// the others.
+func ListSimulationsWithStatus() ([]SimulationEntry, error) {
+	paths, err := Paths()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
-		return nil, fmt.Errorf("failed to read simulations directory: %w", err)
+		return nil, err
+	}
+
+	entries, err := listSimulationEntries(paths.Config, nil)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		seen[entry.Name] = true
+	}
+	legacy, err := listSimulationEntries(paths.LegacyConfig, seen)
+	if err != nil {
+		return nil, err
 	}
+	return append(entries, legacy...), nil
+}
 
-	metricsFiles, err := os.ReadDir(metricsDir)
+// listSimulationEntries lists the simulations of one config tree, skipping
+// the names in skip. A missing tree has none.
+func listSimulationEntries(configDir string, skip map[string]bool) ([]SimulationEntry, error) {
+	simFiles, err := os.ReadDir(filepath.Join(configDir, "simulations"))
 	if err != nil {
 		if os.IsNotExist(err) {
-			return []string{}, nil
+			return []SimulationEntry{}, nil
 		}
-		return nil, fmt.Errorf("failed to read metrics directory: %w", err)
+		return nil, fmt.Errorf("failed to read simulations directory: %w", err)
 	}
 
 	metricsMap := make(map[string]bool)
+	metricsFiles, err := os.ReadDir(filepath.Join(configDir, "metrics"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read metrics directory: %w", err)
+	}
 	for _, f := range metricsFiles {
 		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
 			metricsMap[f.Name()] = true
 		}
 	}
 
-	var simulations []string
+	entries := []SimulationEntry{}
 	for _, f := range simFiles {
-		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
-			if metricsMap[f.Name()] {
-				name := strings.TrimSuffix(f.Name(), ".json")
-				simulations = append(simulations, name)
-			}
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
 		}
+		entry := SimulationEntry{
+			Name:     strings.TrimSuffix(f.Name(), ".json"),
+			Complete: metricsMap[f.Name()],
+		}
+		if skip[entry.Name] {
+			continue
+		}
+		if !entry.Complete {
+			entry.Missing = filepath.Join("metrics", f.Name())
+		}
+		entries = append(entries, entry)
 	}
 
-	return simulations, nil
+	return entries, nil
 }
 
-func ValidateSimulationConfig(simulationName string) error {
-	home, err := os.UserHomeDir()
+// SimulationConfigPath returns the simulation config file for a simulation
+// name, resolved the same way as LoadSimulationConfig. The file may not
+// exist.
+func SimulationConfigPath(simulationName string) (string, error) {
+	paths, err := Paths()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
 
-	configBase := filepath.Join(home, ".autobox", "config")
-	fileName := strings.ToLower(strings.ReplaceAll(simulationName, "-", "_"))
-	if !strings.HasSuffix(fileName, ".json") {
-		fileName = fileName + ".json"
+	fileName := SimulationFileName(simulationName)
+	configBase, _ := paths.configTree(fileName)
+	return filepath.Join(configBase, "simulations", fileName), nil
+}
+
+func ValidateSimulationConfig(simulationName string) error {
+	paths, err := Paths()
+	if err != nil {
+		return err
 	}
 
+	fileName := SimulationFileName(simulationName)
+	configBase, _ := paths.configTree(fileName)
+
 	simPath := filepath.Join(configBase, "simulations", fileName)
 	if _, err := os.Stat(simPath); os.IsNotExist(err) {
+		if literal := strings.TrimSuffix(simulationName, ".json") + ".json"; literal != fileName {
+			return fmt.Errorf("simulation config not found: %s (normalized from %q; use --strict-name to load %s)", fileName, simulationName, literal)
+		}
 		return fmt.Errorf("simulation config not found: %s", fileName)
 	}
 
@@ -148,20 +241,32 @@ func ValidateSimulationConfig(simulationName string) error {
 		return fmt.Errorf("metrics config not found: %s (simulation and metrics configs must have matching names)", fileName)
 	}
 
+	simData, err := os.ReadFile(simPath)
+	if err != nil {
+		return fmt.Errorf("failed to read simulation config: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(simData, &fields); err != nil {
+		return fmt.Errorf("failed to parse simulation config %s: %w", simPath, err)
+	}
+	if err := ValidateSimulationSchema(fields); err != nil {
+		return fmt.Errorf("%s: %w", simPath, err)
+	}
+
 	return nil
 }
 
 func EnsureConfigDirectories() error {
-	home, err := os.UserHomeDir()
+	paths, err := Paths()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
 	dirs := []string{
-		filepath.Join(home, ".autobox", "config"),
-		filepath.Join(home, ".autobox", "config", "simulations"),
-		filepath.Join(home, ".autobox", "config", "metrics"),
-		filepath.Join(home, ".autobox", "logs"),
+		paths.Config,
+		paths.Simulations,
+		paths.Metrics,
+		paths.Logs,
 	}
 
 	for _, dir := range dirs {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -105,7 +106,7 @@ func TestValidateSimulationConfig(t *testing.T) {
 		t.Fatalf("Failed to create metrics dir: %v", err)
 	}
 
-	simData := []byte(`{"name": "test_sim"}`)
+	simData := []byte(`{"name": "test_sim", "agents": []}`)
 	metricsData := []byte(`{"enabled": true}`)
 
 	if err := os.WriteFile(filepath.Join(simDir, "test_sim.json"), simData, 0644); err != nil {
@@ -134,6 +135,17 @@ func TestValidateSimulationConfig(t *testing.T) {
 	if err := ValidateSimulationConfig("no_sim"); err == nil {
 		t.Errorf("Expected validation to fail for missing simulation config")
 	}
+
+	if err := os.WriteFile(filepath.Join(simDir, "typo.json"), []byte(`{"name": "typo", "agnets": []}`), 0644); err != nil {
+		t.Fatalf("Failed to write simulation config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(metricsDir, "typo.json"), metricsData, 0644); err != nil {
+		t.Fatalf("Failed to write metrics config: %v", err)
+	}
+	err = ValidateSimulationConfig("typo")
+	if err == nil || !strings.Contains(err.Error(), `"agents" is required`) || !strings.Contains(err.Error(), `did you mean "agents"`) {
+		t.Errorf("Expected both schema problems for typo.json, got: %v", err)
+	}
 }
 
 func TestListAvailableSimulations(t *testing.T) {
@@ -199,3 +211,127 @@ func TestListAvailableSimulations(t *testing.T) {
 		t.Errorf("Orphan simulation should not be listed")
 	}
 }
+
+func TestListSimulationsWithStatus(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "autobox-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	configBase := filepath.Join(tmpDir, ".autobox", "config")
+	simDir := filepath.Join(configBase, "simulations")
+	metricsDir := filepath.Join(configBase, "metrics")
+
+	if err := os.MkdirAll(simDir, 0755); err != nil {
+		t.Fatalf("Failed to create simulations dir: %v", err)
+	}
+	if err := os.MkdirAll(metricsDir, 0755); err != nil {
+		t.Fatalf("Failed to create metrics dir: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(simDir, "complete_sim.json"), []byte(`{"name": "complete"}`), 0644); err != nil {
+		t.Fatalf("Failed to write simulation config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(metricsDir, "complete_sim.json"), []byte(`{"enabled": true}`), 0644); err != nil {
+		t.Fatalf("Failed to write metrics config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(simDir, "orphan_sim.json"), []byte(`{"name": "orphan"}`), 0644); err != nil {
+		t.Fatalf("Failed to write orphan simulation config: %v", err)
+	}
+
+	entries, err := ListSimulationsWithStatus()
+	if err != nil {
+		t.Fatalf("Failed to list simulations: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	byName := make(map[string]SimulationEntry)
+	for _, entry := range entries {
+		byName[entry.Name] = entry
+	}
+
+	if !byName["complete_sim"].Complete {
+		t.Errorf("Expected complete_sim to be complete")
+	}
+
+	orphan, ok := byName["orphan_sim"]
+	if !ok {
+		t.Fatalf("Expected orphan_sim to be listed")
+	}
+	if orphan.Complete {
+		t.Errorf("Expected orphan_sim to be incomplete")
+	}
+	if orphan.Missing != filepath.Join("metrics", "orphan_sim.json") {
+		t.Errorf("Expected missing metrics path, got '%s'", orphan.Missing)
+	}
+}
+
+func TestSimulationFileName(t *testing.T) {
+	defer Set("simulation.strict_names", false)
+
+	Set("simulation.strict_names", false)
+	if got := SimulationFileName("My-Sim"); got != "my_sim.json" {
+		t.Errorf("SimulationFileName(My-Sim) = %q, want my_sim.json", got)
+	}
+	if got := SimulationFileName("gift_choice.json"); got != "gift_choice.json" {
+		t.Errorf("SimulationFileName(gift_choice.json) = %q, want gift_choice.json", got)
+	}
+
+	Set("simulation.strict_names", true)
+	if got := SimulationFileName("My-Sim"); got != "My-Sim.json" {
+		t.Errorf("strict SimulationFileName(My-Sim) = %q, want My-Sim.json", got)
+	}
+}
+
+func TestLoadSimulationConfigPerNameServer(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	paths, _ := Paths()
+
+	files := map[string]string{
+		filepath.Join(paths.Simulations, "market.json"): `{"name": "market", "agents": []}`,
+		filepath.Join(paths.Metrics, "market.json"):     `{}`,
+		filepath.Join(paths.Simulations, "report.json"): `{"name": "report", "agents": []}`,
+		filepath.Join(paths.Metrics, "report.json"):     `{}`,
+		paths.Server: `{"port": 8080}`,
+		filepath.Join(paths.Servers, "market.json"): `{"port": 9100}`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	market, err := LoadSimulationConfig("market")
+	if err != nil {
+		t.Fatalf("LoadSimulationConfig(market) error = %v", err)
+	}
+	if want := filepath.Join(paths.Servers, "market.json"); market.ServerPath != want {
+		t.Errorf("market ServerPath = %s, want %s", market.ServerPath, want)
+	}
+	if market.Server["port"] != float64(9100) {
+		t.Errorf("market server port = %v, want the per-name 9100", market.Server["port"])
+	}
+
+	report, err := LoadSimulationConfig("report")
+	if err != nil {
+		t.Fatalf("LoadSimulationConfig(report) error = %v", err)
+	}
+	if report.ServerPath != paths.Server {
+		t.Errorf("report ServerPath = %s, want the default %s", report.ServerPath, paths.Server)
+	}
+	if report.Server["port"] != float64(8080) {
+		t.Errorf("report server port = %v, want the default 8080", report.Server["port"])
+	}
+}
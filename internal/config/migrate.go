@@ -0,0 +1,369 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the on-disk layout version this CLI reads and writes. It
+// is recorded as the top-level version key of ~/.autobox/autobox.yaml. Bump
+// it and append to migrations whenever the layout or a settings key changes.
+const SchemaVersion = 2
+
+// legacySchemaVersionFile is the marker earlier releases recorded the
+// version in. It is only read when autobox.yaml has no version key, and is
+// removed once the version is written there.
+const legacySchemaVersionFile = ".schema_version"
+
+type migration struct {
+	version     int
+	description string
+	apply       func(autoboxDir string, report func(string)) error
+}
+
+var migrations = []migration{
+	{legacyLayoutVersion, "move legacy configs/ directory to config/", migrateLegacyConfigsDir},
+	{2, "rename simulation.default_image to docker.image", migrateDefaultImageKey},
+}
+
+// LayoutVersion reports the schema version of the ~/.autobox directory, read
+// from the version key of autobox.yaml. Without one it falls back to the
+// legacy marker file; failing that, a tree is version 0 if it still has the
+// legacy configs/ directory, 1 if it has an unversioned autobox.yaml, and
+// current otherwise.
+func LayoutVersion() (int, error) {
+	paths, err := Paths()
+	if err != nil {
+		return 0, err
+	}
+
+	settingsPath := filepath.Join(paths.Root, settingsFile)
+	doc, err := readSettings(settingsPath)
+	if err != nil {
+		return 0, err
+	}
+	if doc != nil {
+		if node := mappingValue(settingsRoot(doc), "version"); node != nil {
+			version, err := strconv.Atoi(node.Value)
+			if err != nil {
+				return 0, fmt.Errorf("invalid version in %s: %q", settingsPath, node.Value)
+			}
+			return version, nil
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(paths.Root, legacySchemaVersionFile))
+	if err == nil {
+		version, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			return 0, fmt.Errorf("invalid legacy schema version marker: %w", err)
+		}
+		return version, nil
+	}
+	if !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if _, err := os.Stat(paths.LegacyConfig); err == nil {
+		return 0, nil
+	}
+	if doc != nil {
+		return legacyLayoutVersion, nil
+	}
+	return SchemaVersion, nil
+}
+
+// CheckLayoutVersion returns a warning when the on-disk layout was written by
+// a different CLI version, or an empty string when they agree.
+func CheckLayoutVersion() string {
+	version, err := LayoutVersion()
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case version > SchemaVersion:
+		return fmt.Sprintf("~/.autobox uses schema version %d, newer than this CLI supports (%d); consider upgrading autobox", version, SchemaVersion)
+	case version < SchemaVersion:
+		return fmt.Sprintf("~/.autobox uses schema version %d, older than this CLI expects (%d); run 'autobox migrate'", version, SchemaVersion)
+	default:
+		return ""
+	}
+}
+
+// Migrate upgrades the ~/.autobox layout to SchemaVersion, calling report
+// for each action taken. It is safe to run repeatedly.
+func Migrate(report func(string)) error {
+	current, err := LayoutVersion()
+	if err != nil {
+		return err
+	}
+	if current > SchemaVersion {
+		return fmt.Errorf("layout version %d is newer than this CLI supports (%d)", current, SchemaVersion)
+	}
+
+	paths, err := Paths()
+	if err != nil {
+		return err
+	}
+	autoboxDir := paths.Root
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		report(fmt.Sprintf("Migrating to version %d: %s", m.version, m.description))
+		if err := m.apply(autoboxDir, report); err != nil {
+			return fmt.Errorf("migration to version %d failed: %w", m.version, err)
+		}
+	}
+
+	return writeLayoutVersion(autoboxDir, SchemaVersion)
+}
+
+// legacyLayoutVersion is the version that moved configs/ to config/.
+const legacyLayoutVersion = 1
+
+// MigrateLegacyLayout moves a legacy configs/ tree into config/, calling
+// report for each action taken. Unlike Migrate it only ever applies that one
+// migration, so it is safe to run automatically: files already in config/
+// are never overwritten, moved files keep their contents and permissions,
+// and the version it records in autobox.yaml makes later calls a no-op.
+func MigrateLegacyLayout(report func(string)) error {
+	current, err := LayoutVersion()
+	if err != nil {
+		return err
+	}
+	if current >= legacyLayoutVersion {
+		return nil
+	}
+
+	paths, err := Paths()
+	if err != nil {
+		return err
+	}
+	if err := migrateLegacyConfigsDir(paths.Root, report); err != nil {
+		return err
+	}
+	return writeLayoutVersion(paths.Root, legacyLayoutVersion)
+}
+
+const settingsFile = "autobox.yaml"
+
+// writeLayoutVersion records version in autobox.yaml, starting from the
+// scaffolded settings if there is none yet, and removes the legacy marker.
+func writeLayoutVersion(autoboxDir string, version int) error {
+	path := filepath.Join(autoboxDir, settingsFile)
+	doc, err := readSettings(path)
+	if err != nil {
+		return err
+	}
+	if doc == nil {
+		if err := os.MkdirAll(autoboxDir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", autoboxDir, err)
+		}
+		doc = &yaml.Node{}
+		if err := yaml.Unmarshal([]byte(starterSettings), doc); err != nil {
+			return fmt.Errorf("failed to parse starter settings: %w", err)
+		}
+	}
+
+	root := settingsRoot(doc)
+	if node := mappingValue(root, "version"); node != nil {
+		node.SetString(strconv.Itoa(version))
+		node.Tag = "!!int"
+	} else {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: "version"}
+		value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+		root.Content = append([]*yaml.Node{key, value}, root.Content...)
+	}
+
+	if err := writeSettings(path, doc); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(autoboxDir, legacySchemaVersionFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove legacy schema version marker: %w", err)
+	}
+	return nil
+}
+
+// readSettings parses autobox.yaml keeping its comments, returning nil if
+// the file doesn't exist.
+func readSettings(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(doc.Content) > 0 && doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a mapping", path)
+	}
+	return doc, nil
+}
+
+func writeSettings(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, buf.Bytes(), mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// settingsRoot returns the top-level mapping of a settings document, adding
+// an empty one to an empty document.
+func settingsRoot(doc *yaml.Node) *yaml.Node {
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	return doc.Content[0]
+}
+
+// mappingValue returns the value stored under key in a mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// removeMappingKey deletes key from a mapping node, returning its key and
+// value nodes, or nil if it wasn't there.
+func removeMappingKey(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			k, v := mapping.Content[i], mapping.Content[i+1]
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return k, v
+		}
+	}
+	return nil, nil
+}
+
+// migrateDefaultImageKey moves simulation.default_image, which run never
+// read, to docker.image. A docker.image that is already set wins and the old
+// key is dropped.
+func migrateDefaultImageKey(autoboxDir string, report func(string)) error {
+	path := filepath.Join(autoboxDir, settingsFile)
+	doc, err := readSettings(path)
+	if err != nil || doc == nil {
+		return err
+	}
+
+	root := settingsRoot(doc)
+	key, value := removeMappingKey(mappingValue(root, "simulation"), "default_image")
+	if key == nil {
+		return nil
+	}
+
+	docker := mappingValue(root, "docker")
+	switch {
+	case docker != nil && docker.Kind != yaml.MappingNode:
+		return fmt.Errorf("docker in %s is not a mapping", path)
+	case docker == nil:
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "docker"},
+			&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+		docker = root.Content[len(root.Content)-1]
+		fallthrough
+	case mappingValue(docker, "image") == nil:
+		key.Value = "image"
+		docker.Content = append(docker.Content, key, value)
+		report(fmt.Sprintf("Renamed simulation.default_image to docker.image in %s", path))
+	default:
+		report(fmt.Sprintf("Removed simulation.default_image from %s (docker.image is already set)", path))
+	}
+	return writeSettings(path, doc)
+}
+
+func migrateLegacyConfigsDir(autoboxDir string, report func(string)) error {
+	legacy := filepath.Join(autoboxDir, "configs")
+	if _, err := os.Stat(legacy); os.IsNotExist(err) {
+		return nil
+	}
+
+	target := filepath.Join(autoboxDir, "config")
+	if err := mergeDir(legacy, target, report); err != nil {
+		return err
+	}
+
+	if entries, err := os.ReadDir(legacy); err == nil && len(entries) == 0 {
+		if err := os.Remove(legacy); err == nil {
+			report(fmt.Sprintf("Removed empty %s", legacy))
+		}
+	}
+	return nil
+}
+
+// mergeDir moves everything under src into dst, descending into directories
+// that exist on both sides and never overwriting an existing file.
+func mergeDir(src, dst string, report func(string)) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+
+	for _, entry := range entries {
+		from := filepath.Join(src, entry.Name())
+		to := filepath.Join(dst, entry.Name())
+
+		info, err := os.Stat(to)
+		switch {
+		case os.IsNotExist(err):
+			if err := os.Rename(from, to); err != nil {
+				return fmt.Errorf("failed to move %s: %w", from, err)
+			}
+			report(fmt.Sprintf("Moved %s -> %s", from, to))
+		case err != nil:
+			return fmt.Errorf("failed to stat %s: %w", to, err)
+		case entry.IsDir() && info.IsDir():
+			if err := mergeDir(from, to, report); err != nil {
+				return err
+			}
+			if remaining, err := os.ReadDir(from); err == nil && len(remaining) == 0 {
+				os.Remove(from)
+			}
+		default:
+			report(fmt.Sprintf("Skipped %s (%s already exists)", from, to))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateLegacyConfigsDir(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "autobox-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	legacySims := filepath.Join(tmpDir, ".autobox", "configs", "simulations")
+	currentSims := filepath.Join(tmpDir, ".autobox", "config", "simulations")
+	if err := os.MkdirAll(legacySims, 0755); err != nil {
+		t.Fatalf("Failed to create legacy dir: %v", err)
+	}
+	if err := os.MkdirAll(currentSims, 0755); err != nil {
+		t.Fatalf("Failed to create current dir: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(legacySims, "legacy.json"), []byte(`{"name": "legacy"}`), 0644); err != nil {
+		t.Fatalf("Failed to write legacy config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(legacySims, "both.json"), []byte(`{"name": "old"}`), 0644); err != nil {
+		t.Fatalf("Failed to write legacy config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(currentSims, "both.json"), []byte(`{"name": "new"}`), 0644); err != nil {
+		t.Fatalf("Failed to write current config: %v", err)
+	}
+
+	if version, _ := LayoutVersion(); version != 0 {
+		t.Errorf("Expected legacy layout version 0, got %d", version)
+	}
+	if CheckLayoutVersion() == "" {
+		t.Errorf("Expected a version warning for a legacy layout")
+	}
+
+	var actions []string
+	if err := Migrate(func(action string) { actions = append(actions, action) }); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(actions) == 0 {
+		t.Errorf("Expected migration actions to be reported")
+	}
+
+	if _, err := os.Stat(filepath.Join(currentSims, "legacy.json")); err != nil {
+		t.Errorf("Expected legacy.json to be moved: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(currentSims, "both.json"))
+	if err != nil {
+		t.Fatalf("Failed to read both.json: %v", err)
+	}
+	if string(data) != `{"name": "new"}` {
+		t.Errorf("Expected existing config to be preserved, got %s", data)
+	}
+
+	if version, _ := LayoutVersion(); version != SchemaVersion {
+		t.Errorf("Expected layout version %d after migration, got %d", SchemaVersion, version)
+	}
+	if warning := CheckLayoutVersion(); warning != "" {
+		t.Errorf("Expected no warning after migration, got %q", warning)
+	}
+
+	if err := Migrate(func(string) {}); err != nil {
+		t.Errorf("Expected repeated Migrate() to succeed, got %v", err)
+	}
+}
+
+func TestCheckLayoutVersionNewer(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "autobox-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	autoboxDir := filepath.Join(tmpDir, ".autobox")
+	if err := os.MkdirAll(autoboxDir, 0755); err != nil {
+		t.Fatalf("Failed to create autobox dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(autoboxDir, "autobox.yaml"), []byte("version: 99\n"), 0644); err != nil {
+		t.Fatalf("Failed to write autobox.yaml: %v", err)
+	}
+
+	if CheckLayoutVersion() == "" {
+		t.Errorf("Expected a warning for a newer layout")
+	}
+	if err := Migrate(func(string) {}); err == nil {
+		t.Errorf("Expected Migrate() to refuse a newer layout")
+	}
+}
+
+func TestMigrateRenamesDefaultImage(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	paths, _ := Paths()
+
+	settingsPath := filepath.Join(paths.Root, "autobox.yaml")
+	if err := os.MkdirAll(paths.Root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `# my settings
+docker:
+  # shared host
+  label_prefix: com.example
+simulation:
+  default_image: custom-engine:1.2
+  strict_names: true
+`
+	if err := os.WriteFile(settingsPath, []byte(settings), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unversioned autobox.yaml predates the version key.
+	if version, _ := LayoutVersion(); version != legacyLayoutVersion {
+		t.Errorf("LayoutVersion() = %d, want %d", version, legacyLayoutVersion)
+	}
+
+	var actions []string
+	if err := Migrate(func(action string) { actions = append(actions, action) }); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(actions) != 2 {
+		t.Errorf("Migrate() reported %q, want the step and the rename", actions)
+	}
+
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Version    int
+		Docker     map[string]string
+		Simulation map[string]any
+	}
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatalf("migrated autobox.yaml doesn't parse: %v\n%s", err, data)
+	}
+	if got.Version != SchemaVersion {
+		t.Errorf("version = %d, want %d", got.Version, SchemaVersion)
+	}
+	if got.Docker["image"] != "custom-engine:1.2" || got.Docker["label_prefix"] != "com.example" {
+		t.Errorf("docker = %v, want the renamed image and the existing label_prefix", got.Docker)
+	}
+	if _, ok := got.Simulation["default_image"]; ok || got.Simulation["strict_names"] != true {
+		t.Errorf("simulation = %v, want default_image removed and strict_names kept", got.Simulation)
+	}
+	for _, comment := range []string{"# my settings", "# shared host"} {
+		if !strings.Contains(string(data), comment) {
+			t.Errorf("migrated autobox.yaml lost %q:\n%s", comment, data)
+		}
+	}
+	if info, err := os.Stat(settingsPath); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("autobox.yaml mode = %v, want 0600 kept", info.Mode().Perm())
+	}
+}
+
+func TestMigrateKeepsExistingDockerImage(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	paths, _ := Paths()
+
+	settingsPath := filepath.Join(paths.Root, "autobox.yaml")
+	if err := os.MkdirAll(paths.Root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The legacy marker is read when autobox.yaml has no version.
+	if err := os.WriteFile(filepath.Join(paths.Root, legacySchemaVersionFile), []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := "docker:\n  image: mine:latest\nsimulation:\n  default_image: old:latest\n"
+	if err := os.WriteFile(settingsPath, []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Migrate(func(string) {}); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(settingsPath)
+	if !strings.Contains(string(data), "image: mine:latest") || strings.Contains(string(data), "old:latest") {
+		t.Errorf("Migrate() didn't keep docker.image and drop default_image:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(paths.Root, legacySchemaVersionFile)); !os.IsNotExist(err) {
+		t.Error("Migrate() left the legacy version marker behind")
+	}
+	if version, _ := LayoutVersion(); version != SchemaVersion {
+		t.Errorf("LayoutVersion() = %d after migrating, want %d", version, SchemaVersion)
+	}
+}
+
+func TestMigrateLegacyLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	paths, _ := Paths()
+
+	legacy := map[string]struct {
+		content string
+		mode    os.FileMode
+	}{
+		"simulations/market.json": {`{"name": "market"}`, 0600},
+		"simulations/both.json":   {`{"name": "old"}`, 0644},
+		"metrics/market.json":     {`{"enabled": true}`, 0640},
+		"server.json":             {`{"port": 8080}`, 0600},
+	}
+	for name, file := range legacy {
+		path := filepath.Join(paths.LegacyConfig, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(file.content), file.mode); err != nil {
+			t.Fatal(err)
+		}
+		// WriteFile is subject to the umask; set the mode exactly.
+		if err := os.Chmod(path, file.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(paths.Simulations, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(paths.Simulations, "both.json"), []byte(`{"name": "new"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var actions []string
+	if err := MigrateLegacyLayout(func(action string) { actions = append(actions, action) }); err != nil {
+		t.Fatalf("MigrateLegacyLayout() error = %v", err)
+	}
+	if len(actions) == 0 {
+		t.Error("MigrateLegacyLayout() reported no actions")
+	}
+
+	for name, file := range legacy {
+		if name == "simulations/both.json" {
+			continue
+		}
+		path := filepath.Join(paths.Config, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Errorf("%s was not moved: %v", name, err)
+			continue
+		}
+		if info.Mode().Perm() != file.mode {
+			t.Errorf("%s mode = %v, want %v", name, info.Mode().Perm(), file.mode)
+		}
+		if data, _ := os.ReadFile(path); string(data) != file.content {
+			t.Errorf("%s = %s, want %s", name, data, file.content)
+		}
+		if _, err := os.Stat(filepath.Join(paths.LegacyConfig, name)); !os.IsNotExist(err) {
+			t.Errorf("%s is still in the legacy tree", name)
+		}
+	}
+
+	// An existing target is kept, and the legacy copy left for the user.
+	if data, _ := os.ReadFile(filepath.Join(paths.Simulations, "both.json")); string(data) != `{"name": "new"}` {
+		t.Errorf("existing both.json was overwritten with %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(paths.LegacyConfig, "simulations", "both.json")); err != nil {
+		t.Errorf("skipped legacy both.json was removed: %v", err)
+	}
+
+	if version, _ := LayoutVersion(); version != legacyLayoutVersion {
+		t.Errorf("LayoutVersion() = %d after migrating, want %d", version, legacyLayoutVersion)
+	}
+
+	// The recorded version stops a second run, even though configs/ is still there.
+	actions = nil
+	if err := MigrateLegacyLayout(func(action string) { actions = append(actions, action) }); err != nil {
+		t.Fatalf("second MigrateLegacyLayout() error = %v", err)
+	}
+	if len(actions) != 0 {
+		t.Errorf("second MigrateLegacyLayout() reported %q, want nothing", actions)
+	}
+}
+
+func TestMigrateLegacyLayoutWithoutLegacyTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	if err := MigrateLegacyLayout(func(action string) { t.Errorf("unexpected action %q", action) }); err != nil {
+		t.Fatalf("MigrateLegacyLayout() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".autobox")); !os.IsNotExist(err) {
+		t.Error("MigrateLegacyLayout() created ~/.autobox with nothing to migrate")
+	}
+}
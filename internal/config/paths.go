@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LayoutPaths are the locations of everything the CLI keeps under
+// ~/.autobox. Build them with Paths rather than joining paths by hand, so
+// that every command reads and writes the same tree.
+type LayoutPaths struct {
+	// Root is ~/.autobox, which also holds autobox.yaml.
+	Root string
+	// Config is mounted into simulations as /app/config.
+	Config      string
+	Simulations string
+	Metrics     string
+	Server      string
+	// Servers holds per-simulation server configs, which override Server.
+	Servers string
+	// Runs holds the expanded config copies of each run that needs them. It
+	// is inside Config, so the copies are mounted with it.
+	Runs  string
+	Logs  string
+	State string
+	// LegacyConfig is the configs/ directory of releases before schema
+	// version 1. It is only read as a fallback until 'autobox migrate' has
+	// moved its files into Config.
+	LegacyConfig string
+}
+
+// Paths returns the ~/.autobox layout of the current user.
+func Paths() (LayoutPaths, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return LayoutPaths{}, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	root := filepath.Join(home, ".autobox")
+	configDir := filepath.Join(root, "config")
+	return LayoutPaths{
+		Root:         root,
+		Config:       configDir,
+		Simulations:  filepath.Join(configDir, "simulations"),
+		Metrics:      filepath.Join(configDir, "metrics"),
+		Server:       filepath.Join(configDir, "server.json"),
+		Servers:      filepath.Join(configDir, "server"),
+		Runs:         filepath.Join(configDir, "runs"),
+		Logs:         filepath.Join(root, "logs"),
+		State:        filepath.Join(root, "state"),
+		LegacyConfig: filepath.Join(root, "configs"),
+	}, nil
+}
+
+// configTree returns the config directory a simulation file is read from:
+// Config, or LegacyConfig when only the legacy tree has the file. legacy
+// reports which.
+func (p LayoutPaths) configTree(fileName string) (dir string, legacy bool) {
+	if _, err := os.Stat(filepath.Join(p.Config, "simulations", fileName)); os.IsNotExist(err) {
+		if _, err := os.Stat(filepath.Join(p.LegacyConfig, "simulations", fileName)); err == nil {
+			return p.LegacyConfig, true
+		}
+	}
+	return p.Config, false
+}
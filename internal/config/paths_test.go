@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	paths, err := Paths()
+	if err != nil {
+		t.Fatalf("Paths() error = %v", err)
+	}
+	root := filepath.Join(tmpDir, ".autobox")
+	want := LayoutPaths{
+		Root:         root,
+		Config:       filepath.Join(root, "config"),
+		Simulations:  filepath.Join(root, "config", "simulations"),
+		Metrics:      filepath.Join(root, "config", "metrics"),
+		Server:       filepath.Join(root, "config", "server.json"),
+		Servers:      filepath.Join(root, "config", "server"),
+		Runs:         filepath.Join(root, "config", "runs"),
+		Logs:         filepath.Join(root, "logs"),
+		State:        filepath.Join(root, "state"),
+		LegacyConfig: filepath.Join(root, "configs"),
+	}
+	if paths != want {
+		t.Errorf("Paths() = %+v, want %+v", paths, want)
+	}
+}
+
+// writeConfigFile writes content to path, creating its directory, and stops
+// the test if either fails.
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// Every reader and writer of the config tree must agree on where it is.
+func TestConfigTreeIsShared(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	paths, err := Paths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := EnsureConfigDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{paths.Simulations, paths.Metrics, paths.Logs} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			t.Errorf("EnsureConfigDirectories() didn't create %s", dir)
+		}
+	}
+
+	writeConfigFile(t, filepath.Join(paths.Simulations, "shared.json"), `{"name": "shared", "agents": []}`)
+	writeConfigFile(t, filepath.Join(paths.Metrics, "shared.json"), `{}`)
+
+	if path, _ := SimulationConfigPath("shared"); path != filepath.Join(paths.Simulations, "shared.json") {
+		t.Errorf("SimulationConfigPath() = %s", path)
+	}
+	if err := ValidateSimulationConfig("shared"); err != nil {
+		t.Errorf("ValidateSimulationConfig() error = %v", err)
+	}
+	configSet, err := LoadSimulationConfig("shared")
+	if err != nil {
+		t.Fatalf("LoadSimulationConfig() error = %v", err)
+	}
+	if configSet.SimulationPath != filepath.Join(paths.Simulations, "shared.json") ||
+		configSet.MetricsPath != filepath.Join(paths.Metrics, "shared.json") ||
+		configSet.ServerPath != paths.Server || configSet.Legacy {
+		t.Errorf("LoadSimulationConfig() paths = %+v", configSet)
+	}
+	if names, _ := ListAvailableSimulations(); len(names) != 1 || names[0] != "shared" {
+		t.Errorf("ListAvailableSimulations() = %v", names)
+	}
+
+	setDefaults()
+	if got := viper.GetString("simulation.config_directory"); got != paths.Config {
+		t.Errorf("default simulation.config_directory = %s, want %s", got, paths.Config)
+	}
+	if got := viper.GetStringSlice("simulation.default_volumes"); len(got) != 1 || got[0] != paths.Config+":/app/config" {
+		t.Errorf("default simulation.default_volumes = %v", got)
+	}
+}
+
+func TestLegacyConfigFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	paths, err := Paths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeConfigFile(t, filepath.Join(paths.LegacyConfig, "simulations", "old.json"), `{"name": "old", "agents": []}`)
+	writeConfigFile(t, filepath.Join(paths.LegacyConfig, "metrics", "old.json"), `{}`)
+	writeConfigFile(t, filepath.Join(paths.LegacyConfig, "simulations", "both.json"), `{"name": "legacy copy"}`)
+	writeConfigFile(t, filepath.Join(paths.Simulations, "both.json"), `{"name": "both", "agents": []}`)
+	writeConfigFile(t, filepath.Join(paths.Metrics, "both.json"), `{}`)
+
+	configSet, err := LoadSimulationConfig("old")
+	if err != nil {
+		t.Fatalf("LoadSimulationConfig(old) error = %v", err)
+	}
+	if !configSet.Legacy || configSet.SimulationPath != filepath.Join(paths.LegacyConfig, "simulations", "old.json") {
+		t.Errorf("LoadSimulationConfig(old) = %+v, want it read from the legacy tree", configSet)
+	}
+	if err := ValidateSimulationConfig("old"); err != nil {
+		t.Errorf("ValidateSimulationConfig(old) error = %v", err)
+	}
+
+	configSet, err = LoadSimulationConfig("both")
+	if err != nil {
+		t.Fatalf("LoadSimulationConfig(both) error = %v", err)
+	}
+	if configSet.Legacy || configSet.Simulation["name"] != "both" {
+		t.Errorf("LoadSimulationConfig(both) = %+v, want the current tree to win", configSet)
+	}
+
+	names, _ := ListAvailableSimulations()
+	if len(names) != 2 || names[0] != "both" || names[1] != "old" {
+		t.Errorf("ListAvailableSimulations() = %v, want [both old]", names)
+	}
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScaffoldStatus is what Scaffold did with one starter file.
+type ScaffoldStatus int
+
+const (
+	// ScaffoldCreated means the file didn't exist and was written.
+	ScaffoldCreated ScaffoldStatus = iota
+	// ScaffoldOverwritten means an existing file was replaced because of force.
+	ScaffoldOverwritten
+	// ScaffoldKept means an existing file was left alone.
+	ScaffoldKept
+)
+
+// ScaffoldResult reports one starter file. Invalid is set when a kept file
+// failed the same check the starter file passes, so it can be fixed or
+// replaced with force.
+type ScaffoldResult struct {
+	Path    string
+	Status  ScaffoldStatus
+	Invalid error
+}
+
+type scaffoldFile struct {
+	path     string
+	content  string
+	validate func(data []byte) error
+}
+
+func scaffoldFiles(paths LayoutPaths) []scaffoldFile {
+	return []scaffoldFile{
+		{filepath.Join(paths.Root, "autobox.yaml"), starterSettings, validateYAML},
+		{filepath.Join(paths.Simulations, "example.json"), starterSimulation, ValidateSimulationDocument},
+		{filepath.Join(paths.Metrics, "example.json"), starterMetrics, validateJSON},
+	}
+}
+
+const starterSettings = `# autobox CLI settings. Every key is optional; the values shown are the
+# defaults. Keys that are commented out default to something else, such as
+# the Docker environment variables, and show an example value. Any key can
+# also be set from the environment, for example AUTOBOX_DOCKER_IMAGE for
+# docker.image.
+
+# Schema version of the ~/.autobox layout; autobox migrate updates it.
+version: 2
+
+docker:
+  # Daemon to run simulations on; when unset, DOCKER_HOST, or else the local
+  # socket. Point it at a remote tcp:// host to run them on a bigger machine.
+  # host: tcp://simulations.example.com:2376
+  # For a tcp:// host secured with TLS: verify the daemon and authenticate
+  # with ca.pem, cert.pem and key.pem from cert_path. When unset,
+  # DOCKER_TLS_VERIFY and DOCKER_CERT_PATH apply.
+  # tls_verify: true
+  # cert_path: ~/.docker
+  # Engine image used by run when --image isn't given.
+  image: autobox-engine:latest
+  # Label namespace for created and listed simulations. Give each fleet on a
+  # shared host its own prefix to keep them apart.
+  label_prefix: com.autobox
+
+simulation:
+  # Turn off name normalization, so "My-Sim" loads My-Sim.json rather than
+  # my_sim.json.
+  strict_names: false
+  # Environment variables set in every simulation; --env overrides them.
+  # default_environment:
+  #   LOG_LEVEL: info
+
+output:
+  # table, json or yaml.
+  format: table
+  color: true
+  # Environment variables whose names match this regular expression have
+  # their values masked in run -v, status, list, inspect and webhook bodies.
+  secret_pattern: (?i)(KEY|TOKEN|SECRET|PASSWORD)
+
+# Default for run --webhook: the simulation JSON is POSTed here on launch and
+# terminate.
+# webhook:
+#   url: https://example.com/hook
+#   timeout: 5s
+#   retries: 3
+`
+
+const starterSimulation = `{
+  "name": "example",
+  "agents": [
+    {
+      "name": "planner",
+      "role": "Plan the tasks needed to reach the goal"
+    },
+    {
+      "name": "worker",
+      "role": "Carry out the tasks the planner hands out"
+    }
+  ],
+  "duration": 600,
+  "output": "/app/logs/example.json"
+}
+`
+
+const starterMetrics = `{
+  "enabled": true,
+  "interval": 60,
+  "collectors": ["cpu", "memory", "network", "disk"]
+}
+`
+
+// Scaffold sets up ~/.autobox for a new user: it creates the config
+// directories and writes a commented autobox.yaml and an example simulation
+// with its metrics config. Existing files are kept unless force is set, so
+// running it again never loses edits.
+func Scaffold(force bool) ([]ScaffoldResult, error) {
+	if err := EnsureConfigDirectories(); err != nil {
+		return nil, err
+	}
+	paths, err := Paths()
+	if err != nil {
+		return nil, err
+	}
+
+	files := scaffoldFiles(paths)
+	results := make([]ScaffoldResult, 0, len(files))
+	for _, file := range files {
+		path := file.path
+		result := ScaffoldResult{Path: path, Status: ScaffoldCreated}
+
+		existing, err := os.ReadFile(path)
+		switch {
+		case err == nil && !force:
+			result.Status = ScaffoldKept
+			result.Invalid = file.validate(existing)
+			results = append(results, result)
+			continue
+		case err == nil:
+			result.Status = ScaffoldOverwritten
+		case !os.IsNotExist(err):
+			return results, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if err := os.WriteFile(path, []byte(file.content), 0644); err != nil {
+			return results, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func validateYAML(data []byte) error {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+	return nil
+}
+
+func validateJSON(data []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestScaffold(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	autoboxDir := filepath.Join(tmpDir, ".autobox")
+
+	results, err := Scaffold(false)
+	if err != nil {
+		t.Fatalf("Scaffold() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Scaffold() reported %d files, want 3", len(results))
+	}
+	for _, result := range results {
+		if result.Status != ScaffoldCreated {
+			t.Errorf("%s: status = %v, want created", result.Path, result.Status)
+		}
+		if _, err := os.Stat(result.Path); err != nil {
+			t.Errorf("%s was not written: %v", result.Path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(autoboxDir, "logs")); err != nil {
+		t.Errorf("logs directory was not created: %v", err)
+	}
+
+	// The starter files must pass the checks run and the loader apply.
+	if err := ValidateSimulationConfig("example"); err != nil {
+		t.Errorf("example simulation is not valid: %v", err)
+	}
+	if _, err := LoadSimulationConfig("example"); err != nil {
+		t.Errorf("example simulation doesn't load: %v", err)
+	}
+	settings, _ := os.ReadFile(filepath.Join(autoboxDir, "autobox.yaml"))
+	if err := validateYAML(settings); err != nil {
+		t.Errorf("autobox.yaml is not valid: %v", err)
+	}
+	if version, _ := LayoutVersion(); version != SchemaVersion {
+		t.Errorf("starter autobox.yaml records version %d, want %d", version, SchemaVersion)
+	}
+
+	// Setting these would hide DOCKER_HOST and DOCKER_TLS_VERIFY.
+	viper.Reset()
+	viper.SetConfigFile(filepath.Join(autoboxDir, "autobox.yaml"))
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig() error = %v", err)
+	}
+	defer viper.Reset()
+	for _, key := range []string{"docker.host", "docker.tls_verify", "docker.cert_path"} {
+		if IsConfigured(key) {
+			t.Errorf("starter autobox.yaml sets %s, want it commented out", key)
+		}
+	}
+}
+
+func TestScaffoldPreservesExistingFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	simPath := filepath.Join(tmpDir, ".autobox", "config", "simulations", "example.json")
+	settingsPath := filepath.Join(tmpDir, ".autobox", "autobox.yaml")
+	if err := os.MkdirAll(filepath.Dir(simPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mine := `{"name": "mine", "agents": []}`
+	if err := os.WriteFile(simPath, []byte(mine), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settingsPath, []byte("docker: [unclosed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Scaffold(false)
+	if err != nil {
+		t.Fatalf("Scaffold() error = %v", err)
+	}
+	byPath := map[string]ScaffoldResult{}
+	for _, result := range results {
+		byPath[result.Path] = result
+	}
+
+	if r := byPath[simPath]; r.Status != ScaffoldKept || r.Invalid != nil {
+		t.Errorf("existing simulation: %+v, want kept and valid", r)
+	}
+	if data, _ := os.ReadFile(simPath); string(data) != mine {
+		t.Errorf("existing simulation was changed to %s", data)
+	}
+	if r := byPath[settingsPath]; r.Status != ScaffoldKept || r.Invalid == nil {
+		t.Errorf("broken autobox.yaml: %+v, want kept and reported invalid", r)
+	}
+	metricsPath := filepath.Join(tmpDir, ".autobox", "config", "metrics", "example.json")
+	if r := byPath[metricsPath]; r.Status != ScaffoldCreated {
+		t.Errorf("missing metrics config: %+v, want created", r)
+	}
+
+	results, err = Scaffold(true)
+	if err != nil {
+		t.Fatalf("Scaffold(force) error = %v", err)
+	}
+	for _, result := range results {
+		if result.Status != ScaffoldOverwritten {
+			t.Errorf("%s: status = %v, want overwritten", result.Path, result.Status)
+		}
+	}
+	if data, _ := os.ReadFile(simPath); string(data) != starterSimulation {
+		t.Errorf("--force left the simulation as %s", data)
+	}
+}
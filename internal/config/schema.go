@@ -0,0 +1,170 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidateSimulationDocument checks the contents of a simulation config file
+// before it is saved: it must be a single JSON object, and the fields the
+// engine reads must have the right types when present. Unlike
+// ValidateSimulationSchema it doesn't require any field, so a config can be
+// saved while still being written.
+func ValidateSimulationDocument(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return fmt.Errorf("invalid JSON: unexpected content after the top-level object")
+	}
+
+	fields, ok := doc.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("simulation config must be a JSON object")
+	}
+
+	if problems := simulationSchemaProblems(fields, false); len(problems) > 0 {
+		return &SchemaError{Problems: problems}
+	}
+	return nil
+}
+
+// SchemaError lists every problem found in a simulation config, so they can
+// all be fixed in one pass.
+type SchemaError struct {
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid simulation config: " + e.Problems[0]
+	}
+	return "invalid simulation config:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// simulationKeys are the top-level simulation config fields the engine reads.
+var simulationKeys = []string{"name", "agents", "duration", "output"}
+
+// ValidateSimulationSchema checks a parsed simulation config before launch:
+// "name" and "agents" are required, the fields the engine reads must have
+// the right types, and keys that look like misspellings of them, such as
+// "durration", are reported instead of being silently ignored. Other keys
+// are allowed. All problems are returned together as a *SchemaError.
+func ValidateSimulationSchema(fields map[string]interface{}) error {
+	if problems := simulationSchemaProblems(fields, true); len(problems) > 0 {
+		return &SchemaError{Problems: problems}
+	}
+	return nil
+}
+
+func simulationSchemaProblems(fields map[string]interface{}, requireKeys bool) []string {
+	var problems []string
+
+	if name, ok := fields["name"]; ok {
+		if s, isString := name.(string); !isString || s == "" {
+			problems = append(problems, `"name" must be a non-empty string`)
+		}
+	} else if requireKeys {
+		problems = append(problems, `"name" is required`)
+	}
+
+	if agents, ok := fields["agents"]; ok {
+		if list, isArray := agents.([]interface{}); !isArray {
+			problems = append(problems, `"agents" must be an array`)
+		} else {
+			for i, agent := range list {
+				if _, isObject := agent.(map[string]interface{}); !isObject {
+					problems = append(problems, fmt.Sprintf(`"agents[%d]" must be an object`, i))
+				}
+			}
+		}
+	} else if requireKeys {
+		problems = append(problems, `"agents" is required`)
+	}
+
+	if duration, ok := fields["duration"]; ok {
+		if v, isNumber := numberValue(duration); !isNumber {
+			problems = append(problems, `"duration" must be a number of seconds`)
+		} else if v <= 0 {
+			problems = append(problems, `"duration" must be positive`)
+		}
+	}
+
+	if out, ok := fields["output"]; ok {
+		if _, isString := out.(string); !isString {
+			problems = append(problems, `"output" must be a string`)
+		}
+	}
+
+	var unknown []string
+	for key := range fields {
+		if suggestion := misspelledKey(key); suggestion != "" {
+			unknown = append(unknown, fmt.Sprintf("unknown key %q (did you mean %q?)", key, suggestion))
+		}
+	}
+	sort.Strings(unknown)
+	return append(problems, unknown...)
+}
+
+// numberValue accepts numbers decoded either way encoding/json produces them.
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// misspelledKey returns the simulation key that key is probably a typo of,
+// or "" if key is a known key or isn't close to one.
+func misspelledKey(key string) string {
+	lower := strings.ToLower(key)
+	for _, known := range simulationKeys {
+		if key == known {
+			return ""
+		}
+	}
+	for _, known := range simulationKeys {
+		// One slip in a short key, two in a longer one.
+		maxEdits := 1
+		if len(known) > 5 {
+			maxEdits = 2
+		}
+		if lower == known || editDistance(lower, known) <= maxEdits {
+			return known
+		}
+	}
+	return ""
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
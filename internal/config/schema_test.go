@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateSimulationDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"valid", `{"name": "gift_choice", "agents": [], "duration": 3600, "output": "/app/logs/results.json"}`, false},
+		{"extra fields", `{"name": "gift_choice", "seed": 42}`, false},
+		{"empty object", `{}`, false},
+		{"syntax error", `{"name": "gift_choice",}`, true},
+		{"trailing content", `{"name": "a"} {"name": "b"}`, true},
+		{"not an object", `["gift_choice"]`, true},
+		{"empty name", `{"name": ""}`, true},
+		{"agents not array", `{"agents": "agent1"}`, true},
+		{"duration string", `{"duration": "1h"}`, true},
+		{"duration zero", `{"duration": 0}`, true},
+		{"output number", `{"output": 1}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSimulationDocument([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSimulationDocument(%s) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSimulationSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"valid", `{"name": "gift_choice", "agents": [{"name": "buyer"}], "duration": 3600, "output": "/app/logs/results.json"}`, nil},
+		{"extra keys allowed", `{"name": "gift_choice", "agents": [], "seed": 42, "model": "gpt"}`, nil},
+		{"empty object", `{}`, []string{`"name" is required`, `"agents" is required`}},
+		{"missing name", `{"agents": []}`, []string{`"name" is required`}},
+		{"typo", `{"name": "a", "agents": [], "durration": 60}`, []string{`unknown key "durration" (did you mean "duration"?)`}},
+		{"capitalized key", `{"Name": "a", "agents": []}`, []string{`"name" is required`, `unknown key "Name" (did you mean "name"?)`}},
+		{"wrong types", `{"name": 7, "agents": {"buyer": {}}, "duration": "1h", "output": false}`, []string{
+			`"name" must be a non-empty string`,
+			`"agents" must be an array`,
+			`"duration" must be a number of seconds`,
+			`"output" must be a string`,
+		}},
+		{"agent not an object", `{"name": "a", "agents": [{"name": "buyer"}, "seller"]}`, []string{`"agents[1]" must be an object`}},
+		{"negative duration", `{"name": "a", "agents": [], "duration": -5}`, []string{`"duration" must be positive`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.data), &fields); err != nil {
+				t.Fatalf("bad test data: %v", err)
+			}
+
+			err := ValidateSimulationSchema(fields)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("ValidateSimulationSchema() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("ValidateSimulationSchema() error = %v, want a *SchemaError", err)
+			}
+			if !reflect.DeepEqual(schemaErr.Problems, tt.want) {
+				t.Errorf("Problems = %q, want %q", schemaErr.Problems, tt.want)
+			}
+			for _, problem := range tt.want {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("Error() = %q, missing %q", err.Error(), problem)
+				}
+			}
+		})
+	}
+}
@@ -1,40 +1,249 @@
 package docker
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Autobox-AI/autobox-cli/internal/config"
 	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 )
 
 const (
 	AutoboxLabelPrefix = "com.autobox"
 	AutoboxImagePrefix = "autobox-engine"
+	// ExternalNamePrefix marks simulations started outside the CLI, which are
+	// shown under their Docker container name.
+	ExternalNamePrefix = "(external) "
 )
 
 type Client struct {
-	cli *client.Client
+	cli         *client.Client
+	labelPrefix string
 }
 
+// SimulationManager is the core set of simulation operations, so that
+// command logic can be exercised against a fake instead of a daemon. *Client
+// implements it. list, status, logs, metrics, stop and terminate take it;
+// commands that need more of Docker use *Client.
+type SimulationManager interface {
+	LaunchSimulation(ctx context.Context, config models.SimulationConfig) (*models.Simulation, error)
+	ListSimulations(ctx context.Context) ([]*models.Simulation, error)
+	ResolveSimulation(ctx context.Context, ref string) (*models.Simulation, error)
+	ResolveContainerID(ctx context.Context, simulationID string) (string, error)
+	GetSimulationStatus(ctx context.Context, simulationID string) (*models.Simulation, error)
+	InspectSimulation(ctx context.Context, simulationID string) (*models.Simulation, error)
+	IsSimulationRunning(ctx context.Context, simulationID string) (bool, error)
+	GetSimulationMetrics(ctx context.Context, simulationID string) (*models.Metrics, error)
+	StreamSimulationMetrics(ctx context.Context, simulationID string) (<-chan *models.Metrics, <-chan error)
+	StopSimulation(ctx context.Context, simulationID string) error
+	KillSimulation(ctx context.Context, simulationID string) error
+	RemoveSimulation(ctx context.Context, simulationID string, opts RemoveOptions) error
+	GetSimulationLogs(ctx context.Context, simulationID string, opts LogOptions) (string, error)
+	GetSimulationLogsStream(ctx context.Context, simulationID string, opts LogOptions) (io.ReadCloser, error)
+	Close() error
+}
+
+var _ SimulationManager = (*Client)(nil)
+
+// WarningHandler, if set, receives problems found while connecting that don't
+// prevent the client from working, such as a daemon older than
+// docker.api_version.
+var WarningHandler func(message string)
+
+// NewClient connects to Docker as configured under docker: in the config
+// file. A docker.host set in the config file or AUTOBOX_DOCKER_HOST takes
+// precedence; otherwise the standard DOCKER_HOST, DOCKER_TLS_VERIFY and
+// DOCKER_CERT_PATH environment variables apply, falling back to the local
+// socket. Simulations are labelled and listed under docker.label_prefix
+// (default AutoboxLabelPrefix), so fleets with different prefixes don't see
+// each other.
 func NewClient() (*Client, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cfg := config.DockerConfig{
+		APIVersion:  config.GetString("docker.api_version"),
+		TLSVerify:   config.GetBool("docker.tls_verify"),
+		CertPath:    config.GetString("docker.cert_path"),
+		LabelPrefix: LabelPrefix(),
+	}
+	// The built-in default host mustn't hide DOCKER_HOST.
+	if config.IsConfigured("docker.host") {
+		cfg.Host = config.GetString("docker.host")
+	}
+	return NewClientFromConfig(cfg)
+}
+
+// NewClientFromConfig connects to the daemon described by cfg, such as a
+// remote host reached over TLS. Fields left empty fall back to the Docker
+// environment variables. The API version is negotiated with the daemon
+// unless DOCKER_API_VERSION pins it; cfg.APIVersion is only the minimum below
+// which WarningHandler is told about the daemon.
+func NewClientFromConfig(cfg config.DockerConfig) (*Client, error) {
+	opts, err := clientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
 
-	return &Client{cli: cli}, nil
+	prefix := strings.TrimSuffix(cfg.LabelPrefix, ".")
+	if prefix == "" {
+		prefix = AutoboxLabelPrefix
+	}
+	c := &Client{cli: cli, labelPrefix: prefix}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := c.Ping(ctx); err != nil {
+		cli.Close()
+		return nil, err
+	}
+
+	if warning := APIVersionWarning(c.APIVersion(), cfg.APIVersion); warning != "" && WarningHandler != nil {
+		WarningHandler(warning)
+	}
+
+	return c, nil
+}
+
+// TLS file names under cert_path, as used by the docker CLI.
+const (
+	tlsCAFile   = "ca.pem"
+	tlsCertFile = "cert.pem"
+	tlsKeyFile  = "key.pem"
+)
+
+// clientOptions turns cfg into Docker client options, applied over the
+// Docker environment variables.
+func clientOptions(cfg config.DockerConfig) ([]client.Opt, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+	if cfg.Host != "" {
+		opts = append(opts, client.WithHost(cfg.Host))
+	}
+
+	if cfg.TLSVerify {
+		certPath := cfg.CertPath
+		if certPath == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("docker.tls_verify is set but docker.cert_path isn't, and the home directory is unknown: %w", err)
+			}
+			certPath = filepath.Join(home, ".docker")
+		}
+		for _, name := range []string{tlsCAFile, tlsCertFile, tlsKeyFile} {
+			if _, err := os.Stat(filepath.Join(certPath, name)); err != nil {
+				return nil, fmt.Errorf("docker.tls_verify is set but %s is missing from %s (docker.cert_path)", name, certPath)
+			}
+		}
+		opts = append(opts, client.WithTLSClientConfig(
+			filepath.Join(certPath, tlsCAFile),
+			filepath.Join(certPath, tlsCertFile),
+			filepath.Join(certPath, tlsKeyFile),
+		))
+	}
+
+	return opts, nil
+}
+
+// ErrPermissionDenied means the Docker socket exists but the current user may
+// not open it.
+var ErrPermissionDenied = errors.New("permission denied on the Docker socket")
+
+const pingTimeout = 5 * time.Second
+
+// Ping checks that the daemon is reachable and negotiates the API version.
+// The client connects lazily, so without this a missing docker-group
+// membership only shows up as a raw socket error on the first real API call.
+func (c *Client) Ping(ctx context.Context) error {
+	ping, err := c.cli.Ping(ctx)
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("%w at %s: add your user to the docker group (sudo usermod -aG docker $USER, then log out and back in) or run autobox with sudo",
+				ErrPermissionDenied, socketPath(c.cli.DaemonHost()))
+		}
+		return fmt.Errorf("cannot connect to Docker at %s: %w", c.cli.DaemonHost(), err)
+	}
+	c.cli.NegotiateAPIVersionPing(ping)
+	return nil
+}
+
+// APIVersion returns the Docker API version in use, as negotiated with the
+// daemon or fixed by DOCKER_API_VERSION.
+func (c *Client) APIVersion() string {
+	return c.cli.ClientVersion()
+}
+
+// APIVersionWarning explains what a daemon speaking an API older than
+// minimum means for the user, or returns an empty string.
+func APIVersionWarning(negotiated, minimum string) string {
+	if negotiated == "" || minimum == "" || !versions.LessThan(negotiated, minimum) {
+		return ""
+	}
+	return fmt.Sprintf("Docker API version %s is older than the configured minimum %s (docker.api_version); some features may be unavailable, consider upgrading Docker",
+		negotiated, minimum)
+}
+
+// DaemonHost returns the Docker host the client connects to.
+func (c *Client) DaemonHost() string {
+	return c.cli.DaemonHost()
+}
+
+// socketPath strips the unix:// scheme so the path can be checked with ls -l.
+func socketPath(host string) string {
+	return strings.TrimPrefix(host, "unix://")
+}
+
+// LabelPrefix returns the configured docker.label_prefix, or
+// AutoboxLabelPrefix when unset.
+func LabelPrefix() string {
+	prefix := strings.TrimSuffix(config.GetString("docker.label_prefix"), ".")
+	if prefix == "" {
+		return AutoboxLabelPrefix
+	}
+	return prefix
+}
+
+// ReservedLabelKeys are the keys, under the label prefix, that the CLI sets
+// on every simulation and reads back to describe it.
+var ReservedLabelKeys = []string{
+	"simulation", "name", "config_path", "created_at", "cmd",
+	"metadata", "webhook", "engine_version", "image_digest", "entrypoint",
+	"config", "env_keys",
+}
+
+// label returns the full label key for key under the client's prefix.
+func (c *Client) label(key string) string {
+	return c.labelPrefix + "." + key
+}
+
+// IsNotFound reports whether err means the container doesn't exist, as
+// opposed to Docker being unreachable.
+func IsNotFound(err error) bool {
+	return cerrdefs.IsNotFound(err)
 }
 
 func (c *Client) Close() error {
@@ -66,54 +275,120 @@ func (c *Client) findAvailablePort() (string, error) {
 
 func (c *Client) LaunchSimulation(ctx context.Context, config models.SimulationConfig) (*models.Simulation, error) {
 	labels := map[string]string{
-		fmt.Sprintf("%s.simulation", AutoboxLabelPrefix):  "true",
-		fmt.Sprintf("%s.name", AutoboxLabelPrefix):        config.Name,
-		fmt.Sprintf("%s.config_path", AutoboxLabelPrefix): config.ConfigPath,
-		fmt.Sprintf("%s.created_at", AutoboxLabelPrefix):  time.Now().Format(time.RFC3339),
+		c.label("simulation"):  "true",
+		c.label("name"):        config.Name,
+		c.label("config_path"): config.ConfigPath,
+		c.label("created_at"):  time.Now().Format(time.RFC3339),
 	}
 
-	serverPort, _ := c.getServerPort(config.ServerPath)
-	exposedPort := nat.Port(fmt.Sprintf("%s/tcp", serverPort))
+	networkMode, err := c.resolveNetworkMode(ctx, config.NetworkMode)
+	if err != nil {
+		return nil, err
+	}
 
-	hostPort, err := c.findAvailablePort()
+	cmd := engineCommand(config)
+	cmdJSON, err := json.Marshal(cmd)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find available port: %w", err)
+		return nil, fmt.Errorf("failed to encode engine command: %w", err)
+	}
+	labels[c.label("cmd")] = string(cmdJSON)
+
+	if len(config.Metadata) > 0 {
+		metadataJSON, err := json.Marshal(config.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode metadata: %w", err)
+		}
+		labels[c.label("metadata")] = string(metadataJSON)
+	}
+
+	if config.Webhook != "" {
+		labels[c.label("webhook")] = config.Webhook
+	}
+
+	if config.Entrypoint != "" {
+		labels[c.label("entrypoint")] = config.Entrypoint
+	}
+
+	if config.EngineVersion != "" {
+		labels[c.label("engine_version")] = config.EngineVersion
+	}
+
+	if digest, err := c.ResolveImageDigest(ctx, config.Image); err == nil && digest != "" {
+		config.ImageDigest = digest
+		labels[c.label("image_digest")] = digest
+	}
+
+	// Custom labels are validated by the caller; they never replace the
+	// labels the CLI relies on.
+	for key, value := range config.Labels {
+		if _, reserved := labels[key]; !reserved {
+			labels[key] = value
+		}
+	}
+
+	resources, err := resourceLimits(config)
+	if err != nil {
+		return nil, err
+	}
+
+	restartPolicy, err := ParseRestartPolicy(config.RestartPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.addConfigLabels(labels, config); err != nil {
+		return nil, err
 	}
 
 	envVars := c.mapToEnvSlice(config.Environment)
-	envVars = append(envVars, fmt.Sprintf("AUTOBOX_EXTERNAL_PORT=%s", hostPort))
 
 	containerConfig := &container.Config{
 		Image:  config.Image,
 		Labels: labels,
-		Env:    envVars,
-		ExposedPorts: nat.PortSet{
-			exposedPort: struct{}{},
-		},
-		Cmd: []string{
-			"--config", config.ConfigPath,
-			"--metrics", config.MetricsPath,
-			"--server", config.ServerPath,
-		},
+		Cmd:    cmd,
+	}
+	if config.Entrypoint != "" {
+		containerConfig.Entrypoint = []string{config.Entrypoint}
 	}
 
 	hostConfig := &container.HostConfig{
-		Binds:      config.Volumes,
-		AutoRemove: false,
-		PortBindings: nat.PortMap{
+		Binds:         config.Volumes,
+		AutoRemove:    false,
+		NetworkMode:   container.NetworkMode(networkMode),
+		Privileged:    config.Privileged,
+		CapAdd:        config.CapAdd,
+		CapDrop:       config.CapDrop,
+		Resources:     resources,
+		RestartPolicy: restartPolicy,
+	}
+
+	// Docker rejects port publishing when joining another container's network
+	// namespace; the engine is reachable through that container instead.
+	if !hostConfig.NetworkMode.IsContainer() {
+		serverPort, _ := c.getServerPort(config.ServerPath)
+		exposedPort := nat.Port(fmt.Sprintf("%s/tcp", serverPort))
+
+		hostPort, err := c.findAvailablePort()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find available port: %w", err)
+		}
+
+		envVars = append(envVars, fmt.Sprintf("AUTOBOX_EXTERNAL_PORT=%s", hostPort))
+		containerConfig.ExposedPorts = nat.PortSet{
+			exposedPort: struct{}{},
+		}
+		hostConfig.PortBindings = nat.PortMap{
 			exposedPort: []nat.PortBinding{
 				{
 					HostIP:   "0.0.0.0",
 					HostPort: hostPort,
 				},
 			},
-		},
-		RestartPolicy: container.RestartPolicy{
-			Name: "no",
-		},
+		}
 	}
+	containerConfig.Env = envVars
 
-	resp, err := c.cli.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, "")
+	resp, name, err := c.createNamedContainer(ctx, containerName(config.Name, randomSuffix()), containerConfig, hostConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create container: %w", err)
 	}
@@ -122,18 +397,125 @@ func (c *Client) LaunchSimulation(ctx context.Context, config models.SimulationC
 		return nil, fmt.Errorf("failed to start container: %w", err)
 	}
 
-	now := time.Now()
-	simulation := &models.Simulation{
-		ID:          resp.ID[:12],
-		Name:        config.ConfigPath,
-		ContainerID: resp.ID,
+	simulation := newSimulation(resp.ID, config, cmd, time.Now())
+	simulation.ContainerName = name
+	return simulation, nil
+}
+
+// ParseRestartPolicy parses a restart policy as given to docker run
+// --restart: no, always, unless-stopped or on-failure with an optional
+// maximum retry count ("on-failure:5"). An empty policy means no.
+func ParseRestartPolicy(value string) (container.RestartPolicy, error) {
+	name, retries, hasRetries := strings.Cut(strings.TrimSpace(value), ":")
+	policy := container.RestartPolicy{Name: container.RestartPolicyMode(name)}
+
+	switch policy.Name {
+	case "":
+		policy.Name = container.RestartPolicyDisabled
+	case container.RestartPolicyDisabled, container.RestartPolicyAlways, container.RestartPolicyUnlessStopped, container.RestartPolicyOnFailure:
+	default:
+		return policy, fmt.Errorf("invalid restart policy %q (expected no, on-failure[:max-retries], always or unless-stopped)", value)
+	}
+
+	if !hasRetries {
+		return policy, nil
+	}
+	if policy.Name != container.RestartPolicyOnFailure {
+		return policy, fmt.Errorf("invalid restart policy %q: a maximum retry count is only allowed with on-failure", value)
+	}
+	n, err := strconv.Atoi(retries)
+	if err != nil || n < 0 {
+		return policy, fmt.Errorf("invalid restart policy %q: maximum retry count must be a non-negative integer", value)
+	}
+	policy.MaximumRetryCount = n
+	return policy, nil
+}
+
+// newSimulation describes a container that has just been started for config.
+func newSimulation(containerID string, config models.SimulationConfig, cmd []string, startedAt time.Time) *models.Simulation {
+	return &models.Simulation{
+		ID:          containerID[:12],
+		Name:        config.Name,
+		ContainerID: containerID,
 		Status:      models.StatusRunning,
-		CreatedAt:   now,
-		StartedAt:   &now,
+		CreatedAt:   startedAt,
+		StartedAt:   &startedAt,
 		Config:      config,
+		Command:     cmd,
 	}
+}
 
-	return simulation, nil
+// ResolveImageDigest returns the content digest of a locally available image
+// as repo@sha256:..., the form that pins it in a later run.
+func (c *Client) ResolveImageDigest(ctx context.Context, ref string) (string, error) {
+	inspect, err := c.cli.ImageInspect(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image: %w", err)
+	}
+	return digestFromInspect(ref, inspect), nil
+}
+
+// digestFromInspect prefers the registry digest matching the reference's
+// repository. Images that were built locally and never pushed have no repo
+// digests, so the image ID (itself a sha256 content digest) is used instead.
+func digestFromInspect(ref string, inspect image.InspectResponse) string {
+	if at := strings.Index(ref, "@"); at >= 0 {
+		return ref
+	}
+
+	repo := ref
+	if colon := strings.LastIndex(repo, ":"); colon > strings.LastIndex(repo, "/") {
+		repo = repo[:colon]
+	}
+
+	for _, repoDigest := range inspect.RepoDigests {
+		if strings.HasPrefix(repoDigest, repo+"@") {
+			return repoDigest
+		}
+	}
+	if len(inspect.RepoDigests) > 0 {
+		return inspect.RepoDigests[0]
+	}
+	return inspect.ID
+}
+
+// engineCommand builds the container command. A custom entrypoint usually
+// isn't the engine, so it only gets the --engine-arg values, if any, instead
+// of the default --config/--metrics/--server flags.
+func engineCommand(config models.SimulationConfig) []string {
+	if config.Entrypoint != "" {
+		return config.EngineArgs
+	}
+
+	cmd := []string{
+		"--config", config.ConfigPath,
+		"--metrics", config.MetricsPath,
+		"--server", config.ServerPath,
+	}
+	return append(cmd, config.EngineArgs...)
+}
+
+// resolveNetworkMode expands "container:<ref>" to the full ID of a running
+// container so the namespace being joined is unambiguous. Other modes
+// (bridge, host, named networks) are passed through to Docker unchanged.
+func (c *Client) resolveNetworkMode(ctx context.Context, mode string) (string, error) {
+	ref, ok := strings.CutPrefix(mode, "container:")
+	if !ok {
+		return mode, nil
+	}
+	if ref == "" {
+		return "", fmt.Errorf("network mode %q is missing a container reference", mode)
+	}
+
+	target, err := c.cli.ContainerInspect(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve network container %s: %w", ref, err)
+	}
+	if target.State == nil || !target.State.Running {
+		return "", fmt.Errorf("network container %s is not running", ref)
+	}
+
+	return "container:" + target.ID, nil
 }
 
 func (c *Client) GetSimulationStatus(ctx context.Context, simulationID string) (*models.Simulation, error) {
@@ -153,9 +535,116 @@ func (c *Client) GetSimulationStatus(ctx context.Context, simulationID string) (
 	return simulation, nil
 }
 
+// InspectSimulation returns the container-level view of a simulation without
+// querying the engine's HTTP status endpoint, for callers that only need
+// Docker state (timestamps, exit codes) across many containers.
+func (c *Client) InspectSimulation(ctx context.Context, simulationID string) (*models.Simulation, error) {
+	containerJSON, err := c.cli.ContainerInspect(ctx, simulationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	return c.containerToSimulation(containerJSON), nil
+}
+
+// InspectSimulationRaw returns Docker's own inspect output for the
+// simulation's container, unsummarized.
+func (c *Client) InspectSimulationRaw(ctx context.Context, simulationID string) (types.ContainerJSON, error) {
+	containerJSON, err := c.cli.ContainerInspect(ctx, simulationID)
+	if err != nil {
+		return types.ContainerJSON{}, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	return containerJSON, nil
+}
+
+// ResolveContainerID expands an ID prefix to the full container ID.
+func (c *Client) ResolveContainerID(ctx context.Context, simulationID string) (string, error) {
+	containerJSON, err := c.cli.ContainerInspect(ctx, simulationID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect container: %w", err)
+	}
+	return containerJSON.ID, nil
+}
+
+// ErrAmbiguousSimulation is returned by ResolveSimulation when a reference
+// matches more than one simulation.
+var ErrAmbiguousSimulation = errors.New("ambiguous simulation reference")
+
+// ResolveSimulation finds the simulation ref refers to: a container ID or
+// Docker container name, its simulation name, or an ID prefix, in that order
+// of precedence. A reference matching
+// nothing is reported as not found, so IsNotFound holds for the error.
+func (c *Client) ResolveSimulation(ctx context.Context, ref string) (*models.Simulation, error) {
+	simulations, err := c.ListSimulations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resolveSimulation(ref, simulations)
+}
+
+func resolveSimulation(ref string, simulations []*models.Simulation) (*models.Simulation, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("empty simulation ID or name")
+	}
+
+	for _, sim := range simulations {
+		if sim.ContainerID == ref || sim.ID == ref || (sim.ContainerName != "" && sim.ContainerName == ref) {
+			return sim, nil
+		}
+	}
+
+	// Simulations started outside the CLI can be named without the
+	// "(external) " marker they are listed with.
+	var named []*models.Simulation
+	for _, sim := range simulations {
+		if sim.Name == ref || strings.TrimPrefix(sim.Name, ExternalNamePrefix) == ref {
+			named = append(named, sim)
+		}
+	}
+	switch len(named) {
+	case 0:
+	case 1:
+		return named[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d simulations are named %q (%s); use an ID instead",
+			ErrAmbiguousSimulation, len(named), ref, simulationIDs(named))
+	}
+
+	var prefixed []*models.Simulation
+	for _, sim := range simulations {
+		if strings.HasPrefix(sim.ContainerID, ref) {
+			prefixed = append(prefixed, sim)
+		}
+	}
+	switch len(prefixed) {
+	case 0:
+		return nil, fmt.Errorf("no simulation with ID or name %q: %w", ref, cerrdefs.ErrNotFound)
+	case 1:
+		return prefixed[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d simulation IDs start with %q (%s); use a longer prefix",
+			ErrAmbiguousSimulation, len(prefixed), ref, simulationIDs(prefixed))
+	}
+}
+
+func simulationIDs(simulations []*models.Simulation) string {
+	ids := make([]string, len(simulations))
+	for i, sim := range simulations {
+		ids[i] = sim.ID
+	}
+	return strings.Join(ids, ", ")
+}
+
+func (c *Client) IsSimulationRunning(ctx context.Context, simulationID string) (bool, error) {
+	containerJSON, err := c.cli.ContainerInspect(ctx, simulationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	return containerJSON.State != nil && containerJSON.State.Running, nil
+}
+
 func (c *Client) ListSimulations(ctx context.Context) ([]*models.Simulation, error) {
 	filterArgs := filters.NewArgs()
-	filterArgs.Add("label", fmt.Sprintf("%s.simulation=true", AutoboxLabelPrefix))
+	filterArgs.Add("label", c.label("simulation")+"=true")
 
 	containers, err := c.cli.ContainerList(ctx, container.ListOptions{
 		All:     true,
@@ -175,19 +664,166 @@ func (c *Client) ListSimulations(ctx context.Context) ([]*models.Simulation, err
 }
 
 func (c *Client) GetSimulationMetrics(ctx context.Context, simulationID string) (*models.Metrics, error) {
+	stats, err := c.cpuStatsSample(ctx, simulationID)
+	if err != nil {
+		return nil, err
+	}
+	return c.statsToMetrics(stats), nil
+}
+
+// maxConcurrentStats bounds how many stats requests GetMultipleMetrics has in
+// flight. Each one can take a couple of seconds while Docker collects a
+// second CPU sample, so they are worth overlapping, but not without limit.
+const maxConcurrentStats = 8
+
+// metricsSource is the part of Client that fetchMetrics needs.
+type metricsSource interface {
+	GetSimulationMetrics(ctx context.Context, simulationID string) (*models.Metrics, error)
+}
+
+// GetMultipleMetrics fetches a metrics snapshot for each container ID
+// concurrently and returns them keyed by ID. Containers removed while the
+// fetch is in progress are left out of the map rather than failing the
+// others; any other failures are joined into the returned error, alongside
+// the metrics that were fetched.
+func (c *Client) GetMultipleMetrics(ctx context.Context, containerIDs []string) (map[string]*models.Metrics, error) {
+	return fetchMetrics(ctx, c, containerIDs, maxConcurrentStats)
+}
+
+func fetchMetrics(ctx context.Context, source metricsSource, containerIDs []string, workers int) (map[string]*models.Metrics, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		errs    []error
+		results = make(map[string]*models.Metrics, len(containerIDs))
+		slots   = make(chan struct{}, workers)
+	)
+
+	for _, id := range containerIDs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			metrics, err := source.GetSimulationMetrics(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				results[id] = metrics
+			case IsNotFound(err):
+			default:
+				errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
+
+// cpuStatsSample returns a stats sample whose CPU usage can be computed.
+// Docker normally fills in the previous sample's CPU counters itself, but
+// some daemons leave them empty on a one-off request, which would always read
+// as 0% CPU. In that case a second sample is taken and compared with the
+// first.
+func (c *Client) cpuStatsSample(ctx context.Context, simulationID string) (container.StatsResponse, error) {
+	stats, err := c.statsSnapshot(ctx, simulationID)
+	if err != nil || stats.PreCPUStats.CPUUsage.TotalUsage != 0 || stats.Read.IsZero() {
+		return stats, err
+	}
+
+	select {
+	case <-time.After(statsResampleDelay):
+	case <-ctx.Done():
+		return stats, ctx.Err()
+	}
+
+	next, err := c.statsSnapshot(ctx, simulationID)
+	if err != nil {
+		return stats, err
+	}
+	next.PreCPUStats = stats.CPUStats
+	next.PreRead = stats.Read
+	return next, nil
+}
+
+// statsResampleDelay is how far apart cpuStatsSample takes its two samples
+// when the daemon didn't provide the previous one.
+const statsResampleDelay = time.Second
+
+func (c *Client) statsSnapshot(ctx context.Context, simulationID string) (container.StatsResponse, error) {
+	var containerStats container.StatsResponse
+
 	stats, err := c.cli.ContainerStats(ctx, simulationID, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get container stats: %w", err)
+		return containerStats, fmt.Errorf("failed to get container stats: %w", err)
 	}
 	defer stats.Body.Close()
 
-	var containerStats container.StatsResponse
 	if err := json.NewDecoder(stats.Body).Decode(&containerStats); err != nil && err != io.EOF {
-		return nil, fmt.Errorf("failed to decode stats: %w", err)
+		return containerStats, fmt.Errorf("failed to decode stats: %w", err)
 	}
+	return containerStats, nil
+}
+
+// StreamSimulationMetrics follows the container's stats stream, which Docker
+// samples about once a second. Each sample's CPU usage is measured against
+// the previous one, unlike a one-off GetSimulationMetrics call. The metrics
+// channel is closed when the stream ends, because the simulation stopped or
+// ctx was cancelled; a failure to read the stream is then sent on the error
+// channel, which is closed afterwards.
+func (c *Client) StreamSimulationMetrics(ctx context.Context, simulationID string) (<-chan *models.Metrics, <-chan error) {
+	metricsCh := make(chan *models.Metrics)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+		defer close(metricsCh)
+
+		stats, err := c.cli.ContainerStats(ctx, simulationID, true)
+		if err != nil {
+			errCh <- fmt.Errorf("failed to get container stats: %w", err)
+			return
+		}
+		defer stats.Body.Close()
 
-	metrics := c.statsToMetrics(containerStats)
-	return metrics, nil
+		if err := c.decodeStatsStream(ctx, stats.Body, metricsCh); err != nil && ctx.Err() == nil {
+			errCh <- err
+		}
+	}()
+
+	return metricsCh, errCh
+}
+
+// decodeStatsStream sends a Metrics for every complete sample in a stats
+// stream. The first sample has no previous one to compare CPU usage with,
+// and a stopped container yields empty samples; both are skipped rather than
+// reported as 0% CPU.
+func (c *Client) decodeStatsStream(ctx context.Context, r io.Reader, out chan<- *models.Metrics) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var stats container.StatsResponse
+		if err := decoder.Decode(&stats); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to decode stats: %w", err)
+		}
+		if stats.Read.IsZero() || stats.PreRead.IsZero() {
+			continue
+		}
+
+		metrics := c.statsToMetrics(stats)
+		metrics.Timestamp = stats.Read
+		select {
+		case out <- metrics:
+		case <-ctx.Done():
+			return nil
+		}
+	}
 }
 
 func (c *Client) StopSimulation(ctx context.Context, simulationID string) error {
@@ -203,8 +839,129 @@ func (c *Client) StopSimulation(ctx context.Context, simulationID string) error
 	return nil
 }
 
-func (c *Client) RemoveSimulation(ctx context.Context, simulationID string, force bool) error {
-	if force {
+// RestartSimulation restarts the simulation's container, stopped or running.
+// A running engine gets SIGTERM and is killed if it hasn't exited within
+// timeout, as with StopSimulation.
+func (c *Client) RestartSimulation(ctx context.Context, simulationID string, timeout time.Duration) error {
+	seconds := int((timeout + time.Second - 1) / time.Second)
+	if err := c.cli.ContainerRestart(ctx, simulationID, container.StopOptions{Timeout: &seconds}); err != nil {
+		return fmt.Errorf("failed to restart container: %w", err)
+	}
+	return nil
+}
+
+// PauseSimulation freezes every process in the simulation's container. The
+// container keeps its memory and resumes where it left off when unpaused.
+func (c *Client) PauseSimulation(ctx context.Context, simulationID string) error {
+	if err := c.cli.ContainerPause(ctx, simulationID); err != nil {
+		return fmt.Errorf("failed to pause container: %w", err)
+	}
+	return nil
+}
+
+// UnpauseSimulation resumes a paused simulation.
+func (c *Client) UnpauseSimulation(ctx context.Context, simulationID string) error {
+	if err := c.cli.ContainerUnpause(ctx, simulationID); err != nil {
+		return fmt.Errorf("failed to unpause container: %w", err)
+	}
+	return nil
+}
+
+// WaitForExit blocks until the simulation's container stops running and
+// returns its exit code.
+func (c *Client) WaitForExit(ctx context.Context, simulationID string) (int64, error) {
+	statusCh, errCh := c.cli.ContainerWait(ctx, simulationID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		return 0, fmt.Errorf("failed to wait for container: %w", err)
+	case status := <-statusCh:
+		if status.Error != nil {
+			return status.StatusCode, fmt.Errorf("failed to wait for container: %s", status.Error.Message)
+		}
+		return status.StatusCode, nil
+	}
+}
+
+// readyPollInterval is how often WaitUntilReady inspects the container.
+const readyPollInterval = 500 * time.Millisecond
+
+// WaitUntilReady blocks until the simulation's container is running, or
+// healthy if its image defines a health check. It fails if the container
+// exits or turns unhealthy first.
+func (c *Client) WaitUntilReady(ctx context.Context, simulationID string) error {
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	for {
+		containerJSON, err := c.cli.ContainerInspect(ctx, simulationID)
+		if err != nil {
+			return fmt.Errorf("failed to inspect container: %w", err)
+		}
+		if ready, err := containerReady(containerJSON.State); ready || err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// containerReady reports whether a container is ready for the simulations
+// that depend on it, and fails once it never will be.
+func containerReady(state *types.ContainerState) (bool, error) {
+	switch {
+	case state == nil:
+		return false, nil
+	case state.Dead || state.Status == "exited":
+		return false, fmt.Errorf("container exited with code %d before it was ready", state.ExitCode)
+	case state.Health != nil && state.Health.Status == container.Unhealthy:
+		return false, fmt.Errorf("container is unhealthy")
+	case state.Health != nil && state.Health.Status != container.NoHealthcheck:
+		return state.Health.Status == container.Healthy, nil
+	default:
+		return state.Running && !state.Paused && !state.Restarting, nil
+	}
+}
+
+// KillSimulation sends SIGKILL immediately, for engines that ignore SIGTERM
+// and would otherwise hold StopSimulation for the full timeout.
+func (c *Client) KillSimulation(ctx context.Context, simulationID string) error {
+	if err := c.cli.ContainerKill(ctx, simulationID, "SIGKILL"); err != nil {
+		return fmt.Errorf("failed to kill container: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveOptions controls RemoveSimulation. Anonymous volumes are always
+// removed with the container, as with docker rm -v. Named volumes outlive the
+// container unless NamedVolumes is set, since other containers or later runs
+// may rely on them.
+type RemoveOptions struct {
+	Force        bool
+	NamedVolumes bool
+}
+
+func (c *Client) RemoveSimulation(ctx context.Context, simulationID string, opts RemoveOptions) error {
+	_, err := c.RemoveSimulationVolumes(ctx, simulationID, opts)
+	return err
+}
+
+// RemoveSimulationVolumes is RemoveSimulation, also returning the named
+// volumes it removed (none unless opts.NamedVolumes is set).
+func (c *Client) RemoveSimulationVolumes(ctx context.Context, simulationID string, opts RemoveOptions) ([]string, error) {
+	var volumes []string
+	if opts.NamedVolumes {
+		inspect, err := c.cli.ContainerInspect(ctx, simulationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect container: %w", err)
+		}
+		volumes = volumeNames(inspect.Mounts)
+	}
+
+	if opts.Force {
 		timeout := 10
 		stopOptions := container.StopOptions{
 			Timeout: &timeout,
@@ -213,54 +970,187 @@ func (c *Client) RemoveSimulation(ctx context.Context, simulationID string, forc
 	}
 
 	removeOptions := container.RemoveOptions{
-		Force:         force,
+		Force:         opts.Force,
 		RemoveVolumes: true,
 	}
 
 	if err := c.cli.ContainerRemove(ctx, simulationID, removeOptions); err != nil {
-		return fmt.Errorf("failed to remove container: %w", err)
+		return nil, fmt.Errorf("failed to remove container: %w", err)
 	}
 
-	return nil
+	// Anonymous volumes are already gone with the container.
+	removed := []string{}
+	for _, name := range volumes {
+		err := c.cli.VolumeRemove(ctx, name, false)
+		if cerrdefs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove volume %s: %w", name, err)
+		}
+		removed = append(removed, name)
+	}
+
+	return removed, nil
+}
+
+// volumeNames returns the volumes among a container's mounts, skipping bind
+// mounts and tmpfs.
+func volumeNames(mounts []container.MountPoint) []string {
+	var names []string
+	for _, m := range mounts {
+		if m.Type == mount.TypeVolume && m.Name != "" {
+			names = append(names, m.Name)
+		}
+	}
+	return names
 }
 
-func (c *Client) GetSimulationLogs(ctx context.Context, simulationID string, tail int) (string, error) {
+// LogOptions bounds which log lines are returned. Since accepts anything the
+// Docker API does: an RFC3339 timestamp, a Unix timestamp, or a Go duration
+// relative to now. When both are set Docker applies them together, returning
+// at most Tail lines from those written after Since. A zero Tail means no
+// line limit.
+type LogOptions struct {
+	Tail  int
+	Since string
+}
+
+func (o LogOptions) tail() string {
+	if o.Tail <= 0 {
+		return "all"
+	}
+	return strconv.Itoa(o.Tail)
+}
+
+func (c *Client) GetSimulationLogs(ctx context.Context, simulationID string, opts LogOptions) (string, error) {
 	options := container.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Timestamps: true,
-		Tail:       fmt.Sprintf("%d", tail),
+		Tail:       opts.tail(),
+		Since:      opts.Since,
 	}
 
+	tty, err := c.hasTTY(ctx, simulationID)
+	if err != nil {
+		return "", err
+	}
 	reader, err := c.cli.ContainerLogs(ctx, simulationID, options)
 	if err != nil {
 		return "", fmt.Errorf("failed to get container logs: %w", err)
 	}
 	defer reader.Close()
 
-	logs, err := io.ReadAll(reader)
-	if err != nil {
+	var logs bytes.Buffer
+	if err := copyOutput(reader, &logs, &logs, tty); err != nil {
 		return "", fmt.Errorf("failed to read logs: %w", err)
 	}
 
-	return string(logs), nil
+	return logs.String(), nil
 }
 
-func (c *Client) GetSimulationLogsStream(ctx context.Context, simulationID string, tail int) (io.ReadCloser, error) {
+// GetSimulationLogsStream follows a simulation's logs until the container
+// exits or ctx is cancelled. The returned reader yields plain log lines, with
+// Docker's stdout/stderr framing removed.
+func (c *Client) GetSimulationLogsStream(ctx context.Context, simulationID string, opts LogOptions) (io.ReadCloser, error) {
 	options := container.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Timestamps: true,
 		Follow:     true,
-		Tail:       fmt.Sprintf("%d", tail),
+		Tail:       opts.tail(),
+		Since:      opts.Since,
 	}
 
+	tty, err := c.hasTTY(ctx, simulationID)
+	if err != nil {
+		return nil, err
+	}
 	reader, err := c.cli.ContainerLogs(ctx, simulationID, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get container logs: %w", err)
 	}
 
-	return reader, nil
+	return newDemuxReader(reader, tty), nil
+}
+
+// demuxReader is a log stream with Docker's frame headers stripped, stdout
+// and stderr interleaved in the order they arrive.
+type demuxReader struct {
+	*io.PipeReader
+	src io.ReadCloser
+}
+
+// newDemuxReader demultiplexes src in the background, or passes it through
+// if the container has a TTY. Reads return io.EOF once src ends, or its
+// error if it fails. Closing the reader closes src.
+func newDemuxReader(src io.ReadCloser, tty bool) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(copyOutput(src, pw, pw, tty))
+	}()
+	return &demuxReader{PipeReader: pr, src: src}
+}
+
+func (r *demuxReader) Close() error {
+	r.PipeReader.Close()
+	return r.src.Close()
+}
+
+// StreamSimulationOutput follows a simulation's output from the start until
+// the container exits, writing stdout and stderr to separate writers. Output
+// is passed through as the engine wrote it, without timestamps, so structured
+// data on stdout stays machine-readable.
+func (c *Client) StreamSimulationOutput(ctx context.Context, simulationID string, stdout, stderr io.Writer) error {
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	}
+
+	tty, err := c.hasTTY(ctx, simulationID)
+	if err != nil {
+		return err
+	}
+	reader, err := c.cli.ContainerLogs(ctx, simulationID, options)
+	if err != nil {
+		return fmt.Errorf("failed to get container logs: %w", err)
+	}
+	defer reader.Close()
+
+	if err := copyOutput(reader, stdout, stderr, tty); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to stream output: %w", err)
+	}
+	return nil
+}
+
+// demuxOutput splits Docker's multiplexed log stream, used for containers
+// without a TTY, into its stdout and stderr frames.
+func demuxOutput(r io.Reader, stdout, stderr io.Writer) error {
+	_, err := stdcopy.StdCopy(stdout, stderr, r)
+	return err
+}
+
+// copyOutput copies a container's output stream to stdout and stderr. With
+// a TTY, Docker sends the terminal's raw output, which has no frames to
+// split and all goes to stdout.
+func copyOutput(r io.Reader, stdout, stderr io.Writer, tty bool) error {
+	if tty {
+		_, err := io.Copy(stdout, r)
+		return err
+	}
+	return demuxOutput(r, stdout, stderr)
+}
+
+// hasTTY reports whether the simulation's container was created with a TTY,
+// which decides how its log stream is encoded.
+func (c *Client) hasTTY(ctx context.Context, simulationID string) (bool, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, simulationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	return inspect.Config != nil && inspect.Config.Tty, nil
 }
 
 func (c *Client) mapToEnvSlice(envMap map[string]string) []string {
@@ -274,10 +1164,11 @@ func (c *Client) mapToEnvSlice(envMap map[string]string) []string {
 func (c *Client) containerToSimulation(container types.ContainerJSON) *models.Simulation {
 	createdAt, _ := time.Parse(time.RFC3339, container.Created)
 	simulation := &models.Simulation{
-		ID:          container.ID[:12],
-		ContainerID: container.ID,
-		Status:      c.containerStateToStatus(container.State),
-		CreatedAt:   createdAt,
+		ID:            container.ID[:12],
+		ContainerID:   container.ID,
+		ContainerName: strings.TrimPrefix(container.Name, "/"),
+		Status:        c.containerStateToStatus(container.State),
+		CreatedAt:     createdAt,
 	}
 
 	if container.State.StartedAt != "" {
@@ -292,8 +1183,53 @@ func (c *Client) containerToSimulation(container types.ContainerJSON) *models.Si
 		}
 	}
 
-	if name, ok := container.Config.Labels[fmt.Sprintf("%s.name", AutoboxLabelPrefix)]; ok {
+	if !container.State.Running && container.State.Status == "exited" {
+		exitCode := container.State.ExitCode
+		simulation.ExitCode = &exitCode
+	}
+	simulation.OOMKilled = container.State.OOMKilled
+	simulation.RestartCount = container.RestartCount
+
+	if name, ok := container.Config.Labels[c.label("name")]; ok {
 		simulation.Name = name
+	} else {
+		simulation.Name = externalName(container.Name)
+	}
+
+	// Older CLI versions didn't store the config label, so fall back to
+	// what the container itself records.
+	if config, ok := c.parseConfigLabel(container.Config.Labels); ok {
+		simulation.Config = config
+	} else {
+		simulation.Config.Name = simulation.Name
+		simulation.Config.ConfigPath = container.Config.Labels[c.label("config_path")]
+		if container.HostConfig != nil {
+			simulation.Config.Volumes = container.HostConfig.Binds
+			simulation.Config.NetworkMode = string(container.HostConfig.NetworkMode)
+		}
+	}
+
+	simulation.Config.Image = container.Config.Image
+	simulation.Config.ImageDigest = container.Config.Labels[c.label("image_digest")]
+	simulation.Config.EngineVersion = container.Config.Labels[c.label("engine_version")]
+	simulation.Config.Webhook = container.Config.Labels[c.label("webhook")]
+	simulation.Config.Entrypoint = container.Config.Labels[c.label("entrypoint")]
+	simulation.Config.Metadata = c.parseMetadataLabel(container.Config.Labels)
+	if env := c.parseEnvironment(container.Config.Labels, container.Config.Env); env != nil {
+		simulation.Config.Environment = env
+	}
+
+	if container.HostConfig != nil {
+		simulation.Config.Privileged = container.HostConfig.Privileged
+		simulation.Config.CapAdd = container.HostConfig.CapAdd
+		simulation.Config.CapDrop = container.HostConfig.CapDrop
+	}
+
+	if cmdJSON, ok := container.Config.Labels[c.label("cmd")]; ok {
+		_ = json.Unmarshal([]byte(cmdJSON), &simulation.Command)
+	}
+	if simulation.Command == nil {
+		simulation.Command = container.Config.Cmd
 	}
 
 	return simulation
@@ -303,25 +1239,134 @@ func (c *Client) containerListItemToSimulation(container types.Container) *model
 	simulation := &models.Simulation{
 		ID:          container.ID[:12],
 		ContainerID: container.ID,
-		Status:      c.containerStateStringToStatus(container.State),
+		Status:      c.containerStateStringToStatus(container.State, container.Status),
 		CreatedAt:   time.Unix(container.Created, 0),
 	}
+	if len(container.Names) > 0 {
+		simulation.ContainerName = strings.TrimPrefix(container.Names[0], "/")
+	}
+	if code, ok := listExitCode(container.Status); ok && container.State == "exited" {
+		simulation.ExitCode = &code
+	}
 
-	if name, ok := container.Labels[fmt.Sprintf("%s.name", AutoboxLabelPrefix)]; ok {
+	if name, ok := container.Labels[c.label("name")]; ok {
 		simulation.Name = name
+	} else if len(container.Names) > 0 {
+		simulation.Name = externalName(container.Names[0])
 	}
 
+	if config, ok := c.parseConfigLabel(container.Labels); ok {
+		simulation.Config = config
+	}
+	simulation.Config.Metadata = c.parseMetadataLabel(container.Labels)
+
 	return simulation
 }
 
+// externalName labels a container that wasn't launched by this CLI, and so
+// has no name label, with its Docker-assigned name.
+func externalName(dockerName string) string {
+	if dockerName == "" {
+		return ""
+	}
+	return ExternalNamePrefix + strings.TrimPrefix(dockerName, "/")
+}
+
+// parseMetadataLabel decodes the --meta blob. Containers labelled by hand or
+// by older CLI versions may carry invalid JSON, which is treated as absent.
+func (c *Client) parseMetadataLabel(labels map[string]string) map[string]interface{} {
+	raw, ok := labels[c.label("metadata")]
+	if !ok {
+		return nil
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+		return nil
+	}
+	return metadata
+}
+
+// addConfigLabels stores config in labels, so list and status can describe
+// the simulation later. Labels are readable by anyone who can list
+// containers, so the config label leaves out the environment values and the
+// webhook URL, either of which may carry a secret. Only the environment's
+// keys are kept, to find the values in the container's own Env on inspect.
+func (c *Client) addConfigLabels(labels map[string]string, config models.SimulationConfig) error {
+	labelConfig := config
+	labelConfig.Environment = nil
+	labelConfig.Webhook = ""
+	configJSON, err := json.Marshal(labelConfig)
+	if err != nil {
+		return fmt.Errorf("failed to encode simulation config: %w", err)
+	}
+	labels[c.label("config")] = string(configJSON)
+
+	if len(config.Environment) > 0 {
+		keys := make([]string, 0, len(config.Environment))
+		for k := range config.Environment {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		keysJSON, err := json.Marshal(keys)
+		if err != nil {
+			return fmt.Errorf("failed to encode environment keys: %w", err)
+		}
+		labels[c.label("env_keys")] = string(keysJSON)
+	}
+	return nil
+}
+
+// parseEnvironment returns the variables the simulation was launched with,
+// rather than every variable the image sets: the values in env of the keys
+// listed in the env_keys label. It returns nil when there is no such label.
+func (c *Client) parseEnvironment(labels map[string]string, env []string) map[string]string {
+	raw, ok := labels[c.label("env_keys")]
+	if !ok {
+		return nil
+	}
+	var keys []string
+	if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+		return nil
+	}
+
+	values := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		values[k] = v
+	}
+	environment := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if v, ok := values[k]; ok {
+			environment[k] = v
+		}
+	}
+	return environment
+}
+
+// parseConfigLabel decodes the SimulationConfig stored at launch. Like the
+// metadata label, invalid JSON is treated as absent.
+func (c *Client) parseConfigLabel(labels map[string]string) (models.SimulationConfig, bool) {
+	var config models.SimulationConfig
+	raw, ok := labels[c.label("config")]
+	if !ok {
+		return config, false
+	}
+	if err := json.Unmarshal([]byte(raw), &config); err != nil {
+		return models.SimulationConfig{}, false
+	}
+	return config, true
+}
+
 func (c *Client) containerStateToStatus(state *types.ContainerState) models.SimulationStatus {
 	switch {
+	// Docker reports a paused container as running too.
+	case state.Paused:
+		return models.StatusPaused
 	case state.Running:
 		return models.StatusRunning
 	case state.Dead:
 		return models.StatusFailed
-	case state.Paused:
-		return models.StatusStopped
 	case state.Restarting:
 		return models.StatusRunning
 	case state.Status == "exited" && state.ExitCode == 0:
@@ -379,8 +1424,10 @@ func (c *Client) getHTTPStatus(ctx context.Context, container types.ContainerJSO
 		return models.StatusFailed, nil
 	case "running", "active", "in_progress":
 		return models.StatusRunning, nil
-	case "stopped", "paused":
+	case "stopped":
 		return models.StatusStopped, nil
+	case "paused":
+		return models.StatusPaused, nil
 	case "pending", "waiting", "queued":
 		return models.StatusPending, nil
 	default:
@@ -388,49 +1435,111 @@ func (c *Client) getHTTPStatus(ctx context.Context, container types.ContainerJSO
 	}
 }
 
-func (c *Client) containerStateStringToStatus(state string) models.SimulationStatus {
+// containerStateStringToStatus maps the state of a container list entry to a
+// status the same way containerStateToStatus does for an inspect. The list
+// API has no exit code field, so it is read from the human-readable status,
+// e.g. "Exited (1) 5 minutes ago"; an unparseable code counts as failed.
+func (c *Client) containerStateStringToStatus(state, status string) models.SimulationStatus {
 	switch strings.ToLower(state) {
-	case "running":
+	case "running", "restarting":
 		return models.StatusRunning
 	case "exited":
-		return models.StatusCompleted
+		if code, ok := listExitCode(status); ok && code == 0 {
+			return models.StatusCompleted
+		}
+		return models.StatusFailed
 	case "dead":
 		return models.StatusFailed
 	case "paused":
-		return models.StatusStopped
+		return models.StatusPaused
 	default:
 		return models.StatusPending
 	}
 }
 
-func (c *Client) statsToMetrics(stats container.StatsResponse) *models.Metrics {
-	var cpuPercent float64
-	if stats.PreCPUStats.CPUUsage.TotalUsage > 0 {
-		cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage - stats.PreCPUStats.CPUUsage.TotalUsage)
-		systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
-		if systemDelta > 0 && cpuDelta > 0 {
-			cpuPercent = (cpuDelta / systemDelta) * float64(len(stats.CPUStats.CPUUsage.PercpuUsage)) * 100.0
-		}
+// listExitCode extracts the exit code from a container list status string
+// like "Exited (137) 2 hours ago".
+func listExitCode(status string) (int, bool) {
+	rest, ok := strings.CutPrefix(status, "Exited (")
+	if !ok {
+		return 0, false
+	}
+	code, _, ok := strings.Cut(rest, ")")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// cpuPercent is the CPU usage between the two samples in a stats response,
+// where 100 is one fully used core. The core count comes from OnlineCPUs;
+// PercpuUsage is only a fallback for old daemons, as it is deprecated and
+// empty on cgroup v2.
+func cpuPercent(stats container.StatsResponse) float64 {
+	if stats.PreCPUStats.CPUUsage.TotalUsage == 0 {
+		return 0
+	}
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	if systemDelta <= 0 || cpuDelta <= 0 {
+		return 0
 	}
 
+	cpus := float64(stats.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpus == 0 {
+		cpus = 1
+	}
+	return (cpuDelta / systemDelta) * cpus * 100.0
+}
+
+func (c *Client) statsToMetrics(stats container.StatsResponse) *models.Metrics {
 	var memoryPercent float64
 	if stats.MemoryStats.Limit > 0 {
 		memoryPercent = (float64(stats.MemoryStats.Usage) / float64(stats.MemoryStats.Limit)) * 100.0
 	}
 
 	return &models.Metrics{
-		CPUUsage:    cpuPercent,
+		CPUUsage:    cpuPercent(stats),
 		MemoryUsage: memoryPercent,
-		NetworkIO: models.NetworkStats{
-			BytesReceived:      stats.Networks["eth0"].RxBytes,
-			BytesTransmitted:   stats.Networks["eth0"].TxBytes,
-			PacketsReceived:    stats.Networks["eth0"].RxPackets,
-			PacketsTransmitted: stats.Networks["eth0"].TxPackets,
-		},
-		DiskIO: models.DiskStats{
-			BytesRead:    stats.BlkioStats.IoServiceBytesRecursive[0].Value,
-			BytesWritten: stats.BlkioStats.IoServiceBytesRecursive[1].Value,
-		},
-		Timestamp: time.Now(),
+		NetworkIO:   networkStats(stats.Networks),
+		DiskIO:      diskStats(stats.BlkioStats),
+		Timestamp:   time.Now(),
+	}
+}
+
+// networkStats sums traffic over every interface, so containers on a custom
+// network or with more than one interface aren't reported as idle.
+func networkStats(networks map[string]container.NetworkStats) models.NetworkStats {
+	var network models.NetworkStats
+	for _, iface := range networks {
+		network.BytesReceived += iface.RxBytes
+		network.BytesTransmitted += iface.TxBytes
+		network.PacketsReceived += iface.RxPackets
+		network.PacketsTransmitted += iface.TxPackets
+	}
+	return network
+}
+
+// diskStats sums the read and write entries of the blkio stats. Docker
+// reports one entry per device and operation, and the list may be empty (no
+// I/O yet, or a storage driver that doesn't report it), so nothing is assumed
+// about its length or order. cgroup v2 reports the ops in lowercase.
+func diskStats(blkio container.BlkioStats) models.DiskStats {
+	var disk models.DiskStats
+	for _, entry := range blkio.IoServiceBytesRecursive {
+		switch {
+		case strings.EqualFold(entry.Op, "read"):
+			disk.BytesRead += entry.Value
+		case strings.EqualFold(entry.Op, "write"):
+			disk.BytesWritten += entry.Value
+		}
 	}
+	return disk
 }
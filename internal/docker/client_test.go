@@ -0,0 +1,854 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func TestLabelPrefix(t *testing.T) {
+	c := &Client{labelPrefix: "org.team-a"}
+
+	if got := c.label("name"); got != "org.team-a.name" {
+		t.Errorf("label(name) = %q, want org.team-a.name", got)
+	}
+
+	sim := c.containerListItemToSimulation(types.Container{
+		ID:    "abc123def456789",
+		State: "running",
+		Labels: map[string]string{
+			"org.team-a.name":     "mine",
+			"org.team-a.metadata": `{"experiment": 1}`,
+			"com.autobox.name":    "theirs",
+		},
+	})
+
+	if sim.Name != "mine" {
+		t.Errorf("Name = %q, want mine", sim.Name)
+	}
+	if sim.Config.Metadata["experiment"] != float64(1) {
+		t.Errorf("Metadata = %v, want experiment=1", sim.Config.Metadata)
+	}
+}
+
+func TestSocketPath(t *testing.T) {
+	if got := socketPath("unix:///var/run/docker.sock"); got != "/var/run/docker.sock" {
+		t.Errorf("socketPath(unix) = %q, want /var/run/docker.sock", got)
+	}
+	if got := socketPath("tcp://localhost:2375"); got != "tcp://localhost:2375" {
+		t.Errorf("socketPath(tcp) = %q, want it unchanged", got)
+	}
+}
+
+func TestEngineCommand(t *testing.T) {
+	config := models.SimulationConfig{
+		ConfigPath:  "/app/config/simulations/a.json",
+		MetricsPath: "/app/config/metrics/a.json",
+		ServerPath:  "/app/config/server.json",
+		EngineArgs:  []string{"--seed", "42"},
+	}
+
+	want := []string{
+		"--config", config.ConfigPath,
+		"--metrics", config.MetricsPath,
+		"--server", config.ServerPath,
+		"--seed", "42",
+	}
+	if got := engineCommand(config); !reflect.DeepEqual(got, want) {
+		t.Errorf("engineCommand = %v, want %v", got, want)
+	}
+
+	config.Entrypoint = "/bin/sh"
+	if got := engineCommand(config); !reflect.DeepEqual(got, []string{"--seed", "42"}) {
+		t.Errorf("engineCommand with entrypoint = %v, want only the engine args", got)
+	}
+
+	config.EngineArgs = nil
+	if got := engineCommand(config); len(got) != 0 {
+		t.Errorf("engineCommand with entrypoint and no args = %v, want empty", got)
+	}
+}
+
+func TestExternalSimulationName(t *testing.T) {
+	c := &Client{labelPrefix: AutoboxLabelPrefix}
+
+	sim := c.containerListItemToSimulation(types.Container{
+		ID:     "abc123def456789",
+		State:  "running",
+		Names:  []string{"/quirky_turing"},
+		Labels: map[string]string{"com.autobox.simulation": "true"},
+	})
+	if sim.Name != "(external) quirky_turing" {
+		t.Errorf("Name = %q, want (external) quirky_turing", sim.Name)
+	}
+
+	labelled := c.containerListItemToSimulation(types.Container{
+		ID:     "abc123def456789",
+		State:  "running",
+		Names:  []string{"/quirky_turing"},
+		Labels: map[string]string{"com.autobox.name": "gift_choice"},
+	})
+	if labelled.Name != "gift_choice" {
+		t.Errorf("Name = %q, want the name label", labelled.Name)
+	}
+}
+
+func TestVolumeNames(t *testing.T) {
+	mounts := []container.MountPoint{
+		{Type: mount.TypeBind, Source: "/home/me/.autobox/config", Destination: "/app/config"},
+		{Type: mount.TypeVolume, Name: "results", Destination: "/app/logs"},
+		{Type: mount.TypeTmpfs, Destination: "/tmp"},
+		{Type: mount.TypeVolume, Name: "0f3c9d2e", Destination: "/data"},
+	}
+
+	want := []string{"results", "0f3c9d2e"}
+	if got := volumeNames(mounts); !reflect.DeepEqual(got, want) {
+		t.Errorf("volumeNames = %v, want %v", got, want)
+	}
+}
+
+func TestAPIVersionWarning(t *testing.T) {
+	tests := []struct {
+		negotiated, minimum string
+		warn                bool
+	}{
+		{"1.41", "1.41", false},
+		{"1.47", "1.41", false},
+		{"1.40", "1.41", true},
+		{"1.9", "1.41", true},
+		{"1.40", "", false},
+	}
+
+	for _, tt := range tests {
+		got := APIVersionWarning(tt.negotiated, tt.minimum)
+		if (got != "") != tt.warn {
+			t.Errorf("APIVersionWarning(%q, %q) = %q, want warning %v", tt.negotiated, tt.minimum, got, tt.warn)
+		}
+	}
+}
+
+func TestDemuxOutput(t *testing.T) {
+	var stream bytes.Buffer
+	stdoutFrames := stdcopy.NewStdWriter(&stream, stdcopy.Stdout)
+	stderrFrames := stdcopy.NewStdWriter(&stream, stdcopy.Stderr)
+
+	stdoutFrames.Write([]byte(`{"step": 1}` + "\n"))
+	stderrFrames.Write([]byte("starting agents\n"))
+	stdoutFrames.Write([]byte(`{"step": 2}` + "\n"))
+
+	var stdout, stderr bytes.Buffer
+	if err := demuxOutput(&stream, &stdout, &stderr); err != nil {
+		t.Fatalf("demuxOutput: %v", err)
+	}
+
+	if got, want := stdout.String(), "{\"step\": 1}\n{\"step\": 2}\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got, want := stderr.String(), "starting agents\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
+
+func TestCopyOutputTTY(t *testing.T) {
+	// A TTY container's stream is raw terminal output, which StdCopy would
+	// reject as a bad frame header.
+	raw := "step 1\r\nstep 2\r\n"
+	var stdout, stderr bytes.Buffer
+	if err := copyOutput(strings.NewReader(raw), &stdout, &stderr, true); err != nil {
+		t.Fatalf("copyOutput: %v", err)
+	}
+	if stdout.String() != raw || stderr.Len() != 0 {
+		t.Errorf("stdout, stderr = %q, %q; want the raw output on stdout", stdout.String(), stderr.String())
+	}
+
+	reader := newDemuxReader(io.NopCloser(strings.NewReader(raw)), true)
+	defer reader.Close()
+	got, err := io.ReadAll(reader)
+	if err != nil || string(got) != raw {
+		t.Errorf("TTY stream = %q, %v; want it passed through", got, err)
+	}
+}
+
+func TestStatsToMetricsDiskIO(t *testing.T) {
+	tests := []struct {
+		name                  string
+		entries               []container.BlkioStatEntry
+		wantRead, wantWritten uint64
+	}{
+		{name: "empty", entries: nil},
+		{name: "single", entries: []container.BlkioStatEntry{{Op: "Read", Value: 4096}}, wantRead: 4096},
+		{
+			name: "cgroup v1",
+			entries: []container.BlkioStatEntry{
+				{Major: 8, Op: "Read", Value: 100},
+				{Major: 8, Op: "Write", Value: 200},
+				{Major: 8, Op: "Sync", Value: 300},
+				{Major: 8, Op: "Total", Value: 300},
+				{Major: 253, Op: "Write", Value: 50},
+			},
+			wantRead: 100, wantWritten: 250,
+		},
+		{
+			name:     "cgroup v2",
+			entries:  []container.BlkioStatEntry{{Op: "write", Value: 8}, {Op: "read", Value: 16}},
+			wantRead: 16, wantWritten: 8,
+		},
+	}
+
+	c := &Client{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stats container.StatsResponse
+			stats.BlkioStats.IoServiceBytesRecursive = tt.entries
+
+			got := c.statsToMetrics(stats).DiskIO
+			if got.BytesRead != tt.wantRead || got.BytesWritten != tt.wantWritten {
+				t.Errorf("DiskIO = %+v, want read %d, written %d", got, tt.wantRead, tt.wantWritten)
+			}
+		})
+	}
+}
+
+func TestStatsToMetricsNetworkIO(t *testing.T) {
+	var stats container.StatsResponse
+	stats.Networks = map[string]container.NetworkStats{
+		"eth0": {RxBytes: 1000, TxBytes: 500, RxPackets: 10, TxPackets: 5},
+		"eth1": {RxBytes: 24, TxBytes: 12, RxPackets: 2, TxPackets: 1},
+	}
+
+	got := (&Client{}).statsToMetrics(stats).NetworkIO
+	want := models.NetworkStats{BytesReceived: 1024, BytesTransmitted: 512, PacketsReceived: 12, PacketsTransmitted: 6}
+	if got != want {
+		t.Errorf("NetworkIO = %+v, want %+v", got, want)
+	}
+}
+
+func TestDemuxReader(t *testing.T) {
+	var stream bytes.Buffer
+	stdcopy.NewStdWriter(&stream, stdcopy.Stdout).Write([]byte("2025-01-01T00:00:00Z step 1\n"))
+	stdcopy.NewStdWriter(&stream, stdcopy.Stderr).Write([]byte("2025-01-01T00:00:01Z warning\n"))
+	stdcopy.NewStdWriter(&stream, stdcopy.Stdout).Write([]byte("2025-01-01T00:00:02Z step 2\n"))
+
+	reader := newDemuxReader(io.NopCloser(&stream), false)
+	defer reader.Close()
+
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	want := "2025-01-01T00:00:00Z step 1\n2025-01-01T00:00:01Z warning\n2025-01-01T00:00:02Z step 2\n"
+	if string(got) != want {
+		t.Errorf("demuxed stream = %q, want %q", got, want)
+	}
+}
+
+func TestParseMemoryLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "512m", want: 512 << 20},
+		{value: "512MB", want: 512 << 20},
+		{value: "2g", want: 2 << 30},
+		{value: "1.5g", want: 3 << 29},
+		{value: "8192k", want: 8 << 20},
+		{value: "104857600", want: 100 << 20},
+		{value: "", wantErr: true},
+		{value: "lots", wantErr: true},
+		{value: "2t", wantErr: true},
+		{value: "-1g", wantErr: true},
+		{value: "1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMemoryLimit(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMemoryLimit(%q) = %d, %v; want %d, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestResourceLimits(t *testing.T) {
+	resources, err := resourceLimits(models.SimulationConfig{CPULimit: 1.5, MemoryLimit: "2g"})
+	if err != nil {
+		t.Fatalf("resourceLimits: %v", err)
+	}
+	if resources.NanoCPUs != 1_500_000_000 || resources.Memory != 2<<30 {
+		t.Errorf("resourceLimits = NanoCPUs %d, Memory %d; want 1500000000, %d", resources.NanoCPUs, resources.Memory, int64(2<<30))
+	}
+
+	if resources, err := resourceLimits(models.SimulationConfig{}); err != nil || resources.NanoCPUs != 0 || resources.Memory != 0 {
+		t.Errorf("resourceLimits(unset) = %+v, %v; want no limits", resources, err)
+	}
+	if _, err := resourceLimits(models.SimulationConfig{CPULimit: -1}); err == nil {
+		t.Error("resourceLimits(CPULimit -1) = nil error, want an error")
+	}
+}
+
+func TestConfigLabelRoundTrip(t *testing.T) {
+	c := &Client{labelPrefix: AutoboxLabelPrefix}
+	config := models.SimulationConfig{
+		Name:        "gift_choice",
+		ConfigPath:  "/app/config/simulations/gift_choice.json",
+		MetricsPath: "/app/config/metrics/gift_choice.json",
+		Image:       "autobox-engine:latest",
+		Environment: map[string]string{"LOG_LEVEL": "debug", "API_KEY": "sk-secret"},
+		Volumes:     []string{"/home/me/.autobox/config:/app/config"},
+		NetworkMode: "bridge",
+		EngineArgs:  []string{"--seed", "42"},
+		Metadata:    map[string]interface{}{"experiment": float64(42)},
+		CPULimit:    1.5,
+		MemoryLimit: "2g",
+	}
+	labels := map[string]string{
+		"com.autobox.name":     "gift_choice",
+		"com.autobox.metadata": `{"experiment": 42}`,
+	}
+	if err := c.addConfigLabels(labels, config); err != nil {
+		t.Fatalf("addConfigLabels() error = %v", err)
+	}
+	for key, value := range labels {
+		if strings.Contains(value, "sk-secret") {
+			t.Errorf("label %s = %s, want no environment values", key, value)
+		}
+	}
+
+	sim := c.containerToSimulation(types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:         "abc123def456789",
+			State:      &types.ContainerState{Status: "running", Running: true},
+			HostConfig: &container.HostConfig{},
+		},
+		Config: &container.Config{
+			Image:  "autobox-engine:latest",
+			Labels: labels,
+			Env:    []string{"PATH=/usr/local/bin:/usr/bin", "LOG_LEVEL=debug", "API_KEY=sk-secret"},
+		},
+	})
+	if !reflect.DeepEqual(sim.Config, config) {
+		t.Errorf("Config = %+v, want %+v", sim.Config, config)
+	}
+
+	legacy := c.containerToSimulation(types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:         "abc123def456789",
+			State:      &types.ContainerState{Status: "running", Running: true},
+			HostConfig: &container.HostConfig{Binds: []string{"/data:/app/logs"}},
+		},
+		Config: &container.Config{
+			Image:  "autobox-engine:v1",
+			Labels: map[string]string{"com.autobox.name": "old", "com.autobox.config_path": "/app/config/simulation.json"},
+		},
+	})
+	if legacy.Config.Image != "autobox-engine:v1" || legacy.Config.ConfigPath != "/app/config/simulation.json" ||
+		!reflect.DeepEqual(legacy.Config.Volumes, []string{"/data:/app/logs"}) {
+		t.Errorf("Config without config label = %+v, want image, config path and volumes from the container", legacy.Config)
+	}
+}
+
+func TestContainerToSimulationExitState(t *testing.T) {
+	c := &Client{labelPrefix: AutoboxLabelPrefix}
+	inspect := func(state *types.ContainerState) *models.Simulation {
+		return c.containerToSimulation(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{ID: "abc123def456789", State: state},
+			Config:            &container.Config{Labels: map[string]string{"com.autobox.name": "oom"}},
+		})
+	}
+
+	oom := inspect(&types.ContainerState{Status: "exited", ExitCode: 137, OOMKilled: true})
+	if oom.Status != models.StatusFailed || oom.ExitCode == nil || *oom.ExitCode != 137 || !oom.OOMKilled {
+		t.Errorf("OOM-killed container = status %s, exit code %v, OOMKilled %v; want failed, 137, true",
+			oom.Status, oom.ExitCode, oom.OOMKilled)
+	}
+
+	done := inspect(&types.ContainerState{Status: "exited", ExitCode: 0})
+	if done.Status != models.StatusCompleted || done.ExitCode == nil || *done.ExitCode != 0 || done.OOMKilled {
+		t.Errorf("completed container = status %s, exit code %v, OOMKilled %v; want completed, 0, false",
+			done.Status, done.ExitCode, done.OOMKilled)
+	}
+
+	running := inspect(&types.ContainerState{Status: "running", Running: true})
+	if running.ExitCode != nil {
+		t.Errorf("running container has exit code %d, want none", *running.ExitCode)
+	}
+}
+
+func TestDigestFromInspect(t *testing.T) {
+	const sum = "sha256:" + "4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+	tests := []struct {
+		name     string
+		ref      string
+		inspect  image.InspectResponse
+		expected string
+	}{
+		{
+			name:     "Matching repository",
+			ref:      "ghcr.io/autobox/engine:1.2",
+			inspect:  image.InspectResponse{ID: "sha256:local", RepoDigests: []string{"docker.io/other@" + sum, "ghcr.io/autobox/engine@" + sum}},
+			expected: "ghcr.io/autobox/engine@" + sum,
+		},
+		{
+			name:     "Registry port is not a tag",
+			ref:      "localhost:5000/engine",
+			inspect:  image.InspectResponse{RepoDigests: []string{"localhost:5000/engine@" + sum}},
+			expected: "localhost:5000/engine@" + sum,
+		},
+		{
+			name:     "Other repository only",
+			ref:      "autobox-engine:latest",
+			inspect:  image.InspectResponse{RepoDigests: []string{"mirror.local/autobox-engine@" + sum}},
+			expected: "mirror.local/autobox-engine@" + sum,
+		},
+		{
+			name:     "Never pushed",
+			ref:      "autobox-engine:dev",
+			inspect:  image.InspectResponse{ID: sum},
+			expected: sum,
+		},
+		{
+			name:     "Already pinned",
+			ref:      "autobox-engine@" + sum,
+			inspect:  image.InspectResponse{ID: "sha256:local"},
+			expected: "autobox-engine@" + sum,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := digestFromInspect(tt.ref, tt.inspect); got != tt.expected {
+				t.Errorf("digestFromInspect(%q) = %q, want %q", tt.ref, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewSimulationName(t *testing.T) {
+	config := models.SimulationConfig{
+		Name:       "Gift choice",
+		ConfigPath: "/app/config/simulations/gift_choice.json",
+	}
+	sim := newSimulation("abc123def456789", config, nil, time.Now())
+	if sim.Name != "Gift choice" {
+		t.Errorf("Name = %q, want the configured name %q", sim.Name, config.Name)
+	}
+	if sim.ID != "abc123def456" || sim.ContainerID != "abc123def456789" {
+		t.Errorf("ID = %q, ContainerID = %q", sim.ID, sim.ContainerID)
+	}
+}
+
+func TestListAndInspectStatusAgree(t *testing.T) {
+	c := &Client{labelPrefix: AutoboxLabelPrefix}
+	tests := []struct {
+		exitCode int
+		status   string
+		want     models.SimulationStatus
+	}{
+		{0, "Exited (0) 5 minutes ago", models.StatusCompleted},
+		{1, "Exited (1) 5 minutes ago", models.StatusFailed},
+		{137, "Exited (137) About an hour ago", models.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		inspected := c.containerStateToStatus(&types.ContainerState{Status: "exited", ExitCode: tt.exitCode})
+		listed := c.containerListItemToSimulation(types.Container{ID: "abc123def456789", State: "exited", Status: tt.status})
+		if inspected != tt.want || listed.Status != tt.want {
+			t.Errorf("exit code %d: inspect = %s, list = %s, want %s", tt.exitCode, inspected, listed.Status, tt.want)
+		}
+		if listed.ExitCode == nil || *listed.ExitCode != tt.exitCode {
+			t.Errorf("exit code %d: list ExitCode = %v", tt.exitCode, listed.ExitCode)
+		}
+	}
+
+	if got := c.containerStateStringToStatus("exited", "Exited"); got != models.StatusFailed {
+		t.Errorf("exited without a code = %s, want failed", got)
+	}
+}
+
+func TestContainerReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   *types.ContainerState
+		ready   bool
+		wantErr string
+	}{
+		{"created", &types.ContainerState{Status: "created"}, false, ""},
+		{"running", &types.ContainerState{Status: "running", Running: true}, true, ""},
+		{"restarting", &types.ContainerState{Status: "restarting", Running: true, Restarting: true}, false, ""},
+		{"health starting", &types.ContainerState{Status: "running", Running: true, Health: &container.Health{Status: container.Starting}}, false, ""},
+		{"healthy", &types.ContainerState{Status: "running", Running: true, Health: &container.Health{Status: container.Healthy}}, true, ""},
+		{"no health check", &types.ContainerState{Status: "running", Running: true, Health: &container.Health{Status: container.NoHealthcheck}}, true, ""},
+		{"unhealthy", &types.ContainerState{Status: "running", Running: true, Health: &container.Health{Status: container.Unhealthy}}, false, "unhealthy"},
+		{"exited", &types.ContainerState{Status: "exited", ExitCode: 2}, false, "exited with code 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, err := containerReady(tt.state)
+			if ready != tt.ready {
+				t.Errorf("containerReady() = %v, want %v", ready, tt.ready)
+			}
+			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("containerReady() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPausedStatus(t *testing.T) {
+	c := &Client{labelPrefix: AutoboxLabelPrefix}
+	if got := c.containerStateToStatus(&types.ContainerState{Status: "paused", Running: true, Paused: true}); got != models.StatusPaused {
+		t.Errorf("inspect of a paused container = %s, want paused", got)
+	}
+	if got := c.containerStateStringToStatus("paused", "Up 5 minutes (Paused)"); got != models.StatusPaused {
+		t.Errorf("list entry of a paused container = %s, want paused", got)
+	}
+}
+
+func TestParseRestartPolicy(t *testing.T) {
+	tests := []struct {
+		value       string
+		wantName    container.RestartPolicyMode
+		wantRetries int
+		wantErr     bool
+	}{
+		{value: "", wantName: container.RestartPolicyDisabled},
+		{value: "no", wantName: container.RestartPolicyDisabled},
+		{value: "always", wantName: container.RestartPolicyAlways},
+		{value: "unless-stopped", wantName: container.RestartPolicyUnlessStopped},
+		{value: "on-failure", wantName: container.RestartPolicyOnFailure},
+		{value: "on-failure:5", wantName: container.RestartPolicyOnFailure, wantRetries: 5},
+		{value: "sometimes", wantErr: true},
+		{value: "always:3", wantErr: true},
+		{value: "on-failure:many", wantErr: true},
+		{value: "on-failure:-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		policy, err := ParseRestartPolicy(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRestartPolicy(%q) = %+v, want an error", tt.value, policy)
+			}
+			continue
+		}
+		if err != nil || policy.Name != tt.wantName || policy.MaximumRetryCount != tt.wantRetries {
+			t.Errorf("ParseRestartPolicy(%q) = %+v, %v; want %s with %d retries", tt.value, policy, err, tt.wantName, tt.wantRetries)
+		}
+	}
+}
+
+func TestDecodeStatsStream(t *testing.T) {
+	first := time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC)
+	sample := func(read, preRead time.Time, total, preTotal, system, preSystem uint64) container.StatsResponse {
+		var s container.StatsResponse
+		s.Read, s.PreRead = read, preRead
+		s.CPUStats.CPUUsage.TotalUsage, s.PreCPUStats.CPUUsage.TotalUsage = total, preTotal
+		s.CPUStats.SystemUsage, s.PreCPUStats.SystemUsage = system, preSystem
+		s.CPUStats.CPUUsage.PercpuUsage = []uint64{0, 0}
+		return s
+	}
+
+	var stream bytes.Buffer
+	encoder := json.NewEncoder(&stream)
+	encoder.Encode(sample(first, time.Time{}, 100, 0, 1000, 0))
+	encoder.Encode(sample(first.Add(time.Second), first, 200, 100, 2000, 1000))
+	encoder.Encode(container.StatsResponse{})
+
+	out := make(chan *models.Metrics, 3)
+	if err := (&Client{}).decodeStatsStream(context.Background(), &stream, out); err != nil {
+		t.Fatalf("decodeStatsStream: %v", err)
+	}
+	close(out)
+
+	var got []*models.Metrics
+	for m := range out {
+		got = append(got, m)
+	}
+	if len(got) != 1 {
+		t.Fatalf("decodeStatsStream sent %d samples, want 1 (first and empty samples skipped)", len(got))
+	}
+	if got[0].CPUUsage != 20 || !got[0].Timestamp.Equal(first.Add(time.Second)) {
+		t.Errorf("sample = CPU %.2f at %s, want 20.00 at %s", got[0].CPUUsage, got[0].Timestamp, first.Add(time.Second))
+	}
+}
+
+func TestCPUPercent(t *testing.T) {
+	stats := func(total, preTotal, system, preSystem uint64, online uint32, percpu int) container.StatsResponse {
+		var s container.StatsResponse
+		s.CPUStats.CPUUsage.TotalUsage, s.PreCPUStats.CPUUsage.TotalUsage = total, preTotal
+		s.CPUStats.SystemUsage, s.PreCPUStats.SystemUsage = system, preSystem
+		s.CPUStats.OnlineCPUs = online
+		s.CPUStats.CPUUsage.PercpuUsage = make([]uint64, percpu)
+		return s
+	}
+
+	tests := []struct {
+		name  string
+		stats container.StatsResponse
+		want  float64
+	}{
+		{"cgroup v2 uses OnlineCPUs", stats(1_500, 1_000, 10_000, 9_000, 4, 0), 200},
+		{"OnlineCPUs wins over PercpuUsage", stats(1_500, 1_000, 10_000, 9_000, 2, 8), 100},
+		{"Old daemon falls back to PercpuUsage", stats(1_500, 1_000, 10_000, 9_000, 0, 2), 100},
+		{"No core count counts as one", stats(1_500, 1_000, 10_000, 9_000, 0, 0), 50},
+		{"No previous sample", stats(1_500, 0, 10_000, 0, 4, 0), 0},
+		{"Counter reset", stats(500, 1_000, 10_000, 9_000, 4, 0), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cpuPercent(tt.stats); got != tt.want {
+				t.Errorf("cpuPercent = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeMetricsSource struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	gone     map[string]bool
+	failing  map[string]bool
+}
+
+func (f *fakeMetricsSource) GetSimulationMetrics(ctx context.Context, id string) (*models.Metrics, error) {
+	f.mu.Lock()
+	f.inFlight++
+	if f.inFlight > f.peak {
+		f.peak = f.inFlight
+	}
+	f.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+
+	switch {
+	case f.gone[id]:
+		return nil, fmt.Errorf("failed to get container stats: %w", cerrdefs.ErrNotFound)
+	case f.failing[id]:
+		return nil, errors.New("daemon hiccup")
+	}
+	return &models.Metrics{CPUUsage: float64(len(id))}, nil
+}
+
+func TestFetchMetrics(t *testing.T) {
+	var ids []string
+	for i := 0; i < 20; i++ {
+		ids = append(ids, fmt.Sprintf("container-%02d", i))
+	}
+	source := &fakeMetricsSource{
+		gone:    map[string]bool{"container-03": true},
+		failing: map[string]bool{"container-07": true},
+	}
+
+	results, err := fetchMetrics(context.Background(), source, ids, 4)
+
+	if err == nil || !strings.Contains(err.Error(), "container-07") || strings.Contains(err.Error(), "container-03") {
+		t.Errorf("fetchMetrics() error = %v, want only the container-07 failure", err)
+	}
+	if len(results) != 18 {
+		t.Errorf("fetchMetrics() returned %d results, want 18", len(results))
+	}
+	for _, id := range []string{"container-03", "container-07"} {
+		if _, ok := results[id]; ok {
+			t.Errorf("results include %s, which failed", id)
+		}
+	}
+	if m := results["container-12"]; m == nil || m.CPUUsage != float64(len("container-12")) {
+		t.Errorf("results[container-12] = %+v, want its own metrics", m)
+	}
+	if source.peak > 4 {
+		t.Errorf("fetchMetrics() ran %d requests at once, want at most 4", source.peak)
+	}
+	if source.peak < 2 {
+		t.Errorf("fetchMetrics() ran requests one at a time, want them overlapped")
+	}
+}
+
+func TestResolveSimulation(t *testing.T) {
+	sim := func(id, name string) *models.Simulation {
+		return &models.Simulation{ID: id[:12], ContainerID: id, Name: name}
+	}
+	simulations := []*models.Simulation{
+		sim("abc123def4560000000000000000000000000000000000000000000000000001", "gift-choice"),
+		sim("abc999def4560000000000000000000000000000000000000000000000000002", "summer-sale"),
+		sim("fed456abc1230000000000000000000000000000000000000000000000000003", "summer-sale"),
+		sim("0ddba11000000000000000000000000000000000000000000000000000000004", "(external) hand-started"),
+		// A name that looks like another simulation's ID prefix.
+		sim("777777777777000000000000000000000000000000000000000000000000005", "fed456"),
+	}
+	simulations[0].ContainerName = "autobox-gift-choice-3fa9c1"
+
+	tests := []struct {
+		ref           string
+		wantID        string
+		wantAmbiguous bool
+		wantNotFound  bool
+	}{
+		{ref: "abc123def4560000000000000000000000000000000000000000000000000001", wantID: "abc123def456"},
+		{ref: "abc123def456", wantID: "abc123def456"},
+		{ref: "abc1", wantID: "abc123def456"},
+		{ref: "gift-choice", wantID: "abc123def456"},
+		{ref: "autobox-gift-choice-3fa9c1", wantID: "abc123def456"},
+		{ref: "hand-started", wantID: "0ddba1100000"},
+		{ref: "(external) hand-started", wantID: "0ddba1100000"},
+		{ref: "fed456", wantID: "777777777777"},
+		{ref: "fed4", wantID: "fed456abc123"},
+		{ref: "summer-sale", wantAmbiguous: true},
+		{ref: "abc", wantAmbiguous: true},
+		{ref: "nothing-here", wantNotFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := resolveSimulation(tt.ref, simulations)
+			switch {
+			case tt.wantAmbiguous:
+				if !errors.Is(err, ErrAmbiguousSimulation) {
+					t.Fatalf("resolveSimulation(%q) error = %v, want ErrAmbiguousSimulation", tt.ref, err)
+				}
+			case tt.wantNotFound:
+				if !IsNotFound(err) {
+					t.Fatalf("resolveSimulation(%q) error = %v, want not found", tt.ref, err)
+				}
+			case err != nil:
+				t.Fatalf("resolveSimulation(%q) error = %v", tt.ref, err)
+			case got.ID != tt.wantID:
+				t.Errorf("resolveSimulation(%q) = %s, want %s", tt.ref, got.ID, tt.wantID)
+			}
+		})
+	}
+
+	_, err := resolveSimulation("summer-sale", simulations)
+	if err == nil || !strings.Contains(err.Error(), "abc999def456") || !strings.Contains(err.Error(), "fed456abc123") {
+		t.Errorf("ambiguous error = %v, want it to list both matching IDs", err)
+	}
+}
+
+// writeTestCerts writes a self-signed ca.pem, cert.pem and key.pem to dir.
+func writeTestCerts(t *testing.T, dir string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "autobox-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	files := map[string][]byte{
+		tlsCAFile:   certPEM,
+		tlsCertFile: certPEM,
+		tlsKeyFile:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "unix:///from/env.sock")
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+	t.Setenv("DOCKER_CERT_PATH", "")
+	t.Setenv("DOCKER_API_VERSION", "")
+
+	certDir := t.TempDir()
+	writeTestCerts(t, certDir)
+
+	tests := []struct {
+		name     string
+		cfg      config.DockerConfig
+		wantHost string
+		wantTLS  bool
+		wantErr  string
+	}{
+		{"Environment when no host is configured", config.DockerConfig{}, "unix:///from/env.sock", false, ""},
+		{"Configured host wins", config.DockerConfig{Host: "tcp://build-box:2375"}, "tcp://build-box:2375", false, ""},
+		{"Remote host over TLS", config.DockerConfig{Host: "tcp://build-box:2376", TLSVerify: true, CertPath: certDir}, "tcp://build-box:2376", true, ""},
+		{"Cert path ignored without tls_verify", config.DockerConfig{Host: "tcp://build-box:2375", CertPath: certDir}, "tcp://build-box:2375", false, ""},
+		{"Missing certs", config.DockerConfig{Host: "tcp://build-box:2376", TLSVerify: true, CertPath: t.TempDir()}, "", false, "ca.pem is missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := clientOptions(tt.cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("clientOptions() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("clientOptions() error = %v", err)
+			}
+
+			// Hand the client a transport of our own to see what the
+			// options do to it; the client wraps it for tracing.
+			transport := &http.Transport{}
+			opts = append([]client.Opt{client.WithHTTPClient(&http.Client{Transport: transport})}, opts...)
+			cli, err := client.NewClientWithOpts(opts...)
+			if err != nil {
+				t.Fatalf("NewClientWithOpts() error = %v", err)
+			}
+			defer cli.Close()
+
+			if got := cli.DaemonHost(); got != tt.wantHost {
+				t.Errorf("DaemonHost() = %q, want %q", got, tt.wantHost)
+			}
+			if gotTLS := transport.TLSClientConfig != nil && len(transport.TLSClientConfig.Certificates) > 0; gotTLS != tt.wantTLS {
+				t.Errorf("client certificate configured = %v, want %v", gotTLS, tt.wantTLS)
+			}
+		})
+	}
+}
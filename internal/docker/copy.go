@@ -0,0 +1,353 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+)
+
+// CopyFileFromSimulation writes the contents of the file at path inside the
+// simulation's container to w. It works on stopped containers too, so
+// results can be fetched after a run finishes.
+func (c *Client) CopyFileFromSimulation(ctx context.Context, simulationID, path string, w io.Writer) error {
+	stat, err := c.cli.ContainerStatPath(ctx, simulationID, path)
+	if err != nil {
+		return fmt.Errorf("failed to find %s in the container: %w", path, err)
+	}
+	if stat.Mode.IsDir() {
+		return fmt.Errorf("%s is a directory, not a file", path)
+	}
+
+	archive, _, err := c.cli.CopyFromContainer(ctx, simulationID, path)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from the container: %w", path, err)
+	}
+	defer archive.Close()
+
+	if err := extractSingleFile(archive, w); err != nil {
+		return fmt.Errorf("failed to read %s from the container: %w", path, err)
+	}
+	return nil
+}
+
+// extractSingleFile copies the first regular file in a tar stream to w.
+// Docker archives a single file as a one-entry tar; a symlink is archived as
+// the link itself, which is reported rather than silently skipped.
+func extractSingleFile(r io.Reader, w io.Writer) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("archive contains no file")
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			_, err := io.Copy(w, tr)
+			return err
+		case tar.TypeSymlink:
+			return fmt.Errorf("%s is a symlink to %s", header.Name, header.Linkname)
+		}
+	}
+}
+
+// CopyFromSimulation copies a file or directory from the simulation's
+// container to the host, as docker cp does: into dst if it is an existing
+// directory, otherwise to dst itself. File modes are kept.
+func (c *Client) CopyFromSimulation(ctx context.Context, simulationID, src, dst string) error {
+	archive, _, err := c.cli.CopyFromContainer(ctx, simulationID, src)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from the container: %w", src, err)
+	}
+	defer archive.Close()
+
+	if err := extractArchive(archive, dst); err != nil {
+		return fmt.Errorf("failed to extract %s to %s: %w", src, dst, err)
+	}
+	return nil
+}
+
+// CopyToSimulation copies a host file or directory into the simulation's
+// container, as docker cp does: into dst if it is an existing directory in
+// the container, otherwise to dst itself, whose parent must exist. File
+// modes are kept.
+func (c *Client) CopyToSimulation(ctx context.Context, simulationID, src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+
+	// Docker extracts the archive into a directory, so a copy to a new name
+	// is an archive of that name extracted into the parent.
+	dir, name := path.Dir(dst), path.Base(dst)
+	stat, err := c.cli.ContainerStatPath(ctx, simulationID, dst)
+	switch {
+	case err == nil && stat.Mode.IsDir():
+		dir, name = dst, filepath.Base(src)
+	case err == nil && info.IsDir():
+		return fmt.Errorf("cannot copy directory %s over the file %s", src, dst)
+	case err != nil && !cerrdefs.IsNotFound(err):
+		return fmt.Errorf("failed to find %s in the container: %w", dst, err)
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(buildArchive(src, name, pw))
+	}()
+
+	if err := c.cli.CopyToContainer(ctx, simulationID, dir, pr, container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("failed to copy %s to the container: %w", src, err)
+	}
+	return nil
+}
+
+// buildArchive writes src, a file or a directory tree, to w as a tar archive
+// whose top-level entry is called name.
+func buildArchive(src, name string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractArchive writes the entries of a docker cp archive under dst. If dst
+// is an existing directory the archive lands inside it; otherwise its
+// top-level entry is renamed to dst.
+//
+// The archive comes from a container, so nothing in it is trusted: entries
+// may not name paths outside dst, nothing is written through a symlink, and
+// links may only point at paths inside dst. Symlinks are created after
+// everything else so that no later entry can be written through one.
+func extractArchive(r io.Reader, dst string) error {
+	info, err := os.Stat(dst)
+	intoDir := err == nil && info.IsDir()
+
+	// target maps an archive entry name to the path it is extracted to.
+	target := func(entry string) (string, error) {
+		name := path.Clean(entry)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return "", fmt.Errorf("archive entry %q points outside the destination", entry)
+		}
+		if !intoDir {
+			_, name, _ = strings.Cut(name, "/")
+		}
+		return filepath.Join(dst, filepath.FromSlash(name)), nil
+	}
+
+	var symlinks []*tar.Header
+	var symlinkTargets []string
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		dest, err := target(header.Name)
+		if err != nil {
+			return err
+		}
+		if err := checkNoSymlinks(dst, dest); err != nil {
+			return err
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dest, mode); err != nil {
+				return err
+			}
+			// MkdirAll leaves an existing directory's mode alone and is
+			// subject to the umask.
+			if err := os.Chmod(dest, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(tr, dest, mode); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			if err := linkArchiveFile(dst, header, dest, target); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			symlinks = append(symlinks, header)
+			symlinkTargets = append(symlinkTargets, dest)
+		case tar.TypeXGlobalHeader:
+			// PAX metadata for the whole archive; there is nothing to write.
+		default:
+			return fmt.Errorf("archive entry %q has unsupported type %q", header.Name, header.Typeflag)
+		}
+	}
+
+	for i, header := range symlinks {
+		if err := symlinkArchiveEntry(dst, header, symlinkTargets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkNoSymlinks returns an error if target, or any directory between root
+// and target, is a symlink, since writing there could land outside root.
+func checkNoSymlinks(root, target string) error {
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == "." {
+		return err
+	}
+	current := root
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("refusing to write through symlink %s", current)
+		}
+	}
+	return nil
+}
+
+// within reports whether p is root or lies under it.
+func within(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// linkArchiveFile creates a hardlink entry. Its link name is another entry of
+// the archive, which must already have been extracted as a regular file.
+func linkArchiveFile(root string, header *tar.Header, target string, entryTarget func(string) (string, error)) error {
+	source, err := entryTarget(header.Linkname)
+	if err != nil {
+		return err
+	}
+	if err := checkNoSymlinks(root, source); err != nil {
+		return err
+	}
+	info, err := os.Lstat(source)
+	if err != nil {
+		return fmt.Errorf("archive entry %q links to missing %q: %w", header.Name, header.Linkname, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("archive entry %q links to %q, which is not a regular file", header.Name, header.Linkname)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Link(source, target)
+}
+
+// symlinkArchiveEntry creates a symlink entry, refusing one that resolves to
+// a path outside root.
+func symlinkArchiveEntry(root string, header *tar.Header, target string) error {
+	if filepath.IsAbs(header.Linkname) || !within(root, filepath.Join(filepath.Dir(target), header.Linkname)) {
+		return fmt.Errorf("archive entry %q links to %q, outside the destination", header.Name, header.Linkname)
+	}
+	// Earlier symlinks may have changed what the parents resolve to.
+	if err := checkNoSymlinks(root, target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if err := os.Symlink(header.Linkname, target); err != nil {
+		return err
+	}
+
+	// The check above is lexical; a link through another link can still
+	// resolve elsewhere, so check where it really ends up.
+	resolved, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		// A dangling link points nowhere, which is harmless.
+		return nil
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+	if !within(realRoot, resolved) {
+		os.Remove(target)
+		return fmt.Errorf("archive entry %q links to %q, outside the destination", header.Name, header.Linkname)
+	}
+	return nil
+}
+
+func writeArchiveFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(target, mode)
+}
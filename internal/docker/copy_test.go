@@ -0,0 +1,249 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type tarEntry struct {
+	header tar.Header
+	body   string
+}
+
+func buildTar(t *testing.T, entries ...tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		header := entry.header
+		header.Size = int64(len(entry.body))
+		if err := tw.WriteHeader(&header); err != nil {
+			t.Fatalf("WriteHeader() error = %v", err)
+		}
+		if _, err := tw.Write([]byte(entry.body)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return &buf
+}
+
+func TestExtractSingleFile(t *testing.T) {
+	results := `{"rounds": 3}`
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "Single file",
+			entries: []tarEntry{{tar.Header{Name: "results.json", Typeflag: tar.TypeReg, Mode: 0644}, results}},
+			want:    results,
+		},
+		{
+			name: "File after a directory",
+			entries: []tarEntry{
+				{tar.Header{Name: "logs/", Typeflag: tar.TypeDir, Mode: 0755}, ""},
+				{tar.Header{Name: "logs/results.json", Typeflag: tar.TypeReg, Mode: 0644}, results},
+			},
+			want: results,
+		},
+		{
+			name:    "Symlink",
+			entries: []tarEntry{{tar.Header{Name: "results.json", Typeflag: tar.TypeSymlink, Linkname: "/data/out.json"}, ""}},
+			wantErr: "symlink to /data/out.json",
+		},
+		{
+			name:    "Empty archive",
+			wantErr: "no file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := extractSingleFile(buildTar(t, tt.entries...), &out)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("extractSingleFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractSingleFile() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("extractSingleFile() wrote %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+
+	if err := extractSingleFile(strings.NewReader("not a tar archive"), &bytes.Buffer{}); err == nil {
+		t.Error("extractSingleFile() of garbage succeeded, want an error")
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "logs")
+	if err := os.MkdirAll(filepath.Join(src, "agents"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "results.json"), []byte(`{"rounds": 3}`), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "agents", "run.sh"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	if err := buildArchive(src, "logs", &archive); err != nil {
+		t.Fatalf("buildArchive() error = %v", err)
+	}
+
+	// Into an existing directory, the top-level name is kept.
+	into := t.TempDir()
+	if err := extractArchive(bytes.NewReader(archive.Bytes()), into); err != nil {
+		t.Fatalf("extractArchive() error = %v", err)
+	}
+	checkFile(t, filepath.Join(into, "logs", "results.json"), `{"rounds": 3}`, 0640)
+	checkFile(t, filepath.Join(into, "logs", "agents", "run.sh"), "#!/bin/sh\n", 0755)
+	if info, err := os.Stat(filepath.Join(into, "logs", "agents")); err != nil || info.Mode().Perm() != 0750 {
+		t.Errorf("agents directory = %v, %v, want mode 0750", info, err)
+	}
+
+	// To a new path, the top-level entry takes that name.
+	renamed := filepath.Join(t.TempDir(), "copy")
+	if err := extractArchive(bytes.NewReader(archive.Bytes()), renamed); err != nil {
+		t.Fatalf("extractArchive() error = %v", err)
+	}
+	checkFile(t, filepath.Join(renamed, "results.json"), `{"rounds": 3}`, 0640)
+
+	// A single file to a new path is written as that file.
+	var single bytes.Buffer
+	if err := buildArchive(filepath.Join(src, "results.json"), "results.json", &single); err != nil {
+		t.Fatalf("buildArchive() error = %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "out.json")
+	if err := extractArchive(&single, out); err != nil {
+		t.Fatalf("extractArchive() error = %v", err)
+	}
+	checkFile(t, out, `{"rounds": 3}`, 0640)
+}
+
+func TestExtractArchiveRejectsEscapes(t *testing.T) {
+	archive := buildTar(t, tarEntry{tar.Header{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0644}, "x"})
+	if err := extractArchive(archive, t.TempDir()); err == nil {
+		t.Error("extractArchive() wrote outside the destination, want an error")
+	}
+}
+
+func TestExtractArchiveRejectsMaliciousLinks(t *testing.T) {
+	outside := t.TempDir()
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{
+			name: "absolute symlink then a file through it",
+			entries: []tarEntry{
+				{tar.Header{Name: "logs/x", Typeflag: tar.TypeSymlink, Linkname: outside}, ""},
+				{tar.Header{Name: "logs/x/evil", Typeflag: tar.TypeReg, Mode: 0644}, "pwned"},
+			},
+		},
+		{
+			name: "relative symlink out of the destination",
+			entries: []tarEntry{
+				{tar.Header{Name: "logs/x", Typeflag: tar.TypeSymlink, Linkname: "../../.."}, ""},
+			},
+		},
+		{
+			name: "symlink escaping through another symlink",
+			entries: []tarEntry{
+				{tar.Header{Name: "logs/here", Typeflag: tar.TypeSymlink, Linkname: ".."}, ""},
+				{tar.Header{Name: "logs/x", Typeflag: tar.TypeSymlink, Linkname: "here/.."}, ""},
+			},
+		},
+		{
+			name: "hardlink out of the destination",
+			entries: []tarEntry{
+				{tar.Header{Name: "logs/x", Typeflag: tar.TypeLink, Linkname: "../etc/passwd"}, ""},
+			},
+		},
+		{
+			name: "device node",
+			entries: []tarEntry{
+				{tar.Header{Name: "logs/dev", Typeflag: tar.TypeChar, Mode: 0644}, ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := t.TempDir()
+			if err := extractArchive(buildTar(t, tt.entries...), dst); err == nil {
+				t.Error("extractArchive() error = nil, want an error")
+			}
+			if _, err := os.Lstat(filepath.Join(outside, "evil")); err == nil {
+				t.Error("extractArchive() wrote outside the destination")
+			}
+		})
+	}
+}
+
+func TestExtractArchiveRefusesExistingSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	dst := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dst, "logs")); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := buildTar(t, tarEntry{tar.Header{Name: "logs/evil", Typeflag: tar.TypeReg, Mode: 0644}, "pwned"})
+	if err := extractArchive(archive, dst); err == nil {
+		t.Error("extractArchive() error = nil, want an error")
+	}
+	if _, err := os.Lstat(filepath.Join(outside, "evil")); err == nil {
+		t.Error("extractArchive() wrote through an existing symlink")
+	}
+}
+
+func TestExtractArchiveLinks(t *testing.T) {
+	archive := buildTar(t,
+		tarEntry{tar.Header{Name: "logs/", Typeflag: tar.TypeDir, Mode: 0755}, ""},
+		tarEntry{tar.Header{Name: "logs/latest", Typeflag: tar.TypeSymlink, Linkname: "run.json"}, ""},
+		tarEntry{tar.Header{Name: "logs/run.json", Typeflag: tar.TypeReg, Mode: 0644}, "{}"},
+		tarEntry{tar.Header{Name: "logs/copy.json", Typeflag: tar.TypeLink, Linkname: "logs/run.json"}, ""},
+	)
+	dst := t.TempDir()
+	if err := extractArchive(archive, dst); err != nil {
+		t.Fatalf("extractArchive() error = %v", err)
+	}
+	checkFile(t, filepath.Join(dst, "logs", "latest"), "{}", 0644)
+	checkFile(t, filepath.Join(dst, "logs", "copy.json"), "{}", 0644)
+	if link, err := os.Readlink(filepath.Join(dst, "logs", "latest")); err != nil || link != "run.json" {
+		t.Errorf("Readlink(latest) = %q, %v, want run.json", link, err)
+	}
+}
+
+func checkFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	if string(data) != content {
+		t.Errorf("%s = %q, want %q", path, data, content)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != mode {
+		t.Errorf("%s mode = %v, want %v", path, info.Mode().Perm(), mode)
+	}
+}
@@ -0,0 +1,93 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// engineVersionLabels are checked in order for a version baked into the image,
+// which avoids starting a container just to ask the engine. The first is the
+// key the CLI labels simulations with, under the configured prefix.
+func (c *Client) engineVersionLabels() []string {
+	return []string{c.label("engine_version"), "org.opencontainers.image.version"}
+}
+
+const engineVersionProbeTimeout = 30 * time.Second
+
+var engineVersionPattern = regexp.MustCompile(`v?(\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?)`)
+
+// EngineVersion reports the engine version an image contains, from an image
+// label when present and otherwise by running "<image> --version" in a
+// throwaway container with networking disabled.
+func (c *Client) EngineVersion(ctx context.Context, ref string) (string, error) {
+	inspect, err := c.cli.ImageInspect(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image: %w", err)
+	}
+	if inspect.Config != nil {
+		for _, label := range c.engineVersionLabels() {
+			if version := parseEngineVersion(inspect.Config.Labels[label]); version != "" {
+				return version, nil
+			}
+		}
+	}
+
+	output, err := c.probeEngineVersion(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	version := parseEngineVersion(output)
+	if version == "" {
+		return "", fmt.Errorf("no version in engine output %q", output)
+	}
+	return version, nil
+}
+
+func (c *Client) probeEngineVersion(ctx context.Context, ref string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, engineVersionProbeTimeout)
+	defer cancel()
+
+	resp, err := c.cli.ContainerCreate(ctx,
+		&container.Config{Image: ref, Cmd: []string{"--version"}},
+		&container.HostConfig{NetworkMode: "none"},
+		nil, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to create version probe: %w", err)
+	}
+	defer c.cli.ContainerRemove(context.Background(), resp.ID, container.RemoveOptions{Force: true})
+
+	if err := c.cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return "", fmt.Errorf("failed to start version probe: %w", err)
+	}
+	if _, err := c.WaitForExit(ctx, resp.ID); err != nil {
+		return "", err
+	}
+
+	logs, err := c.cli.ContainerLogs(ctx, resp.ID, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to read version probe output: %w", err)
+	}
+	defer logs.Close()
+
+	var out bytes.Buffer
+	if _, err := stdcopy.StdCopy(&out, &out, logs); err != nil {
+		return "", fmt.Errorf("failed to read version probe output: %w", err)
+	}
+	return string(bytes.TrimSpace(out.Bytes())), nil
+}
+
+// parseEngineVersion extracts the first semantic version from s, without a
+// leading "v", or returns an empty string.
+func parseEngineVersion(s string) string {
+	match := engineVersionPattern.FindStringSubmatch(s)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
@@ -0,0 +1,32 @@
+package docker
+
+import "testing"
+
+func TestParseEngineVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0.4.2", "0.4.2"},
+		{"autobox-engine v1.2.3\n", "1.2.3"},
+		{"Autobox Engine 0.5.0-beta.1 (build abc)", "0.5.0-beta.1"},
+		{"unknown option --version", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := parseEngineVersion(tt.input)
+			if result != tt.expected {
+				t.Errorf("parseEngineVersion(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEngineVersionLabels(t *testing.T) {
+	c := &Client{labelPrefix: "org.example.fleet"}
+	if labels := c.engineVersionLabels(); labels[0] != "org.example.fleet.engine_version" {
+		t.Errorf("engineVersionLabels() = %v, want the configured prefix first", labels)
+	}
+}
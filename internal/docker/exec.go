@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ExecOptions controls ExecInSimulation. Stdin is only attached when set.
+// With TTY the command gets a pseudo-terminal sized to ConsoleSize (height,
+// width) and its output, stdout and stderr merged, is written to Stdout.
+// Without it stdout and stderr are kept apart.
+type ExecOptions struct {
+	Stdin       io.Reader
+	Stdout      io.Writer
+	Stderr      io.Writer
+	TTY         bool
+	ConsoleSize *[2]uint
+}
+
+// ExecInSimulation runs command inside the simulation's container and
+// returns its exit code once it finishes. The container must be running; a
+// paused container is reported as such, since Docker can't exec into it.
+func (c *Client) ExecInSimulation(ctx context.Context, simulationID string, command []string, opts ExecOptions) (int, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, simulationID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	switch {
+	case inspect.State == nil || !inspect.State.Running:
+		return 0, fmt.Errorf("simulation %s is not running", inspect.ID[:12])
+	case inspect.State.Paused:
+		return 0, fmt.Errorf("simulation %s is paused (run 'autobox unpause' first)", inspect.ID[:12])
+	}
+
+	created, err := c.cli.ContainerExecCreate(ctx, inspect.ID, container.ExecOptions{
+		Cmd:          command,
+		Tty:          opts.TTY,
+		ConsoleSize:  opts.ConsoleSize,
+		AttachStdin:  opts.Stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create exec: %w", err)
+	}
+
+	attach, err := c.cli.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{
+		Tty:         opts.TTY,
+		ConsoleSize: opts.ConsoleSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to attach to exec: %w", err)
+	}
+	defer attach.Close()
+
+	if opts.Stdin != nil {
+		go func() {
+			io.Copy(attach.Conn, opts.Stdin)
+			// Let the command see EOF on stdin, as a shell does at Ctrl+D.
+			attach.CloseWrite()
+		}()
+	}
+
+	// Only the output side decides when the exec is over: stdin may be a
+	// terminal that never reaches EOF.
+	done := make(chan error, 1)
+	go func() {
+		done <- copyOutput(attach.Reader, opts.Stdout, opts.Stderr, opts.TTY)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return 0, fmt.Errorf("failed to read exec output: %w", err)
+		}
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	result, err := c.cli.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get exec exit code: %w", err)
+	}
+	return result.ExitCode, nil
+}
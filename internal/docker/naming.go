@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+)
+
+const (
+	containerNamePrefix = "autobox-"
+	// maxNameStem keeps generated names readable in docker ps; the random
+	// suffix, not the stem, is what makes them unique.
+	maxNameStem = 40
+	// maxNameAttempts bounds the retries on a name conflict.
+	maxNameAttempts = 10
+)
+
+// containerName builds the Docker name for a new container of the simulation
+// called simName: "autobox-<sanitized name>-<suffix>".
+func containerName(simName, suffix string) string {
+	return containerNamePrefix + sanitizeContainerName(simName) + "-" + suffix
+}
+
+// sanitizeContainerName maps a simulation name onto the characters Docker
+// allows in container names, [a-zA-Z0-9_.-], replacing runs of anything else
+// with a single dash. A name with nothing usable becomes "simulation".
+func sanitizeContainerName(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	stem := b.String()
+	if len(stem) > maxNameStem {
+		stem = stem[:maxNameStem]
+	}
+	stem = strings.TrimRight(stem, "-_.")
+	if stem == "" {
+		return "simulation"
+	}
+	return stem
+}
+
+// randomSuffix returns six hex characters, enough to keep two runs of the
+// same simulation apart.
+func randomSuffix() string {
+	buf := make([]byte, 3)
+	if _, err := rand.Read(buf); err != nil {
+		return "000000"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// createNamedContainer creates the container under name, or under name-2,
+// name-3 and so on if that name is already taken. It returns the name used.
+func (c *Client) createNamedContainer(ctx context.Context, name string, config *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, string, error) {
+	candidate := name
+	for attempt := 1; ; attempt++ {
+		resp, err := c.cli.ContainerCreate(ctx, config, hostConfig, nil, nil, candidate)
+		if err == nil {
+			return resp, candidate, nil
+		}
+		if !cerrdefs.IsConflict(err) || attempt == maxNameAttempts {
+			return resp, "", err
+		}
+		candidate = fmt.Sprintf("%s-%d", name, attempt+1)
+	}
+}
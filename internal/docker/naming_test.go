@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// dockerNamePattern is what the daemon accepts as a container name.
+var dockerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+func TestSanitizeContainerName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Already valid", "gift_choice", "gift_choice"},
+		{"Spaces", "Gift choice", "Gift-choice"},
+		{"Runs of invalid characters", "summer  sale / 2024!", "summer-sale-2024"},
+		{"Leading invalid characters", "--/test", "test"},
+		{"Dots kept", "v1.2", "v1.2"},
+		{"Non-ASCII", "café crème", "caf-cr-me"},
+		{"Nothing usable", "日本語", "simulation"},
+		{"Empty", "", "simulation"},
+		{"Long", strings.Repeat("a", 60), strings.Repeat("a", maxNameStem)},
+		{"Cut at a separator", strings.Repeat("a", maxNameStem-1) + " b", strings.Repeat("a", maxNameStem-1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeContainerName(tt.input)
+			if got != tt.expected {
+				t.Errorf("sanitizeContainerName(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+			name := containerName(tt.input, randomSuffix())
+			if !dockerNamePattern.MatchString(name) {
+				t.Errorf("containerName(%q) = %q, which Docker would reject", tt.input, name)
+			}
+		})
+	}
+}
+
+func TestContainerName(t *testing.T) {
+	if got := containerName("Gift choice", "3fa9c1"); got != "autobox-Gift-choice-3fa9c1" {
+		t.Errorf("containerName() = %q, want autobox-Gift-choice-3fa9c1", got)
+	}
+	if a, b := randomSuffix(), randomSuffix(); len(a) != 6 || a == b {
+		t.Errorf("randomSuffix() = %q then %q, want two different 6-character suffixes", a, b)
+	}
+}
@@ -0,0 +1,209 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+const defaultPullBackoff = time.Second
+
+// PullOptions controls how PullImage retries transient failures. Each retry
+// waits twice as long as the previous one, starting at Backoff.
+type PullOptions struct {
+	Retries int
+	Backoff time.Duration
+	// OnRetry, if set, is called before each retry with the attempt number
+	// (starting at 1), the delay about to be waited, and the failure.
+	OnRetry func(attempt int, delay time.Duration, err error)
+	// OnProgress, if set, is called for each progress message in the pull
+	// stream with the layer ID (empty for image-level messages), the status
+	// and the daemon's progress bar, which is empty for status-only lines.
+	OnProgress func(id, status, progress string)
+	// OnStart, if set, is called once EnsureImage has decided to pull,
+	// before the first attempt.
+	OnStart func()
+}
+
+// PullPolicy says when EnsureImage pulls, as docker run --pull does.
+type PullPolicy string
+
+const (
+	PullNever   PullPolicy = "never"
+	PullMissing PullPolicy = "missing"
+	PullAlways  PullPolicy = "always"
+)
+
+// ParsePullPolicy parses never, missing or always. An empty value means
+// missing.
+func ParsePullPolicy(value string) (PullPolicy, error) {
+	switch policy := PullPolicy(value); policy {
+	case "":
+		return PullMissing, nil
+	case PullNever, PullMissing, PullAlways:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid pull policy %q (expected never, missing or always)", value)
+	}
+}
+
+// pullMessage is one line of the JSON stream returned by ImagePull.
+type pullMessage struct {
+	Status      string `json:"status"`
+	ID          string `json:"id"`
+	Progress    string `json:"progress"`
+	Error       string `json:"error"`
+	ErrorDetail *struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+}
+
+// pullStreamError is a failure reported inside the pull stream after the
+// daemon accepted the request, typically a layer download that was cut off.
+type pullStreamError struct {
+	message string
+}
+
+func (e *pullStreamError) Error() string {
+	return e.message
+}
+
+// ImageExists reports whether ref is available locally.
+func (c *Client) ImageExists(ctx context.Context, ref string) (bool, error) {
+	if _, err := c.cli.ImageInspect(ctx, ref); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to inspect image: %w", err)
+	}
+	return true, nil
+}
+
+// EnsureImage makes ref available locally according to policy and reports
+// whether it pulled. With PullNever a missing image is an error; with
+// PullAlways the image is pulled even if present, picking up a moved tag.
+func (c *Client) EnsureImage(ctx context.Context, ref string, policy PullPolicy, opts PullOptions) (bool, error) {
+	if policy != PullAlways {
+		exists, err := c.ImageExists(ctx, ref)
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			return false, nil
+		}
+		if policy == PullNever {
+			return false, fmt.Errorf("image %s is not available locally and the pull policy is never", ref)
+		}
+	}
+
+	if opts.OnStart != nil {
+		opts.OnStart()
+	}
+	if err := c.PullImage(ctx, ref, opts); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PullImage pulls ref from its registry, retrying transient errors up to
+// opts.Retries times. Errors that retrying can't fix, such as an image that
+// doesn't exist in the registry or denied credentials, fail immediately.
+func (c *Client) PullImage(ctx context.Context, ref string, opts PullOptions) error {
+	delay := opts.Backoff
+	if delay <= 0 {
+		delay = defaultPullBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.pullOnce(ctx, ref, opts.OnProgress)
+		if err == nil {
+			return nil
+		}
+		if attempt > opts.Retries || !isTransientPullError(err) {
+			return fmt.Errorf("failed to pull image %s: %w", ref, err)
+		}
+
+		if opts.OnRetry != nil {
+			opts.OnRetry(attempt, delay, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (c *Client) pullOnce(ctx context.Context, ref string, onProgress func(id, status, progress string)) error {
+	stream, err := c.cli.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	return decodePullStream(stream, onProgress)
+}
+
+// decodePullStream reads the daemon's JSON pull messages, passing each status
+// to onProgress, until the stream ends or reports an error.
+func decodePullStream(stream io.Reader, onProgress func(id, status, progress string)) error {
+	decoder := json.NewDecoder(stream)
+	for {
+		var msg pullMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if msg.Error != "" {
+			return &pullStreamError{message: msg.Error}
+		}
+		if onProgress != nil && msg.Status != "" {
+			onProgress(msg.ID, msg.Status, msg.Progress)
+		}
+	}
+}
+
+// isTransientPullError reports whether a pull failure is worth retrying.
+// Registry connectivity problems surface from the daemon as unavailable or
+// internal errors, while missing images and auth failures have their own
+// error classes and never succeed on retry.
+func isTransientPullError(err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case cerrdefs.IsNotFound(err),
+		cerrdefs.IsUnauthorized(err),
+		cerrdefs.IsPermissionDenied(err),
+		cerrdefs.IsInvalidArgument(err):
+		return false
+	case cerrdefs.IsUnavailable(err),
+		cerrdefs.IsInternal(err),
+		client.IsErrConnectionFailed(err):
+		return true
+	}
+
+	var streamErr *pullStreamError
+	if errors.As(err, &streamErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
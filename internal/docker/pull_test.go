@@ -0,0 +1,96 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+)
+
+func TestIsTransientPullError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"Nil", nil, false},
+		{"Not found", fmt.Errorf("pull: %w", cerrdefs.ErrNotFound), false},
+		{"Unauthorized", cerrdefs.ErrUnauthenticated, false},
+		{"Permission denied", cerrdefs.ErrPermissionDenied, false},
+		{"Invalid reference", cerrdefs.ErrInvalidArgument, false},
+		{"Canceled", context.Canceled, false},
+		{"Unavailable", cerrdefs.ErrUnavailable, true},
+		{"Registry 5xx", fmt.Errorf("daemon: %w", cerrdefs.ErrInternal), true},
+		{"Stream error", &pullStreamError{message: "unexpected EOF"}, true},
+		{"Cut-off stream", io.ErrUnexpectedEOF, true},
+		{"Other", errors.New("something else"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := isTransientPullError(tt.err)
+			if result != tt.expected {
+				t.Errorf("isTransientPullError(%v) = %v, want %v", tt.err, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodePullStream(t *testing.T) {
+	stream := `{"status":"Pulling from library/autobox-engine","id":"latest"}
+{"status":"Pulling fs layer","progressDetail":{},"id":"a1b2c3"}
+{"status":"Downloading","progressDetail":{"current":1024,"total":4096},"progress":"[=====>      ]  1.024kB/4.096kB","id":"a1b2c3"}
+{"status":"Pull complete","progressDetail":{},"id":"a1b2c3"}
+{"status":"Digest: sha256:0123"}
+{"status":"Status: Downloaded newer image for autobox-engine:latest"}
+`
+	type event struct{ id, status, progress string }
+	var got []event
+	err := decodePullStream(strings.NewReader(stream), func(id, status, progress string) {
+		got = append(got, event{id, status, progress})
+	})
+	if err != nil {
+		t.Fatalf("decodePullStream() error = %v", err)
+	}
+
+	want := []event{
+		{"latest", "Pulling from library/autobox-engine", ""},
+		{"a1b2c3", "Pulling fs layer", ""},
+		{"a1b2c3", "Downloading", "[=====>      ]  1.024kB/4.096kB"},
+		{"a1b2c3", "Pull complete", ""},
+		{"", "Digest: sha256:0123", ""},
+		{"", "Status: Downloaded newer image for autobox-engine:latest", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodePullStream() events = %v, want %v", got, want)
+	}
+
+	failing := `{"status":"Downloading","id":"a1b2c3"}
+{"errorDetail":{"message":"unexpected EOF"},"error":"unexpected EOF"}
+`
+	err = decodePullStream(strings.NewReader(failing), nil)
+	var streamErr *pullStreamError
+	if !errors.As(err, &streamErr) || streamErr.message != "unexpected EOF" {
+		t.Errorf("decodePullStream() error = %v, want the stream's error", err)
+	}
+
+	if err := decodePullStream(strings.NewReader(`{"status": "Downl`), nil); err == nil {
+		t.Error("decodePullStream() of a cut-off message succeeded, want an error")
+	}
+}
+
+func TestParsePullPolicy(t *testing.T) {
+	for value, want := range map[string]PullPolicy{"": PullMissing, "never": PullNever, "missing": PullMissing, "always": PullAlways} {
+		if got, err := ParsePullPolicy(value); err != nil || got != want {
+			t.Errorf("ParsePullPolicy(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParsePullPolicy("sometimes"); err == nil {
+		t.Error("ParsePullPolicy(sometimes) succeeded, want an error")
+	}
+}
@@ -0,0 +1,132 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Autobox-AI/autobox-cli/pkg/models"
+	"github.com/docker/docker/api/types/container"
+)
+
+// HostResources is the capacity of the machine running the Docker daemon.
+type HostResources struct {
+	CPUs        int
+	MemoryBytes int64
+}
+
+// ContainerResources is what a simulation container currently uses and the
+// limits configured on it. Zero limits mean the container is unbounded.
+type ContainerResources struct {
+	// CPUs is the number of cores in use, e.g. 1.5 for one and a half.
+	CPUs             float64
+	MemoryBytes      uint64
+	CPULimit         float64
+	MemoryLimitBytes int64
+}
+
+// HostResources reports the CPUs and memory available to the daemon.
+func (c *Client) HostResources(ctx context.Context) (*HostResources, error) {
+	info, err := c.cli.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Docker info: %w", err)
+	}
+	return &HostResources{CPUs: info.NCPU, MemoryBytes: info.MemTotal}, nil
+}
+
+// ContainerResources reports the current usage and configured limits of a
+// simulation container.
+func (c *Client) ContainerResources(ctx context.Context, simulationID string) (*ContainerResources, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, simulationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	containerStats, err := c.cpuStatsSample(ctx, simulationID)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := &ContainerResources{
+		CPUs:        cpuPercent(containerStats) / 100,
+		MemoryBytes: containerStats.MemoryStats.Usage,
+	}
+	if inspect.HostConfig != nil {
+		resources.CPULimit = float64(inspect.HostConfig.NanoCPUs) / 1e9
+		resources.MemoryLimitBytes = inspect.HostConfig.Memory
+	}
+	return resources, nil
+}
+
+// minMemoryLimit is the smallest memory limit Docker accepts.
+const minMemoryLimit = 6 << 20
+
+// ParseMemoryLimit parses a memory size like docker run --memory: a number
+// with an optional b, k, m or g suffix (powers of 1024, case-insensitive,
+// "512m" or "512mb"). A bare number is bytes.
+func ParseMemoryLimit(value string) (int64, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	if s == "" {
+		return 0, fmt.Errorf("invalid memory limit %q: expected a size like 512m or 2g", value)
+	}
+
+	multiplier := int64(1)
+	s = strings.TrimSuffix(s, "b")
+	switch {
+	case strings.HasSuffix(s, "k"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(s, "m"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(s, "g"):
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("invalid memory limit %q: expected a size like 512m or 2g", value)
+	}
+	size := n * float64(multiplier)
+	if size > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid memory limit %q: too large", value)
+	}
+	if size < minMemoryLimit {
+		return 0, fmt.Errorf("invalid memory limit %q: Docker requires at least 6m", value)
+	}
+	return int64(size), nil
+}
+
+// ValidateCPULimit checks a CPU limit in cores, as given to docker run --cpus.
+// Zero means unlimited.
+func ValidateCPULimit(cpus float64) error {
+	if cpus < 0 || math.IsNaN(cpus) || math.IsInf(cpus, 0) {
+		return fmt.Errorf("invalid CPU limit %v: expected a positive number of cores like 1.5", cpus)
+	}
+	if cpus > 0 && cpus < 0.01 {
+		return fmt.Errorf("invalid CPU limit %v: must be at least 0.01", cpus)
+	}
+	return nil
+}
+
+// resourceLimits converts the simulation's CPU and memory limits into Docker's
+// units. Unset limits are left at zero, which Docker treats as unbounded.
+func resourceLimits(config models.SimulationConfig) (container.Resources, error) {
+	var resources container.Resources
+	if err := ValidateCPULimit(config.CPULimit); err != nil {
+		return resources, err
+	}
+	resources.NanoCPUs = int64(config.CPULimit * 1e9)
+
+	if config.MemoryLimit != "" {
+		memory, err := ParseMemoryLimit(config.MemoryLimit)
+		if err != nil {
+			return resources, err
+		}
+		resources.Memory = memory
+	}
+	return resources, nil
+}
@@ -0,0 +1,141 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const historyFile = "history.jsonl"
+
+// OutcomeRunning marks a run whose exit hasn't been recorded yet. Finished
+// runs use the simulation status (completed, failed, stopped), or
+// OutcomeTerminated when terminate removed a live container, or
+// OutcomeRemoved when the container disappeared before its exit was seen.
+const (
+	OutcomeRunning    = "running"
+	OutcomeTerminated = "terminated"
+	OutcomeRemoved    = "removed"
+)
+
+// HistoryRecord describes one run. The history file is append-only: a run is
+// written once at launch and again when it finishes, and ReadHistory merges
+// records that share a container ID.
+type HistoryRecord struct {
+	ContainerID string     `json:"container_id"`
+	Name        string     `json:"name"`
+	Image       string     `json:"image,omitempty"`
+	ImageDigest string     `json:"image_digest,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExitCode    *int       `json:"exit_code,omitempty"`
+	Outcome     string     `json:"outcome"`
+}
+
+func (r HistoryRecord) Finished() bool {
+	return r.Outcome != OutcomeRunning
+}
+
+// Duration is the run time of a finished run, or zero if unknown.
+func (r HistoryRecord) Duration() time.Duration {
+	if r.FinishedAt == nil || r.StartedAt.IsZero() {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// AppendHistory writes a record to ~/.autobox/state/history.jsonl.
+func AppendHistory(record HistoryRecord) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, historyFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// ReadHistory returns one record per run in launch order. Later records for
+// the same container override earlier ones, keeping fields they leave empty.
+// Malformed lines, e.g. from an interrupted write, are skipped.
+func ReadHistory() ([]HistoryRecord, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(dir, historyFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	var records []HistoryRecord
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record HistoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.ContainerID == "" {
+			continue
+		}
+
+		i, seen := index[record.ContainerID]
+		if !seen {
+			index[record.ContainerID] = len(records)
+			records = append(records, record)
+			continue
+		}
+		records[i] = records[i].Merge(record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	return records, nil
+}
+
+// Merge returns r updated with the non-empty fields of update.
+func (r HistoryRecord) Merge(update HistoryRecord) HistoryRecord {
+	if update.Name != "" {
+		r.Name = update.Name
+	}
+	if update.Image != "" {
+		r.Image = update.Image
+	}
+	if update.ImageDigest != "" {
+		r.ImageDigest = update.ImageDigest
+	}
+	if !update.StartedAt.IsZero() {
+		r.StartedAt = update.StartedAt
+	}
+	if update.FinishedAt != nil {
+		r.FinishedAt = update.FinishedAt
+	}
+	if update.ExitCode != nil {
+		r.ExitCode = update.ExitCode
+	}
+	if update.Outcome != "" {
+		r.Outcome = update.Outcome
+	}
+	return r
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "autobox-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	records, err := ReadHistory()
+	if err != nil {
+		t.Fatalf("ReadHistory() on empty state error = %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("Expected no records, got %d", len(records))
+	}
+
+	started := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	finished := started.Add(90 * time.Second)
+	exitCode := 1
+
+	for _, record := range []HistoryRecord{
+		{ContainerID: "aaa", Name: "first", Image: "autobox-engine:latest", StartedAt: started, Outcome: OutcomeRunning},
+		{ContainerID: "bbb", Name: "second", StartedAt: started.Add(time.Minute), Outcome: OutcomeRunning},
+		{ContainerID: "aaa", FinishedAt: &finished, ExitCode: &exitCode, Outcome: "failed"},
+	} {
+		if err := AppendHistory(record); err != nil {
+			t.Fatalf("AppendHistory() error = %v", err)
+		}
+	}
+
+	f, err := os.OpenFile(filepath.Join(tmpDir, ".autobox", "state", historyFile), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	f.WriteString("{\"container_id\": \"ccc\", \"na\n")
+	f.Close()
+
+	records, err = ReadHistory()
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 merged records, got %d", len(records))
+	}
+
+	first := records[0]
+	if first.Name != "first" || first.Image != "autobox-engine:latest" {
+		t.Errorf("Expected launch fields to survive the merge, got %+v", first)
+	}
+	if first.Outcome != "failed" || first.ExitCode == nil || *first.ExitCode != 1 {
+		t.Errorf("Expected exit fields from the later record, got %+v", first)
+	}
+	if !first.Finished() || first.Duration() != 90*time.Second {
+		t.Errorf("Expected a finished 90s run, got finished=%v duration=%s", first.Finished(), first.Duration())
+	}
+
+	if records[1].Finished() {
+		t.Errorf("Expected second run to still be running")
+	}
+}
@@ -0,0 +1,183 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// staleLockAge bounds how long a lock is honored. Mutating operations finish
+// well within this window, so an older lock was left by a crashed process.
+const staleLockAge = 10 * time.Minute
+
+var ErrLocked = errors.New("operation in progress")
+
+type Lock struct {
+	path string
+}
+
+// AcquireLock takes an exclusive per-simulation lock under
+// ~/.autobox/state/locks/. It fails fast with ErrLocked when another
+// invocation holds the lock, rather than waiting on a Docker conflict.
+//
+// The lock file is written in full under a temporary name and then linked
+// into place, so another invocation never sees a lock without its pid and
+// start time and mistakes it for a broken one.
+func AcquireLock(containerID string) (*Lock, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	lockDir := filepath.Join(dir, "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	tmp, err := writeLockFile(lockDir)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+
+	path := filepath.Join(lockDir, containerID+".lock")
+	for attempt := 0; attempt < 2; attempt++ {
+		err := os.Link(tmp, path)
+		if err == nil {
+			return &Lock{path: path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lock file: %w", err)
+		}
+		data, readErr := os.ReadFile(path)
+		var pid int
+		var since time.Time
+		if readErr == nil {
+			pid, since, readErr = parseLock(data)
+		}
+		if readErr != nil {
+			// A lock that can't be read, such as one left by an older
+			// release, is aged by when it was written instead.
+			since = info.ModTime()
+		}
+		if age := time.Since(since); age < staleLockAge {
+			if readErr != nil {
+				return nil, fmt.Errorf("%w on simulation %s (started %s ago)", ErrLocked, shortID(containerID), age.Round(time.Second))
+			}
+			return nil, fmt.Errorf("%w on simulation %s (pid %d, started %s ago)",
+				ErrLocked, shortID(containerID), pid, age.Round(time.Second))
+		}
+
+		removed, err := removeStaleLock(path, info, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove stale lock: %w", err)
+		}
+		if !removed {
+			return nil, fmt.Errorf("%w on simulation %s", ErrLocked, shortID(containerID))
+		}
+	}
+
+	return nil, fmt.Errorf("%w on simulation %s", ErrLocked, shortID(containerID))
+}
+
+// removeStaleLock removes the lock at path if it is still the file that was
+// judged stale, as described by info and data. Another invocation may have
+// replaced it with a fresh lock in the meantime, so the file is renamed
+// aside first and checked: a different file is put back and false returned.
+func removeStaleLock(path string, info os.FileInfo, data []byte) (bool, error) {
+	aside := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, aside); err != nil {
+		if os.IsNotExist(err) {
+			// Removed by someone else; the next attempt can take it.
+			return true, nil
+		}
+		return false, err
+	}
+
+	asideInfo, err := os.Stat(aside)
+	if err != nil {
+		return false, err
+	}
+	asideData, _ := os.ReadFile(aside)
+	if os.SameFile(info, asideInfo) && info.ModTime().Equal(asideInfo.ModTime()) && bytes.Equal(data, asideData) {
+		return true, os.Remove(aside)
+	}
+
+	// A live lock: put it back unless yet another one has taken its place.
+	err = os.Link(aside, path)
+	os.Remove(aside)
+	if err != nil && !os.IsExist(err) {
+		return false, err
+	}
+	return false, nil
+}
+
+// writeLockFile writes this process's lock contents to a new file in dir and
+// returns its path.
+func writeLockFile(dir string) (string, error) {
+	f, err := os.CreateTemp(dir, ".lock-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create lock file: %w", err)
+	}
+	_, err = fmt.Fprintf(f, "%d\n%s\n", os.Getpid(), time.Now().Format(time.RFC3339))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+func (l *Lock) Release() error {
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
+func readLock(path string) (int, time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return parseLock(data)
+}
+
+func parseLock(data []byte) (int, time.Time, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 2 {
+		return 0, time.Time{}, fmt.Errorf("malformed lock file")
+	}
+
+	pid, err := strconv.Atoi(lines[0])
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	since, err := time.Parse(time.RFC3339, lines[1])
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return pid, since, nil
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,157 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "autobox-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	lock, err := AcquireLock("abc123def456")
+	if err != nil {
+		t.Fatalf("AcquireLock() error = %v", err)
+	}
+
+	if _, err := AcquireLock("abc123def456"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked for second acquire, got %v", err)
+	}
+
+	if _, err := AcquireLock("other123"); err != nil {
+		t.Errorf("Expected lock on a different simulation to succeed, got %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	relocked, err := AcquireLock("abc123def456")
+	if err != nil {
+		t.Fatalf("Expected acquire after release to succeed, got %v", err)
+	}
+	relocked.Release()
+}
+
+func TestAcquireLockStale(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "autobox-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", oldHome)
+
+	lockDir := filepath.Join(tmpDir, ".autobox", "state", "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		t.Fatalf("Failed to create lock dir: %v", err)
+	}
+
+	stale := fmt.Sprintf("12345\n%s\n", time.Now().Add(-time.Hour).Format(time.RFC3339))
+	if err := os.WriteFile(filepath.Join(lockDir, "abc123def456.lock"), []byte(stale), 0644); err != nil {
+		t.Fatalf("Failed to write stale lock: %v", err)
+	}
+
+	lock, err := AcquireLock("abc123def456")
+	if err != nil {
+		t.Fatalf("Expected stale lock to be replaced, got %v", err)
+	}
+	lock.Release()
+}
+
+// A lock that can't be parsed, for example one caught half-written, must
+// not be taken over while it is recent.
+func TestAcquireLockUnreadable(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	lockDir := filepath.Join(tmpDir, ".autobox", "state", "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		t.Fatalf("Failed to create lock dir: %v", err)
+	}
+	path := filepath.Join(lockDir, "abc123def456.lock")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("Failed to write empty lock: %v", err)
+	}
+
+	if _, err := AcquireLock("abc123def456"); !errors.Is(err, ErrLocked) {
+		t.Errorf("AcquireLock() with a recent empty lock = %v, want ErrLocked", err)
+	}
+
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := AcquireLock("abc123def456")
+	if err != nil {
+		t.Fatalf("AcquireLock() with an old empty lock error = %v, want it replaced", err)
+	}
+	if _, since, err := readLock(path); err != nil || time.Since(since) > time.Minute {
+		t.Errorf("lock file = %v, %v; want this process's lock", since, err)
+	}
+	lock.Release()
+
+	entries, err := os.ReadDir(lockDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("lock directory has %d files after release, want the temporary file removed too", len(entries))
+	}
+}
+
+// Two invocations can judge the same lock stale. If one of them has already
+// replaced it with its own fresh lock, the other must leave that one alone.
+func TestRemoveStaleLockKeepsReplacement(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "abc123def456.lock")
+
+	stale := []byte(fmt.Sprintf("12345\n%s\n", time.Now().Add(-time.Hour).Format(time.RFC3339)))
+	if err := os.WriteFile(path, stale, 0644); err != nil {
+		t.Fatalf("Failed to write stale lock: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := writeLockFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(fresh, path); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := removeStaleLock(path, info, stale)
+	if err != nil || removed {
+		t.Fatalf("removeStaleLock() over a fresh lock = %v, %v; want it kept", removed, err)
+	}
+	if pid, _, err := readLock(path); err != nil || pid != os.Getpid() {
+		t.Errorf("lock file pid = %d, %v; want the fresh lock back in place", pid, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("lock directory holds %d files, want only the lock", len(entries))
+	}
+}
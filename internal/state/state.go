@@ -0,0 +1,23 @@
+package state
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Autobox-AI/autobox-cli/internal/config"
+)
+
+// Dir returns the directory where the CLI keeps runtime state such as
+// operation locks, creating it if necessary.
+func Dir() (string, error) {
+	paths, err := config.Paths()
+	if err != nil {
+		return "", err
+	}
+
+	dir := paths.State
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create state directory: %w", err)
+	}
+	return dir, nil
+}
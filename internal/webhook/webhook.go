@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultTimeout = 5 * time.Second
+	DefaultRetries = 3
+
+	defaultBackoff = 500 * time.Millisecond
+)
+
+// EventHeader carries the event name, so receivers can tell a launch from a
+// terminate without the body changing shape.
+const EventHeader = "X-Autobox-Event"
+
+// Notifier POSTs JSON payloads to a webhook URL.
+type Notifier struct {
+	URL     string
+	Retries int
+	Backoff time.Duration
+	client  *http.Client
+}
+
+// New returns a Notifier for url. Each attempt is bounded by timeout, and
+// failed attempts are retried up to retries times with exponential backoff.
+func New(url string, timeout time.Duration, retries int) *Notifier {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if retries < 0 {
+		retries = 0
+	}
+	return &Notifier{
+		URL:     url,
+		Retries: retries,
+		Backoff: defaultBackoff,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Notify POSTs payload as JSON. Network errors, 429 and 5xx responses are
+// retried; any other non-2xx response is returned immediately, since the
+// receiver has rejected the payload itself.
+func (n *Notifier) Notify(ctx context.Context, event string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	delay := n.Backoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := n.post(ctx, event, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= n.Retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, event string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook returned %s", resp.Status)
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNotify(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.Header.Get(EventHeader) != "launch" {
+			t.Errorf("%s: got %q, want launch", EventHeader, r.Header.Get(EventHeader))
+		}
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		if payload["id"] != "abc123" {
+			t.Errorf("payload id: got %q, want abc123", payload["id"])
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := New(server.URL, time.Second, 2)
+	n.Backoff = time.Millisecond
+
+	if err := n.Notify(context.Background(), "launch", map[string]string{"id": "abc123"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestNotifyRejected(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := New(server.URL, time.Second, 3)
+	n.Backoff = time.Millisecond
+
+	if err := n.Notify(context.Background(), "launch", struct{}{}); err == nil {
+		t.Fatalf("expected an error for a 400 response")
+	}
+	if calls != 1 {
+		t.Errorf("expected a rejected payload not to be retried, got %d attempts", calls)
+	}
+}
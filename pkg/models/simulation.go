@@ -12,47 +12,67 @@ const (
 	StatusCompleted SimulationStatus = "completed"
 	StatusFailed    SimulationStatus = "failed"
 	StatusStopped   SimulationStatus = "stopped"
+	StatusPaused    SimulationStatus = "paused"
 )
 
 type Simulation struct {
-	ID          string           `json:"id"`
-	Name        string           `json:"name"`
-	ContainerID string           `json:"container_id"`
-	Status      SimulationStatus `json:"status"`
-	CreatedAt   time.Time        `json:"created_at"`
-	StartedAt   *time.Time       `json:"started_at,omitempty"`
-	FinishedAt  *time.Time       `json:"finished_at,omitempty"`
-	Config      SimulationConfig `json:"config"`
-	Metrics     *Metrics         `json:"metrics,omitempty"`
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	ContainerID   string           `json:"container_id"`
+	ContainerName string           `json:"container_name,omitempty"`
+	Status        SimulationStatus `json:"status"`
+	CreatedAt     time.Time        `json:"created_at"`
+	StartedAt     *time.Time       `json:"started_at,omitempty"`
+	FinishedAt    *time.Time       `json:"finished_at,omitempty"`
+	ExitCode      *int             `json:"exit_code,omitempty"`
+	OOMKilled     bool             `json:"oom_killed,omitempty"`
+	RestartCount  int              `json:"restart_count,omitempty"`
+	Config        SimulationConfig `json:"config"`
+	Command       []string         `json:"command,omitempty"`
+	Metrics       *Metrics         `json:"metrics,omitempty"`
 }
 
 type SimulationConfig struct {
-	Name        string            `json:"name"`
-	ConfigPath  string            `json:"config_path"`
-	MetricsPath string            `json:"metrics_path"`
-	ServerPath  string            `json:"server_path"`
-	Image       string            `json:"image"`
-	Environment map[string]string `json:"environment"`
-	Volumes     []string          `json:"volumes"`
+	Name          string                 `json:"name"`
+	ConfigPath    string                 `json:"config_path"`
+	MetricsPath   string                 `json:"metrics_path"`
+	ServerPath    string                 `json:"server_path"`
+	Image         string                 `json:"image"`
+	EngineVersion string                 `json:"engine_version,omitempty"`
+	ImageDigest   string                 `json:"image_digest,omitempty"`
+	Environment   map[string]string      `json:"environment"`
+	Volumes       []string               `json:"volumes"`
+	NetworkMode   string                 `json:"network_mode,omitempty"`
+	Entrypoint    string                 `json:"entrypoint,omitempty"`
+	EngineArgs    []string               `json:"engine_args,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Labels        map[string]string      `json:"labels,omitempty"`
+	Privileged    bool                   `json:"privileged,omitempty"`
+	CapAdd        []string               `json:"cap_add,omitempty"`
+	CapDrop       []string               `json:"cap_drop,omitempty"`
+	CPULimit      float64                `json:"cpu_limit,omitempty"`
+	MemoryLimit   string                 `json:"memory_limit,omitempty"`
+	RestartPolicy string                 `json:"restart_policy,omitempty"`
+	Webhook       string                 `json:"webhook,omitempty"`
 }
 
 type Metrics struct {
-	CPUUsage    float64           `json:"cpu_usage"`
-	MemoryUsage float64           `json:"memory_usage"`
-	NetworkIO   NetworkStats      `json:"network_io"`
-	DiskIO      DiskStats         `json:"disk_io"`
+	CPUUsage    float64                `json:"cpu_usage"`
+	MemoryUsage float64                `json:"memory_usage"`
+	NetworkIO   NetworkStats           `json:"network_io"`
+	DiskIO      DiskStats              `json:"disk_io"`
 	Custom      map[string]interface{} `json:"custom,omitempty"`
-	Timestamp   time.Time         `json:"timestamp"`
+	Timestamp   time.Time              `json:"timestamp"`
 }
 
 type NetworkStats struct {
-	BytesReceived    uint64 `json:"bytes_received"`
-	BytesTransmitted uint64 `json:"bytes_transmitted"`
-	PacketsReceived  uint64 `json:"packets_received"`
+	BytesReceived      uint64 `json:"bytes_received"`
+	BytesTransmitted   uint64 `json:"bytes_transmitted"`
+	PacketsReceived    uint64 `json:"packets_received"`
 	PacketsTransmitted uint64 `json:"packets_transmitted"`
 }
 
 type DiskStats struct {
 	BytesRead    uint64 `json:"bytes_read"`
 	BytesWritten uint64 `json:"bytes_written"`
-}
\ No newline at end of file
+}
@@ -16,6 +16,7 @@ func TestSimulationStatus(t *testing.T) {
 		{"Completed", StatusCompleted, "completed"},
 		{"Failed", StatusFailed, "failed"},
 		{"Stopped", StatusStopped, "stopped"},
+		{"Paused", StatusPaused, "paused"},
 	}
 
 	for _, tt := range tests {